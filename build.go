@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mhoertnagl/donkey/cgen/llvm"
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/loader"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+// build compiles the source files given in args and the modules they
+// import into a single LLVM module. Import paths are relative to the root
+// directory of the project. It returns the process exit code.
+//
+//	donkey build foo.dk [bar.dk ...] [-o foo.ll] [-root dir]
+func build(args []string) int {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	output := flags.String("o", "", "output file (defaults to the first input with extension .ll)")
+	jsonDiags := flags.Bool("json", false, "report diagnostics as JSON, one object per line")
+	utf16 := flags.Bool("utf16", false, "count columns in UTF-16 code units instead of runes")
+	root := flags.String("root", "", "root directory of imported modules (defaults to the directory of the first input)")
+
+	// Allow flags to appear before, between and after the input files.
+	files := []string{}
+	for len(args) > 0 {
+		if err := flags.Parse(args); err != nil {
+			return 2
+		}
+		args = flags.Args()
+		if len(args) > 0 {
+			files = append(files, args[0])
+			args = args[1:]
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "donkey build: no input files\n")
+		return 2
+	}
+
+	unit := token.Runes
+	if *utf16 {
+		unit = token.UTF16
+	}
+	fset := token.NewFileSetWithUnit(unit)
+
+	renderer := diag.NewRendererWithUnit(unit)
+	report := func(ds []*diag.Diagnostic) {
+		if *jsonDiags {
+			enc := json.NewEncoder(os.Stderr)
+			for _, d := range ds {
+				enc.Encode(d)
+			}
+			return
+		}
+		renderer.RenderAll(os.Stderr, ds)
+	}
+
+	if *root == "" {
+		*root = filepath.Dir(files[0])
+	}
+	ld := loader.NewLoader(*root, fset)
+	mods, err := ld.Load(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 1
+	}
+	for file, src := range ld.Sources() {
+		renderer.AddSource(file, src)
+	}
+	if ld.HasErrors() {
+		report(ld.Errors())
+		return 1
+	}
+
+	gen := llvm.NewLlvmCodegen()
+	module := gen.GenerateModules(mods)
+	if gen.HasErrors() {
+		report(gen.Errors())
+		return 1
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(files[0], filepath.Ext(files[0])) + ".ll"
+	}
+	if err := os.WriteFile(*output, []byte(module), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -1,9 +1,15 @@
 package cgen
 
 import (
+	"github.com/mhoertnagl/donkey/diag"
 	"github.com/mhoertnagl/donkey/parser"
 )
 
 type Codegen interface {
 	Generate(node *parser.Program) string
+	// GenerateModules generates the modules of a program. Every module has
+	// to follow the modules it imports.
+	GenerateModules(mods []*parser.Program) string
+	HasErrors() bool
+	Errors() []*diag.Diagnostic
 }
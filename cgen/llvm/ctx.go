@@ -1,7 +1,6 @@
 package llvm
 
 import (
-	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -17,14 +16,6 @@ func (sym *ValueSymbol) GetValue() value.Value {
 	return sym.value
 }
 
-type FuncSymbol struct {
-	fun *ir.Func
-}
-
-func (sym *FuncSymbol) GetValue() value.Value {
-	return sym.fun
-}
-
 type Scope map[string]Symbol
 
 type Context struct {
@@ -51,10 +42,6 @@ func (ctx *Context) SetValue(name string, value value.Value) {
 	ctx.setSymbol(name, &ValueSymbol{value})
 }
 
-func (ctx *Context) SetFunction(name string, fun *ir.Func) {
-	ctx.setSymbol(name, &FuncSymbol{fun})
-}
-
 func (ctx *Context) setSymbol(name string, symbol Symbol) {
 	ctx.scopes[len(ctx.scopes)-1][name] = symbol
 }
@@ -1,15 +1,40 @@
 package llvm
 
 import (
+	"strings"
+
 	"github.com/llir/llvm/ir"
 	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	dtypes "github.com/mhoertnagl/donkey/types"
 	"github.com/mhoertnagl/donkey/utils"
 )
 
+// collectFunctionDefinitions declares the functions of the module. The
+// names of the functions of imported modules are qualified by the import
+// path of the module: the function count of the module math/bits is named
+// math.bits.count. Extern and exported functions keep their name so that
+// they can be linked with C.
 func (c *LlvmCodegen) collectFunctionDefinitions(n *parser.Program) {
+	for _, s := range n.Statements {
+		if f, ok := s.(*parser.FunDefStatement); ok {
+			c.symbols[f] = symbol(n.Path, f.Name.Value)
+			if f.Extern || f.Export {
+				c.symbols[f] = f.Name.Value
+			}
+		}
+	}
 	stmts(c, n.Statements)
 }
 
+// symbol returns the name of the function in the module with the path.
+func symbol(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return strings.ReplaceAll(path, "/", ".") + "." + name
+}
+
 func stmts(c *LlvmCodegen, ns []parser.Statement) {
 	for _, s := range ns {
 		stmt(c, s)
@@ -23,13 +48,131 @@ func stmt(c *LlvmCodegen, n parser.Statement) {
 	}
 }
 
+// funDefStmt declares the function. Generic functions are declared once
+// for every instance when they are used. Extern functions are declared
+// without a body.
 func funDefStmt(c *LlvmCodegen, n *parser.FunDefStatement) {
-	name := n.Name.Value
-	params := utils.Map(n.Params, param)
-	fun := c.module.NewFunc(name, i64, params...)
-	c.ctx.SetFunction(n.Name.Value, fun)
+	if c.info.Schemes[n] != nil {
+		return
+	}
+	if n.Extern || n.Export {
+		c.funcs[n] = c.declareExternal(n)
+		return
+	}
+	// Functions of the main module may clash with the extern and exported
+	// functions of imported modules and with the functions of the runtime
+	// that are declared on first use.
+	switch name := c.symbols[n]; {
+	case isRuntime(name):
+		c.diags.TokenErrorf("C0005", n.Name.Token, "Symbol [%s] is reserved for the runtime.", name)
+	case c.isDeclared(name):
+		c.diags.TokenErrorf("C0005", n.Name.Token, "Symbol [%s] is already defined.", name)
+	}
+	c.funcs[n] = c.declareFunc(c.symbols[n], n, c.info.Funcs[n])
+}
+
+// isRuntime returns true iff the symbol belongs to a function of the C
+// runtime, an LLVM intrinsic or a helper function of the runtime.
+func isRuntime(name string) bool {
+	_, ok := runtimeFuncs[name]
+	return ok || strings.HasPrefix(name, "llvm.") || strings.HasPrefix(name, "pow.")
+}
+
+func (c *LlvmCodegen) declareFunc(name string, n *parser.FunDefStatement, sig *dtypes.Func) *ir.Func {
+	fun := c.newFunc(name, n, sig)
+	c.module.Funcs = append(c.module.Funcs, fun)
+	return fun
+}
+
+// newFunc creates the function without adding it to the module.
+func (c *LlvmCodegen) newFunc(name string, n *parser.FunDefStatement, sig *dtypes.Func) *ir.Func {
+	params := make([]*ir.Param, len(n.Params))
+	for i, p := range n.Params {
+		params[i] = ir.NewParam(p.Value, c.llvmType(sig.Params[i]))
+	}
+	fun := ir.NewFunc(name, c.llvmType(sig.Result), params...)
+	fun.Sig.Variadic = sig.Variadic
+	return fun
+}
+
+// declareExternal declares an extern or exported function with its name
+// as the symbol. The declarations of extern functions are shared with the
+// functions of the C runtime and extern functions of other modules if
+// their signatures are the same. Any other function with the same symbol
+// is an error.
+func (c *LlvmCodegen) declareExternal(n *parser.FunDefStatement) *ir.Func {
+	name := c.symbols[n]
+	fun := c.newFunc(name, n, c.info.Funcs[n])
+	if decl, ok := runtimeFuncs[name]; ok {
+		decl(c)
+	}
+	if prev, ok := c.runtime[name]; ok && n.Extern && prev.Sig.Equal(fun.Sig) {
+		return prev
+	}
+	if c.isDeclared(name) {
+		c.diags.TokenErrorf("C0005", n.Name.Token, "Symbol [%s] is already defined.", name)
+		return fun
+	}
+	c.module.Funcs = append(c.module.Funcs, fun)
+	if n.Extern {
+		c.runtime[name] = fun
+	}
+	return fun
+}
+
+// isDeclared returns true iff the module has a function with the name.
+func (c *LlvmCodegen) isDeclared(name string) bool {
+	for _, fun := range c.module.Funcs {
+		if fun.Name() == name {
+			return true
+		}
+	}
+	return false
 }
 
-func param(p *parser.Identifier) *ir.Param {
-	return ir.NewParam(p.Value, i64)
+// instance is an instance of a generic function. The type parameters of
+// the function are replaced by the types of the substitution.
+type instance struct {
+	fun   *ir.Func
+	def   *parser.FunDefStatement
+	subst map[*dtypes.Var]dtypes.Type
+}
+
+// generic returns the instance of the generic function the identifier
+// refers to and nil if it does not refer to a generic function. The
+// instance is declared when it is used for the first time and its body is
+// generated after the program.
+func (c *LlvmCodegen) generic(id *parser.Identifier) *ir.Func {
+	obj := c.names.Uses[id]
+	if obj == nil || obj.Kind != resolve.Func {
+		return nil
+	}
+	scheme := c.info.Schemes[obj.Fun]
+	if scheme == nil {
+		return nil
+	}
+	// Uses of a generic function within its own group of mutually
+	// recursive functions are not instantiated. They refer to the instance
+	// with the types of the current instance.
+	args, ok := c.info.Instances[id]
+	if !ok {
+		args = utils.Map(scheme.Vars, func(v *dtypes.Var) dtypes.Type { return v })
+	}
+	args = utils.Map(args, c.concrete)
+	key := strings.Join(utils.Map(args, dtypes.Type.String), ".")
+	if fun, ok := c.instances[obj.Fun][key]; ok {
+		return fun
+	}
+	subst := make(map[*dtypes.Var]dtypes.Type)
+	for i, v := range scheme.Vars {
+		subst[v] = args[i]
+	}
+	name := c.symbols[obj.Fun] + "." + key
+	fun := c.declareFunc(name, obj.Fun, dtypes.Subst(scheme.Type, subst).(*dtypes.Func))
+	if c.instances[obj.Fun] == nil {
+		c.instances[obj.Fun] = make(map[string]*ir.Func)
+	}
+	c.instances[obj.Fun][key] = fun
+	c.pending = append(c.pending, &instance{fun: fun, def: obj.Fun, subst: subst})
+	return fun
 }
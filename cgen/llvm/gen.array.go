@@ -0,0 +1,75 @@
+package llvm
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+	dtypes "github.com/mhoertnagl/donkey/types"
+)
+
+// Arrays are pairs of a length and a pointer to the elements on the heap:
+//
+//	{ i64, i64* }
+//
+// Copies of an array share the same elements. Every access is checked
+// against the length of the array. An index out of range prints the
+// position of the index expression and traps.
+
+// arrayType returns the type of arrays of the element type.
+func (c *LlvmCodegen) arrayType(t *dtypes.Array) *types.StructType {
+	return types.NewStruct(i64, types.NewPointer(c.llvmType(t.Elem)))
+}
+
+func (c *LlvmCodegen) arrayLit(n *parser.ArrayLiteral) value.Value {
+	typ := c.arrayType(c.typeOf(n).(*dtypes.Array))
+	ptrTyp := typ.Fields[1].(*types.PointerType)
+	length := constant.NewInt(i64, int64(len(n.Elements)))
+	var data value.Value = constant.NewNull(ptrTyp)
+	if len(n.Elements) > 0 {
+		size := constant.NewMul(length, sizeOf(ptrTyp.ElemType))
+		data = c.block.NewBitCast(c.block.NewCall(c.malloc(), size), ptrTyp)
+	}
+	for i, elem := range n.Elements {
+		val := c.expr(elem)
+		ptr := c.block.NewGetElementPtr(ptrTyp.ElemType, data, constant.NewInt(i64, int64(i)))
+		c.block.NewStore(val, ptr)
+	}
+	arr := c.block.NewInsertValue(constant.NewUndef(typ), length, 0)
+	return c.block.NewInsertValue(arr, data, 1)
+}
+
+func (c *LlvmCodegen) indexExpr(n *parser.IndexExpression) value.Value {
+	ptr := c.elementPtr(n)
+	return c.block.NewLoad(c.llvmType(c.typeOf(n)), ptr)
+}
+
+// elementPtr returns a pointer to the element the index expression refers
+// to after checking that the index is within the bounds of the array.
+func (c *LlvmCodegen) elementPtr(n *parser.IndexExpression) value.Value {
+	arr := c.expr(n.Left)
+	index := c.expr(n.Index)
+	length := c.block.NewExtractValue(arr, 0)
+
+	// Negative indices are greater than any length when compared as
+	// unsigned integers.
+	inRange := c.block.NewICmp(enum.IPredULT, index, length)
+	ok_block := c.newBlock("index.ok")
+	fail_block := c.newBlock("index.fail")
+	c.block.NewCondBr(inRange, ok_block, fail_block)
+
+	// Flush the output of print before the program is aborted.
+	c.setCurrentBlock(fail_block)
+	c.block.NewCall(c.fflush(), constant.NewNull(i8ptr))
+	format := c.globalString("%s: Index [%lld] is out of range for length [%lld].\n")
+	pos := c.globalString(n.Pos().String())
+	c.block.NewCall(c.dprintf(), constant.NewInt(types.I32, 2), format, pos, index, length)
+	c.block.NewCall(c.trap())
+	c.block.NewUnreachable()
+
+	c.setCurrentBlock(ok_block)
+	data := c.block.NewExtractValue(arr, 1)
+	elemTyp := data.Type().(*types.PointerType).ElemType
+	return c.block.NewGetElementPtr(elemTyp, data, index)
+}
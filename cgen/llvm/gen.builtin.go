@@ -0,0 +1,415 @@
+package llvm
+
+import (
+	"math/big"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	"github.com/mhoertnagl/donkey/token"
+	dtypes "github.com/mhoertnagl/donkey/types"
+)
+
+// builtin returns the builtin function the callee refers to or nil if the
+// callee is not a builtin function.
+func (c *LlvmCodegen) builtin(n parser.Expression) *resolve.Object {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Builtin {
+		return obj
+	}
+	return nil
+}
+
+// builtinCall generates a call of a builtin function.
+func (c *LlvmCodegen) builtinCall(obj *resolve.Object, n *parser.CallExpression) value.Value {
+	if obj.Name == "len" {
+		return c.lenCall(n)
+	}
+	return c.printCall(obj, n)
+}
+
+// printCall generates a call of the builtin functions print and println.
+// Both are implemented with printf of the C runtime and return the number
+// of bytes written. Integers are printed as 64 bit values. Smaller integers
+// are extended. Floating-point numbers are printed as doubles with %g.
+func (c *LlvmCodegen) printCall(obj *resolve.Object, n *parser.CallExpression) value.Value {
+	arg := n.Args[0]
+	val := c.expr(arg)
+	format := "%s"
+	newline := ""
+	if obj.Name == "println" {
+		newline = "\n"
+	}
+	switch t := c.typeOf(arg); {
+	case dtypes.IsInteger(t) && val.Type().Equal(types.I128):
+		return c.printInt128(val, dtypes.IsUnsigned(t), newline)
+	case dtypes.IsInteger(t):
+		format = "%lld"
+		if dtypes.IsUnsigned(t) {
+			format = "%llu"
+		}
+		val = c.intCast(val, i64, dtypes.IsUnsigned(t))
+	case dtypes.IsFloat(t):
+		format = "%g"
+		val = c.floatCast(val, types.Double)
+	case t == dtypes.Bool:
+		val = c.block.NewSelect(val, c.globalString("true"), c.globalString("false"))
+	}
+	res := c.block.NewCall(c.printf(), c.globalString(format+newline), val)
+	return c.block.NewSExt(res, i64)
+}
+
+// printInt128 prints a 128 bit integer. printf cannot print 128 bit
+// values, so the magnitude is split into parts of at most 19 decimal
+// digits a * 10^38 + b * 10^19 + c that are printed with a single format.
+// Leading parts that are zero are omitted.
+func (c *LlvmCodegen) printInt128(val value.Value, unsigned bool, newline string) value.Value {
+	zero := constant.NewInt(types.I128, 0)
+	sign := value.Value(c.globalString(""))
+	if !unsigned {
+		neg := c.block.NewICmp(enum.IPredSLT, val, zero)
+		sign = c.block.NewSelect(neg, c.globalString("-"), sign)
+		val = c.block.NewSelect(neg, c.block.NewSub(zero, val), val)
+	}
+	e19 := &constant.Int{Typ: types.I128, X: new(big.Int).SetUint64(10_000_000_000_000_000_000)}
+	hi := c.block.NewUDiv(val, e19)
+	a := c.block.NewTrunc(c.block.NewUDiv(hi, e19), i64)
+	b := c.block.NewTrunc(c.block.NewURem(hi, e19), i64)
+	lo := c.block.NewTrunc(c.block.NewURem(val, e19), i64)
+	hasA := c.block.NewICmp(enum.IPredNE, a, constant.NewInt(i64, 0))
+	hasB := c.block.NewICmp(enum.IPredNE, b, constant.NewInt(i64, 0))
+	first := c.block.NewSelect(hasA, a, c.block.NewSelect(hasB, b, lo))
+	second := c.block.NewSelect(hasA, b, lo)
+	format := c.block.NewSelect(hasA, c.globalString("%s%llu%019llu%019llu"+newline),
+		c.block.NewSelect(hasB, c.globalString("%s%llu%019llu"+newline), c.globalString("%s%llu"+newline)))
+	res := c.block.NewCall(c.printf(), format, sign, first, second, lo)
+	return c.block.NewSExt(res, i64)
+}
+
+// conversion returns the type the callee refers to or nil if the callee is
+// not the name of a type.
+func (c *LlvmCodegen) conversion(n parser.Expression) dtypes.Type {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Type {
+		return dtypes.Predeclared[obj.Name]
+	}
+	return nil
+}
+
+// convert generates the conversion of a number to another numeric type.
+// Integers are truncated if the target type is smaller. Otherwise they are
+// sign extended if their type is signed and zero extended if it is
+// unsigned. Floating-point numbers are rounded to the precision of the
+// target type and truncated toward zero if it is an integer type.
+func (c *LlvmCodegen) convert(n *parser.CallExpression) value.Value {
+	t := c.typeOf(n.Args[0])
+	val := c.expr(n.Args[0])
+	to := c.typeOf(n)
+	switch typ := c.llvmType(to).(type) {
+	case *types.IntType:
+		switch {
+		case dtypes.IsFloat(t) && dtypes.IsUnsigned(to):
+			return c.block.NewFPToUI(val, typ)
+		case dtypes.IsFloat(t):
+			return c.block.NewFPToSI(val, typ)
+		}
+		return c.intCast(val, typ, dtypes.IsUnsigned(t))
+	case *types.FloatType:
+		switch {
+		case dtypes.IsUnsigned(t):
+			return c.block.NewUIToFP(val, typ)
+		case dtypes.IsInteger(t):
+			return c.block.NewSIToFP(val, typ)
+		}
+		return c.floatCast(val, typ)
+	}
+	return val
+}
+
+// floatCast converts the floating-point value to the floating-point type.
+func (c *LlvmCodegen) floatCast(v value.Value, typ *types.FloatType) value.Value {
+	switch {
+	case v.Type().Equal(typ):
+		return v
+	case typ == types.Double:
+		return c.block.NewFPExt(v, typ)
+	}
+	return c.block.NewFPTrunc(v, typ)
+}
+
+// intCast converts the integer value to the integer type. Values of an
+// unsigned type are zero extended.
+func (c *LlvmCodegen) intCast(v value.Value, typ *types.IntType, unsigned bool) value.Value {
+	from := v.Type().(*types.IntType).BitSize
+	switch {
+	case from > typ.BitSize:
+		return c.block.NewTrunc(v, typ)
+	case from < typ.BitSize && unsigned:
+		return c.block.NewZExt(v, typ)
+	case from < typ.BitSize:
+		return c.block.NewSExt(v, typ)
+	}
+	return v
+}
+
+// varargs generates the additional arguments of a call of a variadic
+// extern function. They are promoted like in C: booleans and integers
+// smaller than 32 bits are extended to i32 and f32 values to doubles.
+func (c *LlvmCodegen) varargs(ns []parser.Expression) []value.Value {
+	args := make([]value.Value, len(ns))
+	for i, n := range ns {
+		args[i] = c.expr(n)
+		switch t := c.typeOf(n); {
+		case t == dtypes.Bool:
+			args[i] = c.block.NewZExt(args[i], types.I32)
+		case dtypes.IsInteger(t):
+			if typ := args[i].Type().(*types.IntType); typ.BitSize < 32 {
+				args[i] = c.intCast(args[i], types.I32, dtypes.IsUnsigned(t))
+			}
+		case dtypes.IsFloat(t):
+			args[i] = c.floatCast(args[i], types.Double)
+		}
+	}
+	return args
+}
+
+// lenCall generates a call of the builtin function len. The length of a
+// string is determined with strlen of the C runtime.
+func (c *LlvmCodegen) lenCall(n *parser.CallExpression) value.Value {
+	arg := c.expr(n.Args[0])
+	if c.typeOf(n.Args[0]) == dtypes.String {
+		return c.block.NewCall(c.strlen(), arg)
+	}
+	return c.block.NewExtractValue(arg, 0)
+}
+
+// runtimeFuncs declares the functions of the C runtime that the builtin
+// functions use by name.
+var runtimeFuncs = map[string]func(*LlvmCodegen) *ir.Func{
+	"printf":  (*LlvmCodegen).printf,
+	"dprintf": (*LlvmCodegen).dprintf,
+	"fflush":  (*LlvmCodegen).fflush,
+	"malloc":  (*LlvmCodegen).malloc,
+	"strlen":  (*LlvmCodegen).strlen,
+}
+
+// printf returns the declaration of the printf function of the C runtime.
+func (c *LlvmCodegen) printf() *ir.Func {
+	fun := c.declare("printf", types.I32, ir.NewParam("format", i8ptr))
+	fun.Sig.Variadic = true
+	return fun
+}
+
+// dprintf returns the declaration of the dprintf function of the C
+// runtime. It prints to a file descriptor.
+func (c *LlvmCodegen) dprintf() *ir.Func {
+	fun := c.declare("dprintf", types.I32, ir.NewParam("fd", types.I32), ir.NewParam("format", i8ptr))
+	fun.Sig.Variadic = true
+	return fun
+}
+
+// fflush returns the declaration of the fflush function of the C runtime.
+// The stream is passed as an opaque pointer.
+func (c *LlvmCodegen) fflush() *ir.Func {
+	return c.declare("fflush", types.I32, ir.NewParam("stream", i8ptr))
+}
+
+// malloc returns the declaration of the malloc function of the C runtime.
+func (c *LlvmCodegen) malloc() *ir.Func {
+	return c.declare("malloc", i8ptr, ir.NewParam("size", i64))
+}
+
+// strlen returns the declaration of the strlen function of the C runtime.
+func (c *LlvmCodegen) strlen() *ir.Func {
+	return c.declare("strlen", i64, ir.NewParam("s", i8ptr))
+}
+
+// trap returns the declaration of the llvm.trap intrinsic that aborts the
+// program.
+func (c *LlvmCodegen) trap() *ir.Func {
+	return c.declare("llvm.trap", types.Void)
+}
+
+// fpow returns the declaration of the llvm.pow intrinsic for the
+// floating-point type.
+func (c *LlvmCodegen) fpow(typ *types.FloatType) *ir.Func {
+	name := "llvm.pow.f64"
+	if typ == types.Float {
+		name = "llvm.pow.f32"
+	}
+	return c.declare(name, typ, ir.NewParam("b", typ), ir.NewParam("e", typ))
+}
+
+// idiv returns the function that computes the quotient or the remainder
+// a / b or a % b of the integer type t. There is one function per operator
+// and type. It is defined on first use. Division by zero prints an error
+// and aborts the program like the interpreter does. The smallest signed
+// value divided by -1 wraps around to itself with a remainder of 0.
+func (c *LlvmCodegen) idiv(op token.TokenType, t dtypes.Type) *ir.Func {
+	typ := c.llvmType(t).(*types.IntType)
+	unsigned := dtypes.IsUnsigned(t)
+	name := "div."
+	if op == token.MOD {
+		name = "rem."
+	}
+	if unsigned {
+		name += "u" + typ.String()[1:]
+	} else {
+		name += typ.String()
+	}
+	if fun, ok := c.runtime[name]; ok {
+		return fun
+	}
+	a := ir.NewParam("a", typ)
+	b := ir.NewParam("b", typ)
+	fun := c.module.NewFunc(name, typ, a, b)
+	c.runtime[name] = fun
+
+	entry_block := fun.NewBlock(fun.Name() + ".entry")
+	var check_block, neg_block *ir.Block
+	if !unsigned {
+		check_block = fun.NewBlock("check")
+		neg_block = fun.NewBlock("neg")
+	}
+	div_block := fun.NewBlock("div")
+	fail_block := fun.NewBlock("fail")
+
+	zero := constant.NewInt(typ, 0)
+	isZero := entry_block.NewICmp(enum.IPredEQ, b, zero)
+
+	var res value.Value
+	switch {
+	case unsigned && op == token.DIV:
+		res = div_block.NewUDiv(a, b)
+	case unsigned:
+		res = div_block.NewURem(a, b)
+	case op == token.DIV:
+		res = div_block.NewSDiv(a, b)
+	default:
+		res = div_block.NewSRem(a, b)
+	}
+	div_block.NewRet(res)
+
+	if unsigned {
+		entry_block.NewCondBr(isZero, fail_block, div_block)
+	} else {
+		// Division by -1 is a negation which does not overflow.
+		entry_block.NewCondBr(isZero, fail_block, check_block)
+		isMinusOne := check_block.NewICmp(enum.IPredEQ, b, constant.NewInt(typ, -1))
+		check_block.NewCondBr(isMinusOne, neg_block, div_block)
+		if op == token.DIV {
+			neg_block.NewRet(neg_block.NewSub(zero, a))
+		} else {
+			neg_block.NewRet(zero)
+		}
+	}
+
+	// Flush the output of print before the program is aborted.
+	fail_block.NewCall(c.fflush(), constant.NewNull(i8ptr))
+	format := c.globalString("Division by zero.\n")
+	fail_block.NewCall(c.dprintf(), constant.NewInt(types.I32, 2), format)
+	fail_block.NewCall(c.trap())
+	fail_block.NewUnreachable()
+	return fun
+}
+
+// ipow returns the function that computes the integer power b ** e of the
+// integer type t by repeated squaring. There is one function per type. It
+// is defined on first use. A negative exponent prints an error and aborts
+// the program like the interpreter does.
+func (c *LlvmCodegen) ipow(t dtypes.Type) *ir.Func {
+	typ := c.llvmType(t).(*types.IntType)
+	unsigned := dtypes.IsUnsigned(t)
+	name := "pow." + typ.String()
+	if unsigned {
+		name = "pow.u" + typ.String()[1:]
+	}
+	if fun, ok := c.runtime[name]; ok {
+		return fun
+	}
+	b := ir.NewParam("b", typ)
+	e := ir.NewParam("e", typ)
+	fun := c.module.NewFunc(name, typ, b, e)
+	c.runtime[name] = fun
+
+	entry_block := fun.NewBlock(fun.Name() + ".entry")
+	loop_block := fun.NewBlock("loop")
+	body_block := fun.NewBlock("body")
+	exit_block := fun.NewBlock("exit")
+
+	one := constant.NewInt(typ, 1)
+	zero := constant.NewInt(typ, 0)
+
+	if unsigned {
+		entry_block.NewBr(loop_block)
+	} else {
+		fail_block := fun.NewBlock("fail")
+		neg := entry_block.NewICmp(enum.IPredSLT, e, zero)
+		entry_block.NewCondBr(neg, fail_block, loop_block)
+
+		var exp64 value.Value = e
+		switch {
+		case typ.BitSize < 64:
+			exp64 = fail_block.NewSExt(e, i64)
+		case typ.BitSize > 64:
+			exp64 = fail_block.NewTrunc(e, i64)
+		}
+		// Flush the output of print before the program is aborted.
+		fail_block.NewCall(c.fflush(), constant.NewNull(i8ptr))
+		format := c.globalString("Negative exponent [%lld].\n")
+		fail_block.NewCall(c.dprintf(), constant.NewInt(types.I32, 2), format, exp64)
+		fail_block.NewCall(c.trap())
+		fail_block.NewUnreachable()
+	}
+
+	res := loop_block.NewPhi(ir.NewIncoming(one, entry_block))
+	base := loop_block.NewPhi(ir.NewIncoming(b, entry_block))
+	exp := loop_block.NewPhi(ir.NewIncoming(e, entry_block))
+	var more value.Value
+	if unsigned {
+		more = loop_block.NewICmp(enum.IPredUGT, exp, zero)
+	} else {
+		more = loop_block.NewICmp(enum.IPredSGT, exp, zero)
+	}
+	loop_block.NewCondBr(more, body_block, exit_block)
+
+	odd := body_block.NewTrunc(exp, i1)
+	mul := body_block.NewMul(res, base)
+	res_next := body_block.NewSelect(odd, mul, res)
+	base_next := body_block.NewMul(base, base)
+	var exp_next value.Value
+	if unsigned {
+		exp_next = body_block.NewLShr(exp, one)
+	} else {
+		exp_next = body_block.NewAShr(exp, one)
+	}
+	body_block.NewBr(loop_block)
+
+	res.Incs = append(res.Incs, ir.NewIncoming(res_next, body_block))
+	base.Incs = append(base.Incs, ir.NewIncoming(base_next, body_block))
+	exp.Incs = append(exp.Incs, ir.NewIncoming(exp_next, body_block))
+
+	exit_block.NewRet(res)
+	return fun
+}
+
+// declare returns the declaration of an external function. The function
+// is declared on first use.
+func (c *LlvmCodegen) declare(name string, ret types.Type, params ...*ir.Param) *ir.Func {
+	fun, ok := c.runtime[name]
+	if !ok {
+		fun = c.module.NewFunc(name, ret, params...)
+		c.runtime[name] = fun
+	}
+	return fun
+}
@@ -0,0 +1,167 @@
+package llvm
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	dtypes "github.com/mhoertnagl/donkey/types"
+)
+
+var i32 = types.I32
+var nullI8ptr = constant.NewNull(i8ptr)
+
+// Function values are closures. A closure is a pair of a pointer to the
+// code of the function and a pointer to its environment:
+//
+//	{ i64 (i8*, i64)*, i8* }
+//
+// The code takes the environment as an additional first argument. The
+// environment holds pointers to the variables the function captures.
+// Captured variables are allocated on the heap so that closures may
+// outlive the function that created them and see each other's updates.
+// The environment of functions that do not capture any variables is null.
+
+// closureType returns the type of closures of the signature.
+func (c *LlvmCodegen) closureType(sig *dtypes.Func) *types.StructType {
+	return types.NewStruct(types.NewPointer(c.codeType(sig)), i8ptr)
+}
+
+// codeType returns the type of the code of closures of the signature.
+func (c *LlvmCodegen) codeType(sig *dtypes.Func) *types.FuncType {
+	params := []types.Type{i8ptr}
+	for _, p := range sig.Params {
+		params = append(params, c.llvmType(p))
+	}
+	return types.NewFunc(c.llvmType(sig.Result), params...)
+}
+
+// funLit lifts the function literal into a function of the module and
+// returns a closure that pairs it with the captured variables.
+func (c *LlvmCodegen) funLit(n *parser.FunctionLiteral) value.Value {
+	sig := c.typeOf(n).(*dtypes.Func)
+	captures := c.names.Captures[n]
+	params := []*ir.Param{ir.NewParam("env", i8ptr)}
+	for i, p := range n.Params {
+		params = append(params, ir.NewParam(p.Value, c.llvmType(sig.Params[i])))
+	}
+	code := c.module.NewFunc(c.funcName(c.fun.Name()+".fn"), c.llvmType(sig.Result), params...)
+
+	// Generate the lifted function and continue with the current function
+	// afterwards.
+	fun, block, entry, allocas := c.fun, c.block, c.entry, c.allocas
+	blockNames, loops := c.blockNames, c.loops
+	if !c.function(code, captures, n.Params, n.Body) {
+		c.diags.TokenErrorf("C0004", n.Token, "Missing return statement in anonymous function.")
+	}
+	c.fun, c.block, c.entry, c.allocas = fun, block, entry, allocas
+	c.blockNames, c.loops = blockNames, loops
+
+	return c.closure(code, c.newEnv(captures))
+}
+
+// closure pairs the code with the environment.
+func (c *LlvmCodegen) closure(code *ir.Func, env value.Value) value.Value {
+	typ := types.NewStruct(code.Type(), i8ptr)
+	clo := c.block.NewInsertValue(constant.NewUndef(typ), code, 0)
+	return c.block.NewInsertValue(clo, env, 1)
+}
+
+// envType returns the type of the environment that holds pointers to the
+// captured variables.
+func (c *LlvmCodegen) envType(captures []*resolve.Object) *types.StructType {
+	fields := make([]types.Type, len(captures))
+	for i, obj := range captures {
+		fields[i] = types.NewPointer(c.llvmType(c.defType(obj.Decl)))
+	}
+	return types.NewStruct(fields...)
+}
+
+// newEnv allocates the environment and stores the storage locations of
+// the captured variables in it.
+func (c *LlvmCodegen) newEnv(captures []*resolve.Object) value.Value {
+	if len(captures) == 0 {
+		return nullI8ptr
+	}
+	typ := c.envType(captures)
+	mem := c.block.NewCall(c.malloc(), sizeOf(typ))
+	env := c.block.NewBitCast(mem, types.NewPointer(typ))
+	for i, obj := range captures {
+		ptr := c.ctx.Get(obj.Name).GetValue()
+		field := c.block.NewGetElementPtr(typ, env, constant.NewInt(i32, 0), constant.NewInt(i32, int64(i)))
+		c.block.NewStore(ptr, field)
+	}
+	return mem
+}
+
+// loadEnv binds the captured variables to the storage locations held by
+// the environment.
+func (c *LlvmCodegen) loadEnv(mem value.Value, captures []*resolve.Object) {
+	typ := c.envType(captures)
+	env := c.block.NewBitCast(mem, types.NewPointer(typ))
+	for i, obj := range captures {
+		field := c.block.NewGetElementPtr(typ, env, constant.NewInt(i32, 0), constant.NewInt(i32, int64(i)))
+		ptr := c.block.NewLoad(typ.Fields[i], field)
+		c.ctx.SetValue(obj.Name, ptr)
+	}
+}
+
+// callClosure calls the code of the closure with its environment.
+func (c *LlvmCodegen) callClosure(clo value.Value, args []value.Value) value.Value {
+	code := c.block.NewExtractValue(clo, 0)
+	env := c.block.NewExtractValue(clo, 1)
+	return c.block.NewCall(code, append([]value.Value{env}, args...)...)
+}
+
+// funcValue returns a closure for the function definition. Its code is a
+// wrapper that ignores the environment and calls the function.
+func (c *LlvmCodegen) funcValue(fun *ir.Func) value.Value {
+	code, ok := c.wrappers[fun]
+	if !ok {
+		params := []*ir.Param{ir.NewParam("env", i8ptr)}
+		args := make([]value.Value, len(fun.Params))
+		for i, p := range fun.Params {
+			param := ir.NewParam(p.Name(), p.Typ)
+			params = append(params, param)
+			args[i] = param
+		}
+		code = c.module.NewFunc(fun.Name()+".closure", fun.Sig.RetType, params...)
+		entry := code.NewBlock(code.Name() + ".entry")
+		res := entry.NewCall(fun, args...)
+		if types.IsVoid(fun.Sig.RetType) {
+			entry.NewRet(nil)
+		} else {
+			entry.NewRet(res)
+		}
+		c.wrappers[fun] = code
+	}
+	return c.closure(code, nullI8ptr)
+}
+
+// storage allocates the storage location of a variable or parameter.
+// Variables captured by function literals live on the heap.
+func (c *LlvmCodegen) storage(id *parser.Identifier, typ types.Type) value.Value {
+	if obj := c.names.Defs[id]; obj != nil && obj.Captured {
+		mem := c.block.NewCall(c.malloc(), sizeOf(typ))
+		return c.block.NewBitCast(mem, types.NewPointer(typ))
+	}
+	return c.alloca(typ)
+}
+
+// sizeOf returns the size of the type in bytes as a constant expression.
+func sizeOf(typ types.Type) constant.Constant {
+	ptr := constant.NewGetElementPtr(typ, constant.NewNull(types.NewPointer(typ)), constant.NewInt(i32, 1))
+	return constant.NewPtrToInt(ptr, i64)
+}
+
+// funcName returns a unique name for a lifted function by appending a
+// sequence number to the name.
+func (c *LlvmCodegen) funcName(name string) string {
+	n := c.funcNames[name]
+	c.funcNames[name]++
+	return fmt.Sprintf("%s.%d", name, n)
+}
@@ -0,0 +1,146 @@
+package llvm
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	dtypes "github.com/mhoertnagl/donkey/types"
+)
+
+// Enums are named LLVM struct types. The first member is the tag, the
+// index of the variant. It is followed by the fields of all variants in
+// the order of the definition:
+//
+//	enum Shape { Circle(r), Rect(w, h) }  =>  %Shape = type { i64, i64, i64, i64 }
+//
+// Only the fields of the variant given by the tag are defined. Enums are
+// passed by value like structs. A match extracts the tag and branches to
+// the arm of the variant with a switch.
+
+// enumType returns the named type of the enum. The type is added to the
+// module on first use. It is registered before its fields are translated
+// so that fields may refer to the enum through arrays.
+func (c *LlvmCodegen) enumType(t *dtypes.Enum) *types.StructType {
+	if typ, ok := c.enums[t]; ok {
+		return typ
+	}
+	typ := types.NewStruct(i64)
+	typ.SetName(c.typeName(t.Name))
+	c.enums[t] = typ
+	for _, v := range t.Variants {
+		for _, f := range v.Fields {
+			typ.Fields = append(typ.Fields, c.llvmType(f))
+		}
+	}
+	c.module.TypeDefs = append(c.module.TypeDefs, typ)
+	return typ
+}
+
+// fieldIndex returns the index of the first field of the variant in the
+// enum type.
+func fieldIndex(t *dtypes.Enum, variant int) uint64 {
+	index := uint64(1)
+	for _, v := range t.Variants[:variant] {
+		index += uint64(len(v.Fields))
+	}
+	return index
+}
+
+// variant returns the variant the identifier refers to or nil if it does
+// not refer to a variant.
+func (c *LlvmCodegen) variant(n parser.Expression) *resolve.Object {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Variant {
+		return obj
+	}
+	return nil
+}
+
+// variantValue returns the value of a variant without fields. It is a
+// constant that only defines the tag.
+func (c *LlvmCodegen) variantValue(n *parser.Identifier) value.Value {
+	t := c.typeOf(n).(*dtypes.Enum)
+	typ := c.enumType(t)
+	fields := []constant.Constant{constant.NewInt(i64, int64(t.VariantIndex(n.Value)))}
+	for _, f := range typ.Fields[1:] {
+		fields = append(fields, constant.NewUndef(f))
+	}
+	return constant.NewStruct(typ, fields...)
+}
+
+// variantCall constructs a value of a variant with fields.
+func (c *LlvmCodegen) variantCall(n *parser.CallExpression) value.Value {
+	t := c.typeOf(n).(*dtypes.Enum)
+	variant := t.VariantIndex(c.names.Name(n.Function).Value)
+	tag := constant.NewInt(i64, int64(variant))
+	var res value.Value = c.block.NewInsertValue(constant.NewUndef(c.enumType(t)), tag, 0)
+	index := fieldIndex(t, variant)
+	for i, arg := range n.Args {
+		res = c.block.NewInsertValue(res, c.expr(arg), index+uint64(i))
+	}
+	return res
+}
+
+// match branches on the tag of the value to the arms of its variants. The
+// wildcard arm is the default of the switch. Without a wildcard arm the
+// default is unreachable since the arms cover every variant. The result
+// is selected with a phi node in the end block.
+func (c *LlvmCodegen) match(n *parser.MatchExpression) value.Value {
+	t := c.typeOf(n.Value).(*dtypes.Enum)
+	val := c.expr(n.Value)
+	tag := c.block.NewExtractValue(val, 0)
+	switch_block := c.getCurrentBlock()
+	end_block := c.newDetachedBlock("match.end")
+
+	var default_block *ir.Block
+	arm_blocks := make([]*ir.Block, len(n.Arms))
+	cases := []*ir.Case{}
+	for i, arm := range n.Arms {
+		arm_blocks[i] = c.newDetachedBlock("match.arm")
+		if arm.IsWildcard() {
+			default_block = arm_blocks[i]
+			continue
+		}
+		variant := int64(t.VariantIndex(arm.Pattern.Value))
+		cases = append(cases, ir.NewCase(constant.NewInt(i64, variant), arm_blocks[i]))
+	}
+	if default_block == nil {
+		default_block = c.newDetachedBlock("match.default")
+		default_block.NewUnreachable()
+	}
+	switch_block.NewSwitch(tag, default_block, cases...)
+
+	incomings := []*ir.Incoming{}
+	for i, arm := range n.Arms {
+		c.attachBlock(arm_blocks[i])
+		c.setCurrentBlock(arm_blocks[i])
+		c.ctx.PushScope()
+		if !arm.IsWildcard() {
+			index := fieldIndex(t, t.VariantIndex(arm.Pattern.Value))
+			for j, id := range arm.Bindings {
+				field := c.block.NewExtractValue(val, index+uint64(j))
+				ptr := c.storage(id, c.llvmType(c.defType(id)))
+				c.block.NewStore(field, ptr)
+				c.ctx.SetValue(id.Value, ptr)
+			}
+		}
+		body := c.expr(arm.Body)
+		c.ctx.PopScope()
+		// The body may have changed the current block.
+		incomings = append(incomings, ir.NewIncoming(body, c.getCurrentBlock()))
+		c.block.NewBr(end_block)
+	}
+	if default_block.Parent == nil {
+		c.attachBlock(default_block)
+	}
+
+	c.attachBlock(end_block)
+	c.setCurrentBlock(end_block)
+	return end_block.NewPhi(incomings...)
+}
@@ -1,11 +1,17 @@
 package llvm
 
 import (
+	"math/big"
+
+	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
 	"github.com/mhoertnagl/donkey/token"
+	dtypes "github.com/mhoertnagl/donkey/types"
 	"github.com/mhoertnagl/donkey/utils"
 )
 
@@ -15,10 +21,26 @@ func (c *LlvmCodegen) expr(n parser.Expression) value.Value {
 		return c.boolLit(n)
 	case *parser.Integer:
 		return c.intLit(n)
+	case *parser.Float:
+		return c.floatLit(n)
+	case *parser.String:
+		return c.stringLit(n)
 	case *parser.Identifier:
 		return c.identifier(n)
 	case *parser.CallExpression:
 		return c.callExpr(n)
+	case *parser.FunctionLiteral:
+		return c.funLit(n)
+	case *parser.ArrayLiteral:
+		return c.arrayLit(n)
+	case *parser.IndexExpression:
+		return c.indexExpr(n)
+	case *parser.StructLiteral:
+		return c.structLit(n)
+	case *parser.SelectorExpression:
+		return c.selector(n)
+	case *parser.MatchExpression:
+		return c.match(n)
 	case *parser.BinaryExpression:
 		return c.binaryExpr(n)
 	case *parser.PrefixExpression:
@@ -31,39 +53,114 @@ func (c *LlvmCodegen) boolLit(n *parser.Boolean) value.Value {
 	return constant.NewBool(n.Value)
 }
 
+// intLit emits the literal with the bits of its value in the type. Values
+// above the largest signed value of the type are emitted negative.
 func (c *LlvmCodegen) intLit(n *parser.Integer) value.Value {
-	return constant.NewInt(i64, n.Value)
+	typ := c.llvmType(c.typeOf(n)).(*types.IntType)
+	if n.Value.IsInt64() {
+		return constant.NewInt(typ, n.Value.Int64())
+	}
+	x := new(big.Int).Set(n.Value)
+	if x.Bit(int(typ.BitSize-1)) == 1 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(typ.BitSize)))
+	}
+	return &constant.Int{Typ: typ, X: x}
+}
+
+func (c *LlvmCodegen) floatLit(n *parser.Float) value.Value {
+	return constant.NewFloat(c.llvmType(c.typeOf(n)).(*types.FloatType), n.Value)
 }
 
 func (c *LlvmCodegen) identifier(n *parser.Identifier) value.Value {
-	sym := c.ctx.Get((n.Value))
-	switch sym := sym.(type) {
-	case *ValueSymbol:
-		return c.block.NewLoad(i64, sym.GetValue())
-	case *FuncSymbol:
-		return sym.GetValue()
+	if c.variant(n) != nil {
+		return c.variantValue(n)
 	}
-	return nil
+	if fun := c.funcDef(n); fun != nil {
+		return c.funcValue(fun)
+	}
+	if sym, ok := c.ctx.Get(n.Value).(*ValueSymbol); ok {
+		return c.block.NewLoad(c.llvmType(c.typeOf(n)), sym.GetValue())
+	}
+	c.diags.TokenErrorf("C0001", n.Token, "Undefined identifier [%s].", n.Value)
+	return undefI64
 }
 
 func (c *LlvmCodegen) callExpr(n *parser.CallExpression) value.Value {
-	name := c.expr(n.Function)
+	if obj := c.builtin(n.Function); obj != nil {
+		return c.builtinCall(obj, n)
+	}
+	if c.variant(n.Function) != nil {
+		return c.variantCall(n)
+	}
+	if c.conversion(n.Function) != nil {
+		return c.convert(n)
+	}
+	// Function definitions are called directly.
+	if fun := c.funcDef(n.Function); fun != nil {
+		if fun.Sig.Variadic {
+			fixed := len(fun.Params)
+			args := append(utils.Map(n.Args[:fixed], c.expr), c.varargs(n.Args[fixed:])...)
+			return c.block.NewCall(fun, args...)
+		}
+		args := utils.Map(n.Args, c.expr)
+		return c.block.NewCall(fun, args...)
+	}
+	if _, ok := c.typeOf(n.Function).(*dtypes.Func); !ok {
+		c.diags.TokenErrorf("C0002", n.Token, "[%s] is not a function.", n.Function)
+		return undefI64
+	}
+	clo := c.expr(n.Function)
 	args := utils.Map(n.Args, c.expr)
-	return c.block.NewCall(name, args...)
+	return c.callClosure(clo, args)
+}
+
+// funcDef returns the function the callee refers to if it is the name of
+// a function definition and nil otherwise.
+func (c *LlvmCodegen) funcDef(n parser.Expression) *ir.Func {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if fun := c.generic(id); fun != nil {
+		return fun
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Func {
+		return c.funcs[obj.Fun]
+	}
+	return nil
 }
 
 func (c *LlvmCodegen) binaryExpr(n *parser.BinaryExpression) value.Value {
+	switch n.Operator {
+	case token.CONJ, token.DISJ:
+		return c.logicalExpr(n)
+	}
+
 	l := c.expr(n.Left)
 	r := c.expr(n.Right)
-	switch n.Operator {
+	return c.binaryOp(n.Operator, c.typeOf(n.Left), l, r)
+}
+
+// binaryOp applies the operator to the operands of type t. Division,
+// remainder, >>> and the comparisons are unsigned for operands of an
+// unsigned integer type. The logical operators && and || are generated by
+// logicalExpr instead.
+func (c *LlvmCodegen) binaryOp(op token.TokenType, t dtypes.Type, l, r value.Value) value.Value {
+	if dtypes.IsFloat(t) {
+		return c.floatOp(op, l, r)
+	}
+	unsigned := dtypes.IsUnsigned(t)
+	switch op {
 	case token.PLUS:
 		return c.block.NewAdd(l, r)
 	case token.MINUS:
 		return c.block.NewSub(l, r)
 	case token.TIMES:
 		return c.block.NewMul(l, r)
-	case token.DIV:
-		return c.block.NewSDiv(l, r)
+	case token.DIV, token.MOD:
+		return c.block.NewCall(c.idiv(op, t), l, r)
+	case token.POW:
+		return c.block.NewCall(c.ipow(t), l, r)
 
 	case token.AND:
 		return c.block.NewAnd(l, r)
@@ -71,27 +168,30 @@ func (c *LlvmCodegen) binaryExpr(n *parser.BinaryExpression) value.Value {
 		return c.block.NewOr(l, r)
 	case token.XOR:
 		return c.block.NewXor(l, r)
-
-	// TODO: Short circuiting?
-	case token.CONJ:
-		return c.block.NewAnd(l, r)
-	case token.DISJ:
-		return c.block.NewOr(l, r)
+	case token.NAND:
+		return c.block.NewXor(minusOne(l), c.block.NewAnd(l, r))
+	case token.NOR:
+		return c.block.NewXor(minusOne(l), c.block.NewOr(l, r))
+	case token.XNOR:
+		return c.block.NewXor(minusOne(l), c.block.NewXor(l, r))
 
 	case token.SLL:
 		return c.block.NewShl(l, r)
 	case token.SRL:
 		return c.block.NewLShr(l, r)
 	case token.SRA:
+		if unsigned {
+			return c.block.NewLShr(l, r)
+		}
 		return c.block.NewAShr(l, r)
 	case token.ROL:
 		x := c.block.NewShl(l, r)
-		d := c.block.NewSub(wordSizeI64, r)
+		d := c.block.NewSub(bitSize(l), r)
 		y := c.block.NewLShr(l, d)
 		return c.block.NewOr(x, y)
 	case token.ROR:
 		x := c.block.NewLShr(l, r)
-		d := c.block.NewSub(wordSizeI64, r)
+		d := c.block.NewSub(bitSize(l), r)
 		y := c.block.NewShl(l, d)
 		return c.block.NewOr(x, y)
 
@@ -100,24 +200,131 @@ func (c *LlvmCodegen) binaryExpr(n *parser.BinaryExpression) value.Value {
 	case token.NEQ:
 		return c.block.NewICmp(enum.IPredNE, l, r)
 	case token.LT:
+		if unsigned {
+			return c.block.NewICmp(enum.IPredULT, l, r)
+		}
 		return c.block.NewICmp(enum.IPredSLT, l, r)
 	case token.LE:
+		if unsigned {
+			return c.block.NewICmp(enum.IPredULE, l, r)
+		}
 		return c.block.NewICmp(enum.IPredSLE, l, r)
 	case token.GT:
+		if unsigned {
+			return c.block.NewICmp(enum.IPredUGT, l, r)
+		}
 		return c.block.NewICmp(enum.IPredSGT, l, r)
 	case token.GE:
+		if unsigned {
+			return c.block.NewICmp(enum.IPredUGE, l, r)
+		}
 		return c.block.NewICmp(enum.IPredSGE, l, r)
 	}
 	return nil
 }
 
+// floatOp applies the operator to floating-point operands. The comparisons
+// are ordered except for != so that every comparison with NaN is false
+// except for !=.
+func (c *LlvmCodegen) floatOp(op token.TokenType, l, r value.Value) value.Value {
+	switch op {
+	case token.PLUS:
+		return c.block.NewFAdd(l, r)
+	case token.MINUS:
+		return c.block.NewFSub(l, r)
+	case token.TIMES:
+		return c.block.NewFMul(l, r)
+	case token.DIV:
+		return c.block.NewFDiv(l, r)
+	case token.MOD:
+		return c.block.NewFRem(l, r)
+	case token.POW:
+		return c.block.NewCall(c.fpow(l.Type().(*types.FloatType)), l, r)
+
+	case token.EQU:
+		return c.block.NewFCmp(enum.FPredOEQ, l, r)
+	case token.NEQ:
+		return c.block.NewFCmp(enum.FPredUNE, l, r)
+	case token.LT:
+		return c.block.NewFCmp(enum.FPredOLT, l, r)
+	case token.LE:
+		return c.block.NewFCmp(enum.FPredOLE, l, r)
+	case token.GT:
+		return c.block.NewFCmp(enum.FPredOGT, l, r)
+	case token.GE:
+		return c.block.NewFCmp(enum.FPredOGE, l, r)
+	}
+	return nil
+}
+
+// minusOne returns the integer constant -1 of the type of the value. All
+// its bits are set.
+func minusOne(v value.Value) constant.Constant {
+	return constant.NewInt(v.Type().(*types.IntType), -1)
+}
+
+// bitSize returns the number of bits of the integer type of the value as
+// a constant of that type.
+func bitSize(v value.Value) constant.Constant {
+	typ := v.Type().(*types.IntType)
+	return constant.NewInt(typ, int64(typ.BitSize))
+}
+
+// logicalExpr generates a short-circuiting && or || expression. The right
+// hand side is only evaluated if the left hand side does not determine the
+// result already. Consider the following expression:
+//
+//	b != 0 && a / b > 1
+//
+// The left hand side is evaluated in the current block. If it is false, the
+// code branches directly to the merge block. Otherwise it continues with
+// the right hand side in a separate block. The merge block selects the
+// result with a phi node depending on the predecessor block.
+func (c *LlvmCodegen) logicalExpr(n *parser.BinaryExpression) value.Value {
+	prefix := "and"
+	// The result if the right hand side is skipped.
+	short := _false
+	if n.Operator == token.DISJ {
+		prefix = "or"
+		short = _true
+	}
+
+	l := c.expr(n.Left)
+	// The left hand side may have changed the current block if it contains
+	// a nested && or || expression.
+	lhs_block := c.getCurrentBlock()
+	rhs_block := c.newBlock(prefix + ".rhs")
+	merge_block := c.newDetachedBlock(prefix + ".merge")
+	if n.Operator == token.CONJ {
+		c.block.NewCondBr(l, rhs_block, merge_block)
+	} else {
+		c.block.NewCondBr(l, merge_block, rhs_block)
+	}
+
+	c.setCurrentBlock(rhs_block)
+	r := c.expr(n.Right)
+	// The right hand side may have changed the current block as well.
+	rhs_block = c.getCurrentBlock()
+	rhs_block.NewBr(merge_block)
+
+	c.attachBlock(merge_block)
+	c.setCurrentBlock(merge_block)
+	return merge_block.NewPhi(
+		ir.NewIncoming(short, lhs_block),
+		ir.NewIncoming(r, rhs_block),
+	)
+}
+
 func (c *LlvmCodegen) prefixExpr(n *parser.PrefixExpression) value.Value {
 	v := c.expr(n.Value)
 	switch n.Operator {
 	case token.MINUS:
-		return c.block.NewSub(zeroI64, v)
+		if _, ok := v.Type().(*types.FloatType); ok {
+			return c.block.NewFNeg(v)
+		}
+		return c.block.NewSub(constant.NewInt(v.Type().(*types.IntType), 0), v)
 	case token.INV:
-		return c.block.NewXor(minusOneI64, v)
+		return c.block.NewXor(minusOne(v), v)
 	case token.NOT:
 		return c.block.NewXor(_true, v)
 	}
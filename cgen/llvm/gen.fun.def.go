@@ -4,43 +4,115 @@ import (
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/value"
 	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
 )
 
 func (c *LlvmCodegen) funDefStmt(n *parser.FunDefStatement) value.Value {
-	name := n.Name.Value
+	// The instances of generic functions are generated on demand.
+	if c.info.Schemes[n] != nil {
+		return nil
+	}
 	// Load the appropriate function declaration.
 	// Function declarations have been collected already in fun.decl.go.
-	sym := c.ctx.Get(n.Name.Value)
-	// TODO: map functions and identifiers separately
-	switch fun := sym.GetValue().(type) {
-	case *ir.Func:
-		c.fun = fun
-		// Create a new function scoped context. Arguments
-		// and local variables are only visible in the
-		// function body.
-		c.ctx.PushScope()
-
-		// Create the function entry block.
-		// TODO: Wrapper for ir.Func that also holds function AST and provides convenient methods.
-		//       c.fun.CreateEntryBlock()
-		c.setCurrentBlock(c.fun.NewBlock(name + ".entry"))
-
-		// Allocate and store function arguments.
-		for _, arg := range c.fun.Params {
-			// For each argument allocate space and store to
-			// it the argument value.
-			ptr := c.block.NewAlloca(i64)
-			c.block.NewStore(arg, ptr)
-			// Add the storage location of the argument to
-			// the function scoped context.
-			c.ctx.SetValue(arg.Name(), ptr)
+	fun, ok := c.funcs[n]
+	if !ok || n.Extern {
+		return nil
+	}
+	if !c.function(fun, nil, n.Params, n.Body) {
+		c.diags.TokenErrorf("C0004", n.Name.Token, "Missing return statement in function [%s].", n.Name.Value)
+	}
+	return c.fun
+}
+
+// instanceBodies generates the bodies of the instances of generic
+// functions. Instances may declare further instances. Missing return
+// statements are reported once per function.
+func (c *LlvmCodegen) instanceBodies() {
+	reported := map[*parser.FunDefStatement]bool{}
+	for len(c.pending) > 0 {
+		inst := c.pending[0]
+		c.pending = c.pending[1:]
+		c.subst = inst.subst
+		if !c.function(inst.fun, nil, inst.def.Params, inst.def.Body) && !reported[inst.def] {
+			c.diags.TokenErrorf("C0004", inst.def.Name.Token, "Missing return statement in function [%s].", inst.def.Name.Value)
+			reported[inst.def] = true
 		}
+	}
+	c.subst = nil
+}
+
+// function generates the body of a function definition or a function
+// literal. The variables captured by a function literal are passed in an
+// environment as the first argument. It returns false if the end of the
+// body is reachable, i.e. a return statement is missing.
+func (c *LlvmCodegen) function(fun *ir.Func, captures []*resolve.Object, params []*parser.Identifier, body *parser.BlockStatement) bool {
+	c.fun = fun
+	c.blockNames = make(map[string]int)
+	c.loops = nil
+	// Create a new function scoped context. Arguments
+	// and local variables are only visible in the
+	// function body.
+	c.ctx.PushScope()
 
-		// Compile the function body.
-		c.blockStmt(n.Body)
+	// Create the function entry block.
+	// TODO: Wrapper for ir.Func that also holds function AST and provides convenient methods.
+	//       c.fun.CreateEntryBlock()
+	c.entry = c.fun.NewBlock(fun.Name() + ".entry")
+	c.allocas = 0
+	c.setCurrentBlock(c.entry)
 
-		// Pop the function scoped context.
-		c.ctx.PopScope()
+	// Bind the captured variables to their storage locations in the
+	// environment.
+	if len(captures) > 0 {
+		c.loadEnv(fun.Params[0], captures)
 	}
-	return c.fun
+
+	// Allocate and store function arguments.
+	args := fun.Params[len(fun.Params)-len(params):]
+	for i, arg := range args {
+		// For each argument allocate space and store to
+		// it the argument value.
+		ptr := c.storage(params[i], arg.Typ)
+		c.block.NewStore(arg, ptr)
+		// Add the storage location of the argument to
+		// the function scoped context.
+		c.ctx.SetValue(params[i].Value, ptr)
+	}
+
+	// Compile the function body.
+	c.blockStmt(body)
+
+	// Complete the last block of the function. It has no
+	// terminator if the function body does not end with a
+	// return statement or if it is the unreachable exit block
+	// of an infinite loop.
+	complete := true
+	if c.block.Term == nil {
+		complete = !c.isReachable(c.block)
+		c.block.NewUnreachable()
+	}
+
+	// Pop the function scoped context.
+	c.ctx.PopScope()
+	c.setCurrentBlock(nil)
+	return complete
+}
+
+// isReachable returns true iff the block is the entry block of the current
+// function or the successor of another block.
+func (c *LlvmCodegen) isReachable(block *ir.Block) bool {
+	if block == c.fun.Blocks[0] {
+		return true
+	}
+	for _, b := range c.fun.Blocks {
+		if b.Term == nil {
+			continue
+		}
+		for _, succ := range b.Term.Succs() {
+			if succ == block {
+				return true
+			}
+		}
+	}
+	return false
 }
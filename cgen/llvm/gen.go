@@ -1,24 +1,27 @@
 package llvm
 
 import (
+	"fmt"
+
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 	"github.com/mhoertnagl/donkey/cgen"
+	"github.com/mhoertnagl/donkey/diag"
 	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	dtypes "github.com/mhoertnagl/donkey/types"
 )
 
-// TODO: Scopes for if
-// TODO: for loop
-// TODO: Assignment
-
 var i1 = types.I1
 var i64 = types.I64
 
 var zeroI64 = constant.NewInt(i64, 0)
-var minusOneI64 = constant.NewInt(i64, -1)
-var wordSizeI64 = constant.NewInt(i64, 64)
+
+// undefI64 stands in for values that could not be generated because of
+// an error.
+var undefI64 = constant.NewUndef(i64)
 
 var _false = constant.NewInt(i1, 0)
 var _true = constant.NewInt(i1, 1)
@@ -28,24 +31,122 @@ type LlvmCodegen struct {
 	module *ir.Module
 	fun    *ir.Func
 	block  *ir.Block
+	diags  *diag.List
+	names  *resolve.Info
+	info   *dtypes.Info
+	// Global string constants by value and the functions of the runtime
+	// and extern functions by name.
+	strings map[string]constant.Constant
+	runtime map[string]*ir.Func
+	// Closure wrappers of function definitions and the number of lifted
+	// function literals per name.
+	wrappers  map[*ir.Func]*ir.Func
+	funcNames map[string]int
+	// Named types of structs and enums and the number of named types per
+	// name.
+	structs   map[*dtypes.Struct]*types.StructType
+	enums     map[*dtypes.Enum]*types.StructType
+	typeNames map[string]int
+	// Entry block of the current function and the number of alloca
+	// instructions at its beginning.
+	entry   *ir.Block
+	allocas int
+	// Number of blocks per name in the current function.
+	blockNames map[string]int
+	// Enclosing loops of the current statement.
+	loops []loop
+	// Instances of generic functions by their type arguments, the instances
+	// whose bodies have not been generated yet and the substitution of the
+	// type parameters of the current instance.
+	instances map[*parser.FunDefStatement]map[string]*ir.Func
+	pending   []*instance
+	subst     map[*dtypes.Var]dtypes.Type
+	// Function definitions and their names in the module.
+	funcs   map[*parser.FunDefStatement]*ir.Func
+	symbols map[*parser.FunDefStatement]string
 }
 
 func NewLlvmCodegen() cgen.Codegen {
 	return &LlvmCodegen{
-		ctx:    NewContext(),
-		module: ir.NewModule(),
+		ctx:       NewContext(),
+		module:    ir.NewModule(),
+		diags:     diag.NewList(),
+		strings:   make(map[string]constant.Constant),
+		runtime:   make(map[string]*ir.Func),
+		wrappers:  make(map[*ir.Func]*ir.Func),
+		funcNames: make(map[string]int),
+		structs:   make(map[*dtypes.Struct]*types.StructType),
+		enums:     make(map[*dtypes.Enum]*types.StructType),
+		typeNames: make(map[string]int),
+		instances: make(map[*parser.FunDefStatement]map[string]*ir.Func),
+		funcs:     make(map[*parser.FunDefStatement]*ir.Func),
+		symbols:   make(map[*parser.FunDefStatement]string),
 	}
 }
 
+func (c *LlvmCodegen) HasErrors() bool {
+	return c.diags.HasErrors()
+}
+
+func (c *LlvmCodegen) Errors() []*diag.Diagnostic {
+	return c.diags.Items()
+}
+
 func (c *LlvmCodegen) Generate(n *parser.Program) string {
-	c.collectFunctionDefinitions(n)
-	c.stmts(n.Statements)
+	return c.GenerateModules([]*parser.Program{n})
+}
+
+// GenerateModules links the modules of a program into a single LLVM
+// module. Every module has to follow the modules it imports.
+func (c *LlvmCodegen) GenerateModules(mods []*parser.Program) string {
+	c.names = resolve.NewResolver(c.diags).ResolveModules(mods)
+	if c.diags.HasErrors() {
+		return ""
+	}
+	// The names have been resolved. The modules are checked and generated
+	// as a single program.
+	prog := parser.NewProgram()
+	for _, mod := range mods {
+		prog.Statements = append(prog.Statements, mod.Statements...)
+	}
+	c.info = dtypes.NewChecker(c.diags).Check(prog, c.names)
+	if c.diags.HasErrors() {
+		return ""
+	}
+	c.topLevel(prog.Statements)
+	if c.diags.HasErrors() {
+		return ""
+	}
+	for _, mod := range mods {
+		c.collectFunctionDefinitions(mod)
+	}
+	c.stmts(prog.Statements)
+	c.instanceBodies()
 	return c.module.String()
 }
 
+// topLevel reports the statements of the program that are not
+// definitions. The compiled program starts at main, so other statements
+// are only allowed in function bodies.
+func (c *LlvmCodegen) topLevel(ns []parser.Statement) {
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.FunDefStatement, *parser.StructDefStatement, *parser.EnumDefStatement, *parser.ImportStatement:
+		default:
+			c.diags.NodeErrorf("C0006", n, "Only definitions are allowed outside of functions.")
+		}
+	}
+}
+
 func (c *LlvmCodegen) stmts(ns []parser.Statement) value.Value {
 	var res value.Value = nil
 	for _, s := range ns {
+		// Statements after a return, break or continue statement are
+		// unreachable. Generating them would append instructions
+		// after the terminator of the current block.
+		if c.block != nil && c.block.Term != nil {
+			break
+		}
 		res = c.genStmt(s)
 	}
 	return res
@@ -55,12 +156,22 @@ func (c *LlvmCodegen) genStmt(n parser.Statement) value.Value {
 	switch n := n.(type) {
 	case *parser.LetStatement:
 		return c.letStmt(n)
+	case *parser.AssignStatement:
+		return c.assignStmt(n)
 	case *parser.FunDefStatement:
 		return c.funDefStmt(n)
 	case *parser.BlockStatement:
 		return c.blockStmt(n)
 	case *parser.IfStatement:
 		return c.ifStmt(n)
+	case *parser.WhileStatement:
+		return c.whileStmt(n)
+	case *parser.ForStatement:
+		return c.forStmt(n)
+	case *parser.BreakStatement:
+		return c.breakStmt(n)
+	case *parser.ContinueStatement:
+		return c.continueStmt(n)
 	case *parser.ReturnStatement:
 		return c.returnStmt(n)
 	case *parser.ExpressionStatement:
@@ -72,13 +183,57 @@ func (c *LlvmCodegen) genStmt(n parser.Statement) value.Value {
 func (c *LlvmCodegen) letStmt(n *parser.LetStatement) value.Value {
 	name := n.Name.Value
 	val := c.expr(n.Value)
-	ptr := c.block.NewAlloca(i64)
+	ptr := c.storage(n.Name, c.llvmType(c.defType(n.Name)))
 	c.block.NewStore(val, ptr)
 	c.ctx.SetValue(name, ptr)
 	return ptr
 }
 
+// assignStmt stores the value in the storage location of the target. The
+// value is generated before the target. Compound assignments load the
+// current value of the target and store the result of the operator.
+func (c *LlvmCodegen) assignStmt(n *parser.AssignStatement) value.Value {
+	val := c.expr(n.Value)
+	ptr := c.address(n.Target)
+	if ptr == nil {
+		return nil
+	}
+	if n.Operator != "" {
+		typ := c.typeOf(n.Target)
+		cur := c.block.NewLoad(c.llvmType(typ), ptr)
+		val = c.binaryOp(n.Operator, typ, cur, val)
+	}
+	c.block.NewStore(val, ptr)
+	return ptr
+}
+
+// address returns a pointer to the storage location of the target of an
+// assignment. Variables are stored in the location that has been allocated
+// by their let statement.
+func (c *LlvmCodegen) address(n parser.Expression) value.Value {
+	switch n := n.(type) {
+	case *parser.IndexExpression:
+		return c.elementPtr(n)
+	case *parser.SelectorExpression:
+		return c.fieldPtr(n)
+	}
+	id := n.(*parser.Identifier)
+	if sym, ok := c.ctx.Get(id.Value).(*ValueSymbol); ok {
+		return sym.GetValue()
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Func {
+		c.diags.TokenErrorf("C0003", id.Token, "Cannot assign to function [%s].", id.Value)
+		return nil
+	}
+	c.diags.TokenErrorf("C0001", id.Token, "Undefined identifier [%s].", id.Value)
+	return nil
+}
+
+// blockStmt generates the statements of the block in a new scope.
+// Variables declared in the block are not visible after the block.
 func (c *LlvmCodegen) blockStmt(n *parser.BlockStatement) value.Value {
+	c.ctx.PushScope()
+	defer c.ctx.PopScope()
 	return c.stmts(n.Statements)
 }
 
@@ -92,6 +247,99 @@ func (c *LlvmCodegen) exprStmt(n *parser.ExpressionStatement) value.Value {
 	return c.expr(n.Value)
 }
 
+// typeOf returns the type of the expression in the current instance.
+func (c *LlvmCodegen) typeOf(n parser.Expression) dtypes.Type {
+	return c.concrete(c.info.TypeOf(n))
+}
+
+// defType returns the type of the declared name in the current instance.
+func (c *LlvmCodegen) defType(id *parser.Identifier) dtypes.Type {
+	return c.concrete(c.info.Defs[id])
+}
+
+// concrete replaces the type parameters of the type by the types of the
+// current instance.
+func (c *LlvmCodegen) concrete(t dtypes.Type) dtypes.Type {
+	if c.subst == nil {
+		return t
+	}
+	return dtypes.Subst(t, c.subst)
+}
+
+// llvmType returns the LLVM type that represents values of the type.
+func (c *LlvmCodegen) llvmType(t dtypes.Type) types.Type {
+	switch t := t.(type) {
+	case *dtypes.Func:
+		return c.closureType(t)
+	case *dtypes.Array:
+		return c.arrayType(t)
+	case *dtypes.Struct:
+		return c.structType(t)
+	case *dtypes.Enum:
+		return c.enumType(t)
+	case *dtypes.Basic:
+		if dtypes.IsInteger(t) {
+			return types.NewInt(uint64(t.Bits()))
+		}
+		if t == dtypes.F32 {
+			return types.Float
+		}
+		if t == dtypes.F64 {
+			return types.Double
+		}
+	}
+	switch t {
+	case dtypes.Bool:
+		return i1
+	case dtypes.String:
+		return i8ptr
+	case dtypes.Void:
+		return types.Void
+	}
+	return i64
+}
+
+// alloca allocates stack space for a value of the given type. All alloca
+// instructions are placed at the beginning of the function's entry block.
+// Allocations inside of branches and loops would otherwise grow the stack
+// each time they are executed.
+func (c *LlvmCodegen) alloca(typ types.Type) *ir.InstAlloca {
+	ptr := ir.NewAlloca(typ)
+	insts := c.entry.Insts
+	insts = append(insts, nil)
+	copy(insts[c.allocas+1:], insts[c.allocas:])
+	insts[c.allocas] = ptr
+	c.entry.Insts = insts
+	c.allocas++
+	return ptr
+}
+
+// newBlock appends a new block to the current function. Block names are
+// made unique by appending a sequence number.
+func (c *LlvmCodegen) newBlock(name string) *ir.Block {
+	block := c.newDetachedBlock(name)
+	c.attachBlock(block)
+	return block
+}
+
+// newDetachedBlock creates a new block that does not belong to the current
+// function yet. It has to be attached with attachBlock. This allows blocks
+// to be referenced before their position in the function is known.
+func (c *LlvmCodegen) newDetachedBlock(name string) *ir.Block {
+	n := c.blockNames[name]
+	c.blockNames[name]++
+	if n > 0 {
+		name = fmt.Sprintf("%s.%d", name, n)
+	}
+	return ir.NewBlock(name)
+}
+
+// attachBlock appends a detached block to the current function.
+func (c *LlvmCodegen) attachBlock(block *ir.Block) {
+	block.Parent = c.fun
+	c.fun.Blocks = append(c.fun.Blocks, block)
+}
+
 func (c *LlvmCodegen) setCurrentBlock(block *ir.Block) {
 	c.block = block
 }
@@ -13,19 +13,18 @@ func (c *LlvmCodegen) ifStmt(n *parser.IfStatement) value.Value {
 }
 
 func (c *LlvmCodegen) ifWithAlt(n *parser.IfStatement) value.Value {
-	then_block := c.fun.NewBlock("if.then")
-	else_block := c.fun.NewBlock("if.else")
-
-	// Generate the condition and then a conditional branch.
+	// Generate the condition and then a conditional branch. The
+	// condition is generated first because it may add blocks itself
+	// if it contains && or || expressions.
 	cond := c.expr(n.Condition)
+	then_block := c.newBlock("if.then")
+	else_block := c.newBlock("if.else")
 	c.block.NewCondBr(cond, then_block, else_block)
 
 	// Set the current block to then_block then generate the
 	// consequence statements.
 	c.setCurrentBlock(then_block)
-	// TODO: push scope
 	c.genStmt(n.Consequence)
-	// TODO: pop scope
 	// Finally set the then_block to the current block. The
 	// current block may not be the same as then_block because
 	// stmts could have changed it because of a
@@ -35,9 +34,7 @@ func (c *LlvmCodegen) ifWithAlt(n *parser.IfStatement) value.Value {
 	// Set the current block to else_block then generate the
 	// alternative statements.
 	c.setCurrentBlock(else_block)
-	// TODO: push scope
 	c.genStmt(n.Alternative)
-	// TODO: pop scope
 	// Finally set the else_block to the current block. The
 	// current block may not be the same as else_block because
 	// stmts could have changed it because of a
@@ -61,7 +58,7 @@ func (c *LlvmCodegen) ifWithAlt(n *parser.IfStatement) value.Value {
 	// superfluous and ends without a terminator which results
 	// in a compilation error.
 	if then_block.Term == nil || else_block.Term == nil {
-		merge_block := c.fun.NewBlock("if.merge")
+		merge_block := c.newBlock("if.merge")
 		// If the then_block has no terminator, complete the block
 		// with an unconditional jump to the merge_block.
 		if then_block.Term == nil {
@@ -81,19 +78,18 @@ func (c *LlvmCodegen) ifWithAlt(n *parser.IfStatement) value.Value {
 }
 
 func (c *LlvmCodegen) ifWithoutAlt(n *parser.IfStatement) value.Value {
-	then_block := c.fun.NewBlock("if.then")
-	merge_block := c.fun.NewBlock("if.merge")
-
-	// Generate the condition and then a conditional branch.
+	// Generate the condition and then a conditional branch. The
+	// condition is generated first because it may add blocks itself
+	// if it contains && or || expressions.
 	cond := c.expr(n.Condition)
+	then_block := c.newBlock("if.then")
+	merge_block := c.newBlock("if.merge")
 	c.block.NewCondBr(cond, then_block, merge_block)
 
 	// Set the current block to then_block then generate the
 	// consequence statements.
 	c.setCurrentBlock(then_block)
-	// TODO: push scope
 	c.genStmt(n.Consequence)
-	// TODO: pop scope
 	// Finally set the then_block to the current block. The
 	// current block may not be the same as then_block because
 	// stmts could have changed it because of a
@@ -0,0 +1,142 @@
+package llvm
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+)
+
+// loop holds the jump targets of continue and break statements in the
+// body of the innermost loop.
+type loop struct {
+	continue_block *ir.Block
+	break_block    *ir.Block
+}
+
+func (c *LlvmCodegen) pushLoop(continue_block, break_block *ir.Block) {
+	c.loops = append(c.loops, loop{continue_block, break_block})
+}
+
+func (c *LlvmCodegen) popLoop() {
+	c.loops = c.loops[:len(c.loops)-1]
+}
+
+func (c *LlvmCodegen) currentLoop() loop {
+	return c.loops[len(c.loops)-1]
+}
+
+// whileStmt generates a while loop. Consider the following program:
+//
+//	while i < n {
+//	  i = i + 1;
+//	}
+//
+// The header block evaluates the condition and either branches to the
+// body or to the exit block. The body jumps back to the header.
+func (c *LlvmCodegen) whileStmt(n *parser.WhileStatement) value.Value {
+	header_block := c.newBlock("while.header")
+	c.block.NewBr(header_block)
+
+	c.setCurrentBlock(header_block)
+	cond := c.expr(n.Condition)
+	body_block := c.newBlock("while.body")
+	exit_block := c.newDetachedBlock("while.exit")
+	c.loopBr(cond, body_block, exit_block)
+
+	// A continue statement re-evaluates the condition.
+	c.genLoopBody(n.Body, body_block, header_block, exit_block)
+
+	// Continue with exit_block as the new current block.
+	c.attachBlock(exit_block)
+	c.setCurrentBlock(exit_block)
+	return nil
+}
+
+// forStmt generates a for loop. Consider the following program:
+//
+//	for let i = 0; i < n; i = i + 1 {
+//	  s = s + i;
+//	}
+//
+// The init statement is generated in the current block. The header block
+// evaluates the condition and either branches to the body or to the exit
+// block. The body jumps to the latch block which executes the post
+// statement and jumps back to the header.
+func (c *LlvmCodegen) forStmt(n *parser.ForStatement) value.Value {
+	// Variables declared in the init statement are only visible in the
+	// loop.
+	c.ctx.PushScope()
+	defer c.ctx.PopScope()
+
+	if n.Init != nil {
+		c.genStmt(n.Init)
+	}
+
+	header_block := c.newBlock("for.header")
+	c.block.NewBr(header_block)
+
+	c.setCurrentBlock(header_block)
+	body_block := c.newDetachedBlock("for.body")
+	latch_block := c.newDetachedBlock("for.latch")
+	exit_block := c.newDetachedBlock("for.exit")
+	if n.Condition != nil {
+		cond := c.expr(n.Condition)
+		c.loopBr(cond, body_block, exit_block)
+	} else {
+		c.block.NewBr(body_block)
+	}
+	c.attachBlock(body_block)
+
+	// A continue statement executes the post statement first.
+	c.genLoopBody(n.Body, body_block, latch_block, exit_block)
+
+	c.attachBlock(latch_block)
+	c.setCurrentBlock(latch_block)
+	if n.Post != nil {
+		c.genStmt(n.Post)
+	}
+	c.block.NewBr(header_block)
+
+	// Continue with exit_block as the new current block.
+	c.attachBlock(exit_block)
+	c.setCurrentBlock(exit_block)
+	return nil
+}
+
+// loopBr branches to body_block if the condition of a loop holds and to
+// exit_block otherwise. A loop with the constant condition true only
+// branches to body_block like a for loop without a condition. Its exit
+// block is unreachable unless the body contains a break statement.
+func (c *LlvmCodegen) loopBr(cond value.Value, body_block, exit_block *ir.Block) {
+	if cond == constant.True {
+		c.block.NewBr(body_block)
+	} else {
+		c.block.NewCondBr(cond, body_block, exit_block)
+	}
+}
+
+// genLoopBody generates the loop body in body_block. If the body does not
+// end with a terminator it jumps to continue_block.
+func (c *LlvmCodegen) genLoopBody(body *parser.BlockStatement, body_block, continue_block, break_block *ir.Block) {
+	c.setCurrentBlock(body_block)
+	c.pushLoop(continue_block, break_block)
+	c.blockStmt(body)
+	c.popLoop()
+	// The current block may not be the same as body_block because the
+	// body could have changed it because of a nested if statement for
+	// instance.
+	if c.block.Term == nil {
+		c.block.NewBr(continue_block)
+	}
+}
+
+func (c *LlvmCodegen) breakStmt(n *parser.BreakStatement) value.Value {
+	c.block.NewBr(c.currentLoop().break_block)
+	return nil
+}
+
+func (c *LlvmCodegen) continueStmt(n *parser.ContinueStatement) value.Value {
+	c.block.NewBr(c.currentLoop().continue_block)
+	return nil
+}
@@ -0,0 +1,35 @@
+package llvm
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+)
+
+var i8 = types.I8
+var i8ptr = types.NewPointer(i8)
+
+func (c *LlvmCodegen) stringLit(n *parser.String) value.Value {
+	return c.globalString(n.Value)
+}
+
+// globalString returns a pointer to the first character of a global
+// constant array that holds the null-terminated string. Equal strings
+// share the same array.
+func (c *LlvmCodegen) globalString(s string) constant.Constant {
+	if ptr, ok := c.strings[s]; ok {
+		return ptr
+	}
+	arr := constant.NewCharArrayFromString(s + "\x00")
+	def := c.module.NewGlobalDef(fmt.Sprintf(".str.%d", len(c.strings)), arr)
+	def.Linkage = enum.LinkagePrivate
+	def.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+	def.Immutable = true
+	ptr := constant.NewGetElementPtr(arr.Typ, def, zeroI64, zeroI64)
+	c.strings[s] = ptr
+	return ptr
+}
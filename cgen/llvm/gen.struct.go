@@ -0,0 +1,75 @@
+package llvm
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mhoertnagl/donkey/parser"
+	dtypes "github.com/mhoertnagl/donkey/types"
+)
+
+// Structs are named LLVM struct types with one member per field in the
+// order of the definition. They are passed by value. Fields are read with
+// extractvalue and written through a pointer into the storage of the
+// variable or array element that holds the struct.
+
+// structType returns the named type of the struct. The type is added to
+// the module on first use. It is registered before its fields are
+// translated so that fields may refer to the struct through arrays.
+func (c *LlvmCodegen) structType(t *dtypes.Struct) *types.StructType {
+	if typ, ok := c.structs[t]; ok {
+		return typ
+	}
+	typ := types.NewStruct()
+	typ.SetName(c.typeName(t.Name))
+	c.structs[t] = typ
+	for _, f := range t.Fields {
+		typ.Fields = append(typ.Fields, c.llvmType(f.Type))
+	}
+	c.module.TypeDefs = append(c.module.TypeDefs, typ)
+	return typ
+}
+
+// typeName returns a unique name for a struct type. Structs of different
+// blocks may have the same name.
+func (c *LlvmCodegen) typeName(name string) string {
+	n := c.typeNames[name]
+	c.typeNames[name]++
+	if n > 0 {
+		name = fmt.Sprintf("%s.%d", name, n)
+	}
+	return name
+}
+
+func (c *LlvmCodegen) structLit(n *parser.StructLiteral) value.Value {
+	t := c.typeOf(n).(*dtypes.Struct)
+	var res value.Value = constant.NewUndef(c.structType(t))
+	for i, id := range n.Fields {
+		val := c.expr(n.Values[i])
+		res = c.block.NewInsertValue(res, val, uint64(t.FieldIndex(id.Value)))
+	}
+	return res
+}
+
+func (c *LlvmCodegen) selector(n *parser.SelectorExpression) value.Value {
+	if id := c.names.Qualified(n); id != nil {
+		return c.identifier(id)
+	}
+	t := c.typeOf(n.Left).(*dtypes.Struct)
+	val := c.expr(n.Left)
+	return c.block.NewExtractValue(val, uint64(t.FieldIndex(n.Field.Value)))
+}
+
+// fieldPtr returns a pointer to the field of the struct the selector
+// expression refers to.
+func (c *LlvmCodegen) fieldPtr(n *parser.SelectorExpression) value.Value {
+	t := c.typeOf(n.Left).(*dtypes.Struct)
+	ptr := c.address(n.Left)
+	if ptr == nil {
+		return nil
+	}
+	index := constant.NewInt(i32, int64(t.FieldIndex(n.Field.Value)))
+	return c.block.NewGetElementPtr(c.structType(t), ptr, constant.NewInt(i32, 0), index)
+}
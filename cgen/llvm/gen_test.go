@@ -12,10 +12,6 @@ import (
 	"github.com/mhoertnagl/donkey/utils/fs"
 )
 
-// TODO: assignments
-// TODO: tests assignments
-// TODO: tests nested if
-
 func TestCodeGeneration(t *testing.T) {
 	files := fs.FindFilesWithExtension("tests", ".dk")
 	for _, actFile := range files {
@@ -30,6 +26,112 @@ func TestCodeGeneration(t *testing.T) {
 	}
 }
 
+func TestBlockScopes(t *testing.T) {
+	testErrors(t, "fn main() { if true { let a = 1; } return a; }", "1:43: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "fn main() { { let a = 1; } return a; }", "1:35: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "fn main() { for let i = 0; i < 1; i = i + 1 { } return i; }", "1:56: error[R0001]: Undefined identifier [i].")
+	testErrors(t, "fn main() { while true { let b = 1; break; } return b; }", "1:53: error[R0001]: Undefined identifier [b].")
+}
+
+func TestMissingReturn(t *testing.T) {
+	testErrors(t, "fn main() { let a = 1; }", "1:4: error[C0004]: Missing return statement in function [main].")
+	testErrors(t, "fn main() { let f = fn() { 1; }; return 0; }", "1:21: error[C0004]: Missing return statement in anonymous function.")
+	testErrors(t, "fn f(x) { if true { return x; } } fn main() { f(1); f(true); return 0; }", "1:4: error[C0004]: Missing return statement in function [f].")
+	testErrors(t, "fn f() { while true { return 1; } } fn main() { return f(); }")
+	testErrors(t, "fn f() { for ; true; { return 1; } } fn main() { return f(); }")
+	testErrors(t, "fn main() { while true { break; } }", "1:4: error[C0004]: Missing return statement in function [main].")
+	testErrors(t, "fn main() { let a = true; while a { return 1; } }", "1:4: error[C0004]: Missing return statement in function [main].")
+}
+
+func TestTopLevelStatements(t *testing.T) {
+	testErrors(t, "let g = 5; fn main() { return g; }", "1:1: error[C0006]: Only definitions are allowed outside of functions.")
+	testErrors(t, "println(1); fn main() { return 0; }", "1:1: error[C0006]: Only definitions are allowed outside of functions.")
+	testErrors(t, "struct P { x } enum E { A } fn main() { return 0; }")
+}
+
+func TestModules(t *testing.T) {
+	bits := parse(t, "math/bits", "pub fn count(x: int) { return x; } pub fn id(x) { return x; } fn helper() { return 1; }")
+	main := parse(t, "", `import "math/bits"; fn helper() { return 2; } fn main() { return bits.count(bits.id(1)) + helper(); }`)
+	gen := llvm.NewLlvmCodegen()
+	act := gen.GenerateModules([]*parser.Program{bits, main})
+	if gen.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", gen.Errors())
+	}
+	for _, exp := range []string{"define i64 @math.bits.count(i64 %x)", "define i64 @math.bits.helper()", "define i64 @helper()", "define i64 @math.bits.id.int(i64 %x)"} {
+		if !strings.Contains(act, exp) {
+			t.Errorf("Expected [%s] in [%s].", exp, act)
+		}
+	}
+}
+
+func TestQualifiedNames(t *testing.T) {
+	geo := parse(t, "geo", "pub struct P { x, y } pub enum Shape { Circle(r), Square(a) }")
+	main := parse(t, "", `import "geo";
+fn area(s: geo.Shape) { return match s { geo.Circle(r) => 3 * r * r, geo.Square(a) => a * a }; }
+fn main() { let p = geo.P { x: 1, y: 2 }; return area(geo.Square(p.y)) + p.x; }`)
+	gen := llvm.NewLlvmCodegen()
+	act := gen.GenerateModules([]*parser.Program{geo, main})
+	if gen.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", gen.Errors())
+	}
+	for _, exp := range []string{"%P = type { i64, i64 }", "%Shape = type { i64, i64, i64 }", "switch i64"} {
+		if !strings.Contains(act, exp) {
+			t.Errorf("Expected [%s] in [%s].", exp, act)
+		}
+	}
+}
+
+func TestRuntimeSymbols(t *testing.T) {
+	testErrors(t, "fn printf(x: int) -> int { return x; } fn main() { println(printf(1)); return 0; }", "1:4: error[C0005]: Symbol [printf] is reserved for the runtime.")
+	testErrors(t, "fn malloc(x: int) -> int { return x; } fn main() { let a = [1]; return malloc(0); }", "1:4: error[C0005]: Symbol [malloc] is reserved for the runtime.")
+	intrinsics := parse(t, "llvm", "pub fn trap() -> int { return 0; }")
+	gen := llvm.NewLlvmCodegen()
+	gen.GenerateModules([]*parser.Program{intrinsics, parse(t, "", `import "llvm"; fn main() { return llvm.trap(); }`)})
+	if errs := gen.Errors(); len(errs) != 1 || errs[0].String() != "1:8: error[C0005]: Symbol [llvm.trap] is reserved for the runtime." {
+		t.Errorf("Expected a clash with [llvm.trap] but got %v.", errs)
+	}
+}
+
+func TestExterns(t *testing.T) {
+	testErrors(t, "extern fn malloc(size: i32) -> string; fn main() { return 0; }", "1:11: error[C0005]: Symbol [malloc] is already defined.")
+	testErrors(t, "export fn main() { return 0; }")
+	libc := parse(t, "libc", "pub extern fn putchar(c: i32) -> i32; pub extern fn malloc(size: u64) -> string;")
+	util := parse(t, "util", "extern fn putchar(c: i32) -> i32; pub export fn twice(x: i32) -> i32 { return 2 * x; }")
+	main := parse(t, "", `import "libc"; import "util"; fn main() { libc.putchar(util.twice(33)); println(len(libc.malloc(u64(8)))); return 0; }`)
+	gen := llvm.NewLlvmCodegen()
+	act := gen.GenerateModules([]*parser.Program{libc, util, main})
+	if gen.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", gen.Errors())
+	}
+	for _, exp := range []string{"declare i32 @putchar(i32 %c)", "declare i8* @malloc(i64 %size)", "define i32 @twice(i32 %x)", "call i32 @twice(i32 33)"} {
+		if strings.Count(act, exp) != 1 {
+			t.Errorf("Expected [%s] once in [%s].", exp, act)
+		}
+	}
+	bad := parse(t, "bad", "extern fn putchar(c: i64) -> i32;")
+	gen = llvm.NewLlvmCodegen()
+	gen.GenerateModules([]*parser.Program{libc, bad, parse(t, "", `import "bad"; fn main() { return 0; }`)})
+	if errs := gen.Errors(); len(errs) != 1 || errs[0].String() != "1:11: error[C0005]: Symbol [putchar] is already defined." {
+		t.Errorf("Expected a conflicting declaration of [putchar] but got %v.", errs)
+	}
+	gen = llvm.NewLlvmCodegen()
+	gen.GenerateModules([]*parser.Program{libc, parse(t, "", `import "libc"; fn putchar(c: i32) { return c; } fn main() { return 0; }`)})
+	if errs := gen.Errors(); len(errs) != 1 || errs[0].String() != "1:19: error[C0005]: Symbol [putchar] is already defined." {
+		t.Errorf("Expected a conflicting definition of [putchar] but got %v.", errs)
+	}
+}
+
+func parse(t *testing.T, path string, input string) *parser.Program {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	prog := p.Parse()
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", p.Errors())
+	}
+	prog.Path = path
+	return prog
+}
+
 func compile(t *testing.T, file string) string {
 	t.Helper()
 	input, _ := os.ReadFile(file)
@@ -46,3 +148,24 @@ func equalsIgnoreSpace(exp, act string) bool {
 	act = matcher.ReplaceAllString(act, "")
 	return exp == act
 }
+
+func testErrors(t *testing.T, input string, expected ...string) {
+	t.Helper()
+	lexer := lexer.NewLexer(input)
+	parser := parser.NewParser(lexer)
+	prog := parser.Parse()
+	if parser.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", parser.Errors())
+	}
+	gen := llvm.NewLlvmCodegen()
+	gen.Generate(prog)
+	actual := gen.Errors()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 6, i64* %0
+	store i64 0, i64* %1
+	%2 = load i64, i64* %1
+	%3 = icmp ne i64 %2, 0
+	br i1 %3, label %and.rhs, label %and.merge
+
+and.rhs:
+	%4 = load i64, i64* %0
+	%5 = load i64, i64* %1
+	%6 = call i64 @div.i64(i64 %4, i64 %5)
+	%7 = icmp sgt i64 %6, 1
+	br label %and.merge
+
+and.merge:
+	%8 = phi i1 [ false, %main.entry ], [ %7, %and.rhs ]
+	br i1 %8, label %if.then, label %if.merge
+
+if.then:
+	ret i64 1
+
+if.merge:
+	ret i64 0
+}
+
+define i64 @div.i64(i64 %a, i64 %b) {
+div.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	%2 = sub i64 0, %a
+	ret i64 %2
+
+div:
+	%3 = sdiv i64 %a, %b
+	ret i64 %3
+
+fail:
+	%4 = call i32 @fflush(i8* null)
+	%5 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
@@ -0,0 +1,25 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 1, i64* %0
+	store i64 0, i64* %1
+	%2 = load i64, i64* %0
+	%3 = icmp eq i64 %2, 0
+	br i1 %3, label %or.merge, label %or.rhs
+
+or.rhs:
+	%4 = load i64, i64* %1
+	%5 = icmp eq i64 %4, 0
+	br label %or.merge
+
+or.merge:
+	%6 = phi i1 [ true, %main.entry ], [ %5, %or.rhs ]
+	br i1 %6, label %if.then, label %if.merge
+
+if.then:
+	ret i64 1
+
+if.merge:
+	ret i64 0
+}
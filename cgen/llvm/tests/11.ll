@@ -0,0 +1,42 @@
+define i64 @check(i64 %a, i64 %b, i64 %c) {
+check.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	%2 = alloca i64
+	store i64 %a, i64* %0
+	store i64 %b, i64* %1
+	store i64 %c, i64* %2
+	%3 = load i64, i64* %0
+	%4 = icmp sgt i64 %3, 0
+	br i1 %4, label %and.rhs, label %and.merge
+
+and.rhs:
+	%5 = load i64, i64* %1
+	%6 = icmp sgt i64 %5, 0
+	br label %and.merge
+
+and.merge:
+	%7 = phi i1 [ false, %check.entry ], [ %6, %and.rhs ]
+	br i1 %7, label %or.merge, label %or.rhs
+
+or.rhs:
+	%8 = load i64, i64* %2
+	%9 = icmp sgt i64 %8, 0
+	br label %or.merge
+
+or.merge:
+	%10 = phi i1 [ true, %and.merge ], [ %9, %or.rhs ]
+	br i1 %10, label %if.then, label %if.merge
+
+if.then:
+	ret i64 1
+
+if.merge:
+	ret i64 0
+}
+
+define i64 @main() {
+main.entry:
+	%0 = call i64 @check(i64 1, i64 0, i64 1)
+	ret i64 %0
+}
@@ -0,0 +1,51 @@
+define i64 @check(i64 %a, i64 %b, i64 %c) {
+check.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	%2 = alloca i64
+	store i64 %a, i64* %0
+	store i64 %b, i64* %1
+	store i64 %c, i64* %2
+	%3 = load i64, i64* %0
+	%4 = icmp sgt i64 %3, 0
+	br i1 %4, label %or.merge, label %or.rhs
+
+or.rhs:
+	%5 = load i64, i64* %1
+	%6 = icmp sgt i64 %5, 0
+	br i1 %6, label %and.rhs, label %and.merge
+
+and.rhs:
+	%7 = load i64, i64* %2
+	%8 = icmp sgt i64 %7, 0
+	br i1 %8, label %or.merge.1, label %or.rhs.1
+
+or.rhs.1:
+	%9 = load i64, i64* %0
+	%10 = icmp slt i64 %9, 0
+	br label %or.merge.1
+
+or.merge.1:
+	%11 = phi i1 [ true, %and.rhs ], [ %10, %or.rhs.1 ]
+	br label %and.merge
+
+and.merge:
+	%12 = phi i1 [ false, %or.rhs ], [ %11, %or.merge.1 ]
+	br label %or.merge
+
+or.merge:
+	%13 = phi i1 [ true, %check.entry ], [ %12, %and.merge ]
+	br i1 %13, label %if.then, label %if.merge
+
+if.then:
+	ret i64 1
+
+if.merge:
+	ret i64 0
+}
+
+define i64 @main() {
+main.entry:
+	%0 = call i64 @check(i64 0, i64 1, i64 1)
+	ret i64 %0
+}
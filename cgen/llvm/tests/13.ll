@@ -0,0 +1,22 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	store i64 1, i64* %0
+	%1 = load i64, i64* %0
+	%2 = icmp slt i64 %1, 2
+	br i1 %2, label %if.then, label %if.merge
+
+if.then:
+	%3 = load i64, i64* %0
+	%4 = icmp slt i64 %3, 1
+	br i1 %4, label %if.then.1, label %if.merge.1
+
+if.merge:
+	ret i64 0
+
+if.then.1:
+	ret i64 1
+
+if.merge.1:
+	br label %if.merge
+}
@@ -0,0 +1,24 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 1, i64* %0
+	store i64 2, i64* %1
+	%2 = load i64, i64* %0
+	%3 = load i64, i64* %1
+	%4 = add i64 %2, %3
+	store i64 %4, i64* %0
+	%5 = load i64, i64* %0
+	%6 = icmp sgt i64 %5, 2
+	br i1 %6, label %if.then, label %if.merge
+
+if.then:
+	%7 = load i64, i64* %0
+	%8 = mul i64 %7, 2
+	store i64 %8, i64* %1
+	br label %if.merge
+
+if.merge:
+	%9 = load i64, i64* %1
+	ret i64 %9
+}
@@ -0,0 +1,27 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 0, i64* %0
+	store i64 0, i64* %1
+	br label %while.header
+
+while.header:
+	%2 = load i64, i64* %0
+	%3 = icmp slt i64 %2, 10
+	br i1 %3, label %while.body, label %while.exit
+
+while.body:
+	%4 = load i64, i64* %0
+	%5 = add i64 %4, 1
+	store i64 %5, i64* %0
+	%6 = load i64, i64* %1
+	%7 = load i64, i64* %0
+	%8 = add i64 %6, %7
+	store i64 %8, i64* %1
+	br label %while.header
+
+while.exit:
+	%9 = load i64, i64* %1
+	ret i64 %9
+}
@@ -0,0 +1,46 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 0, i64* %0
+	store i64 0, i64* %1
+	br label %for.header
+
+for.header:
+	%2 = load i64, i64* %1
+	%3 = icmp slt i64 %2, 10
+	br i1 %3, label %for.body, label %for.exit
+
+for.body:
+	%4 = load i64, i64* %1
+	%5 = icmp eq i64 %4, 3
+	br i1 %5, label %if.then, label %if.merge
+
+if.then:
+	br label %for.latch
+
+if.merge:
+	%6 = load i64, i64* %1
+	%7 = icmp sgt i64 %6, 6
+	br i1 %7, label %if.then.1, label %if.merge.1
+
+if.then.1:
+	br label %for.exit
+
+if.merge.1:
+	%8 = load i64, i64* %0
+	%9 = load i64, i64* %1
+	%10 = add i64 %8, %9
+	store i64 %10, i64* %0
+	br label %for.latch
+
+for.latch:
+	%11 = load i64, i64* %1
+	%12 = add i64 %11, 1
+	store i64 %12, i64* %1
+	br label %for.header
+
+for.exit:
+	%13 = load i64, i64* %0
+	ret i64 %13
+}
@@ -0,0 +1,53 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 0, i64* %0
+	br label %for.header
+
+for.header:
+	br label %for.body
+
+for.body:
+	store i64 0, i64* %1
+	br label %while.header
+
+while.header:
+	br label %while.body
+
+while.body:
+	%2 = load i64, i64* %1
+	%3 = add i64 %2, 1
+	store i64 %3, i64* %1
+	%4 = load i64, i64* %1
+	%5 = icmp sge i64 %4, 3
+	br i1 %5, label %if.then, label %if.merge
+
+if.then:
+	br label %while.exit
+
+if.merge:
+	br label %while.header
+
+while.exit:
+	%6 = load i64, i64* %0
+	%7 = load i64, i64* %1
+	%8 = add i64 %6, %7
+	store i64 %8, i64* %0
+	%9 = load i64, i64* %0
+	%10 = icmp sgt i64 %9, 10
+	br i1 %10, label %if.then.1, label %if.merge.1
+
+if.then.1:
+	%11 = load i64, i64* %0
+	ret i64 %11
+
+if.merge.1:
+	br label %for.latch
+
+for.latch:
+	br label %for.header
+
+for.exit:
+	unreachable
+}
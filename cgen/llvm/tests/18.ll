@@ -0,0 +1,51 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	%2 = alloca i64
+	%3 = alloca i64
+	%4 = alloca i64
+	store i64 1, i64* %0
+	%5 = load i64, i64* %0
+	%6 = icmp sgt i64 %5, 0
+	br i1 %6, label %if.then, label %if.merge
+
+if.then:
+	store i64 10, i64* %1
+	%7 = load i64, i64* %1
+	%8 = add i64 %7, 1
+	store i64 %8, i64* %1
+	br label %if.merge
+
+if.merge:
+	store i64 0, i64* %2
+	store i64 0, i64* %3
+	br label %for.header
+
+for.header:
+	%9 = load i64, i64* %3
+	%10 = icmp slt i64 %9, 3
+	br i1 %10, label %for.body, label %for.exit
+
+for.body:
+	%11 = load i64, i64* %3
+	%12 = mul i64 %11, 2
+	store i64 %12, i64* %4
+	%13 = load i64, i64* %2
+	%14 = load i64, i64* %4
+	%15 = add i64 %13, %14
+	store i64 %15, i64* %2
+	br label %for.latch
+
+for.latch:
+	%16 = load i64, i64* %3
+	%17 = add i64 %16, 1
+	store i64 %17, i64* %3
+	br label %for.header
+
+for.exit:
+	%18 = load i64, i64* %0
+	%19 = load i64, i64* %2
+	%20 = add i64 %18, %19
+	ret i64 %20
+}
@@ -0,0 +1,29 @@
+define i1 @isEven(i64 %n) {
+isEven.entry:
+	%0 = alloca i64
+	store i64 %n, i64* %0
+	%1 = load i64, i64* %0
+	%2 = and i64 %1, 1
+	%3 = icmp eq i64 %2, 0
+	ret i1 %3
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i1
+	%1 = alloca i1
+	%2 = call i1 @isEven(i64 4)
+	store i1 %2, i1* %0
+	store i1 false, i1* %1
+	%3 = load i1, i1* %0
+	%4 = xor i1 true, %3
+	store i1 %4, i1* %1
+	%5 = load i1, i1* %1
+	br i1 %5, label %if.then, label %if.merge
+
+if.then:
+	ret i64 1
+
+if.merge:
+	ret i64 0
+}
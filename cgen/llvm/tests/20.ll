@@ -0,0 +1,13 @@
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	store i64 -1, i64* %0
+	%2 = or i64 240, 15
+	store i64 %2, i64* %1
+	%3 = load i64, i64* %0
+	%4 = lshr i64 %3, 56
+	%5 = load i64, i64* %1
+	%6 = and i64 %4, %5
+	ret i64 %6
+}
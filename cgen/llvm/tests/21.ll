@@ -0,0 +1,53 @@
+@.str.0 = private unnamed_addr constant [13 x i8] c"HELLO\09WORLD!\00"
+@.str.1 = private unnamed_addr constant [19 x i8] c"hello \22world\22 \F0\9F\98\80\00"
+@.str.2 = private unnamed_addr constant [4 x i8] c"%s\0A\00"
+@.str.3 = private unnamed_addr constant [3 x i8] c"%s\00"
+@.str.4 = private unnamed_addr constant [1 x i8] c"\00"
+@.str.5 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.6 = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.7 = private unnamed_addr constant [6 x i8] c"false\00"
+
+define i8* @greeting(i64 %loud) {
+greeting.entry:
+	%0 = alloca i64
+	store i64 %loud, i64* %0
+	%1 = load i64, i64* %0
+	%2 = icmp sgt i64 %1, 0
+	br i1 %2, label %if.then, label %if.merge
+
+if.then:
+	ret i8* getelementptr ([13 x i8], [13 x i8]* @.str.0, i64 0, i64 0)
+
+if.merge:
+	ret i8* getelementptr ([19 x i8], [19 x i8]* @.str.1, i64 0, i64 0)
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i8*
+	%1 = alloca i64
+	%2 = call i8* @greeting(i64 0)
+	store i8* %2, i8** %0
+	%3 = load i8*, i8** %0
+	%4 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0), i8* %3)
+	%5 = sext i32 %4 to i64
+	%6 = call i8* @greeting(i64 1)
+	%7 = call i32 (i8*, ...) @printf(i8* getelementptr ([3 x i8], [3 x i8]* @.str.3, i64 0, i64 0), i8* %6)
+	%8 = sext i32 %7 to i64
+	%9 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.4, i64 0, i64 0))
+	%10 = sext i32 %9 to i64
+	%11 = mul i64 42, 2
+	%12 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 %11)
+	%13 = sext i32 %12 to i64
+	%14 = icmp slt i64 1, 2
+	%15 = select i1 %14, i8* getelementptr ([5 x i8], [5 x i8]* @.str.6, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.7, i64 0, i64 0)
+	%16 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0), i8* %15)
+	%17 = sext i32 %16 to i64
+	store i64 %17, i64* %1
+	%18 = load i64, i64* %1
+	%19 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 %18)
+	%20 = sext i32 %19 to i64
+	ret i64 0
+}
+
+declare i32 @printf(i8* %format, ...)
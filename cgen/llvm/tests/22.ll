@@ -0,0 +1,192 @@
+@.str.0 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+define i64 @double(i64 %x) {
+double.entry:
+	%0 = alloca i64
+	store i64 %x, i64* %0
+	%1 = load i64, i64* %0
+	%2 = mul i64 2, %1
+	ret i64 %2
+}
+
+define { i64 (i8*, i64)*, i8* } @adder(i64 %a) {
+adder.entry:
+	%0 = call i8* @malloc(i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64))
+	%1 = bitcast i8* %0 to i64*
+	store i64 %a, i64* %1
+	%2 = call i8* @malloc(i64 ptrtoint ({ i64* }* getelementptr ({ i64* }, { i64* }* null, i32 1) to i64))
+	%3 = bitcast i8* %2 to { i64* }*
+	%4 = getelementptr { i64* }, { i64* }* %3, i32 0, i32 0
+	store i64* %1, i64** %4
+	%5 = insertvalue { i64 (i8*, i64)*, i8* } undef, i64 (i8*, i64)* @adder.fn.0, 0
+	%6 = insertvalue { i64 (i8*, i64)*, i8* } %5, i8* %2, 1
+	ret { i64 (i8*, i64)*, i8* } %6
+}
+
+define { i64 (i8*)*, i8* } @counter() {
+counter.entry:
+	%0 = call i8* @malloc(i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64))
+	%1 = bitcast i8* %0 to i64*
+	store i64 0, i64* %1
+	%2 = call i8* @malloc(i64 ptrtoint ({ i64* }* getelementptr ({ i64* }, { i64* }* null, i32 1) to i64))
+	%3 = bitcast i8* %2 to { i64* }*
+	%4 = getelementptr { i64* }, { i64* }* %3, i32 0, i32 0
+	store i64* %1, i64** %4
+	%5 = insertvalue { i64 (i8*)*, i8* } undef, i64 (i8*)* @counter.fn.0, 0
+	%6 = insertvalue { i64 (i8*)*, i8* } %5, i8* %2, 1
+	ret { i64 (i8*)*, i8* } %6
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca { i64 (i8*, i64)*, i8* }
+	%1 = alloca { i64 (i8*)*, i8* }
+	%2 = alloca { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* }
+	%3 = call { i64 (i8*, i64)*, i8* } @adder(i64 1)
+	store { i64 (i8*, i64)*, i8* } %3, { i64 (i8*, i64)*, i8* }* %0
+	%4 = load { i64 (i8*, i64)*, i8* }, { i64 (i8*, i64)*, i8* }* %0
+	%5 = extractvalue { i64 (i8*, i64)*, i8* } %4, 0
+	%6 = extractvalue { i64 (i8*, i64)*, i8* } %4, 1
+	%7 = call i64 %5(i8* %6, i64 41)
+	%8 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %7)
+	%9 = sext i32 %8 to i64
+	%10 = insertvalue { i64 (i8*, i64)*, i8* } undef, i64 (i8*, i64)* @double.closure, 0
+	%11 = insertvalue { i64 (i8*, i64)*, i8* } %10, i8* null, 1
+	%12 = call i64 @apply.int.int({ i64 (i8*, i64)*, i8* } %11, i64 21)
+	%13 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %12)
+	%14 = sext i32 %13 to i64
+	%15 = insertvalue { i64 (i8*, i64)*, i8* } undef, i64 (i8*, i64)* @main.fn.0, 0
+	%16 = insertvalue { i64 (i8*, i64)*, i8* } %15, i8* null, 1
+	%17 = call i64 @apply.int.int({ i64 (i8*, i64)*, i8* } %16, i64 7)
+	%18 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %17)
+	%19 = sext i32 %18 to i64
+	%20 = call { i64 (i8*)*, i8* } @counter()
+	store { i64 (i8*)*, i8* } %20, { i64 (i8*)*, i8* }* %1
+	%21 = load { i64 (i8*)*, i8* }, { i64 (i8*)*, i8* }* %1
+	%22 = extractvalue { i64 (i8*)*, i8* } %21, 0
+	%23 = extractvalue { i64 (i8*)*, i8* } %21, 1
+	%24 = call i64 %22(i8* %23)
+	%25 = load { i64 (i8*)*, i8* }, { i64 (i8*)*, i8* }* %1
+	%26 = extractvalue { i64 (i8*)*, i8* } %25, 0
+	%27 = extractvalue { i64 (i8*)*, i8* } %25, 1
+	%28 = call i64 %26(i8* %27)
+	%29 = load { i64 (i8*)*, i8* }, { i64 (i8*)*, i8* }* %1
+	%30 = extractvalue { i64 (i8*)*, i8* } %29, 0
+	%31 = extractvalue { i64 (i8*)*, i8* } %29, 1
+	%32 = call i64 %30(i8* %31)
+	%33 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %32)
+	%34 = sext i32 %33 to i64
+	%35 = call i8* @malloc(i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64))
+	%36 = bitcast i8* %35 to i64*
+	store i64 10, i64* %36
+	%37 = call i8* @malloc(i64 ptrtoint ({ i64* }* getelementptr ({ i64* }, { i64* }* null, i32 1) to i64))
+	%38 = bitcast i8* %37 to { i64* }*
+	%39 = getelementptr { i64* }, { i64* }* %38, i32 0, i32 0
+	store i64* %36, i64** %39
+	%40 = insertvalue { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* } undef, { i64 (i8*, i64)*, i8* } (i8*, i64)* @main.fn.1, 0
+	%41 = insertvalue { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* } %40, i8* %37, 1
+	store { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* } %41, { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* }* %2
+	%42 = load { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* }, { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* }* %2
+	%43 = extractvalue { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* } %42, 0
+	%44 = extractvalue { { i64 (i8*, i64)*, i8* } (i8*, i64)*, i8* } %42, 1
+	%45 = call { i64 (i8*, i64)*, i8* } %43(i8* %44, i64 1)
+	%46 = extractvalue { i64 (i8*, i64)*, i8* } %45, 0
+	%47 = extractvalue { i64 (i8*, i64)*, i8* } %45, 1
+	%48 = call i64 %46(i8* %47, i64 2)
+	ret i64 %48
+}
+
+declare i8* @malloc(i64 %size)
+
+define i64 @adder.fn.0(i8* %env, i64 %b) {
+adder.fn.0.entry:
+	%0 = alloca i64
+	%1 = bitcast i8* %env to { i64* }*
+	%2 = getelementptr { i64* }, { i64* }* %1, i32 0, i32 0
+	%3 = load i64*, i64** %2
+	store i64 %b, i64* %0
+	%4 = load i64, i64* %3
+	%5 = load i64, i64* %0
+	%6 = add i64 %4, %5
+	ret i64 %6
+}
+
+define i64 @counter.fn.0(i8* %env) {
+counter.fn.0.entry:
+	%0 = bitcast i8* %env to { i64* }*
+	%1 = getelementptr { i64* }, { i64* }* %0, i32 0, i32 0
+	%2 = load i64*, i64** %1
+	%3 = load i64, i64* %2
+	%4 = add i64 %3, 1
+	store i64 %4, i64* %2
+	%5 = load i64, i64* %2
+	ret i64 %5
+}
+
+declare i32 @printf(i8* %format, ...)
+
+define i64 @apply.int.int({ i64 (i8*, i64)*, i8* } %f, i64 %x) {
+apply.int.int.entry:
+	%0 = alloca { i64 (i8*, i64)*, i8* }
+	%1 = alloca i64
+	store { i64 (i8*, i64)*, i8* } %f, { i64 (i8*, i64)*, i8* }* %0
+	store i64 %x, i64* %1
+	%2 = load { i64 (i8*, i64)*, i8* }, { i64 (i8*, i64)*, i8* }* %0
+	%3 = load i64, i64* %1
+	%4 = extractvalue { i64 (i8*, i64)*, i8* } %2, 0
+	%5 = extractvalue { i64 (i8*, i64)*, i8* } %2, 1
+	%6 = call i64 %4(i8* %5, i64 %3)
+	ret i64 %6
+}
+
+define i64 @double.closure(i8* %env, i64 %x) {
+double.closure.entry:
+	%0 = call i64 @double(i64 %x)
+	ret i64 %0
+}
+
+define i64 @main.fn.0(i8* %env, i64 %x) {
+main.fn.0.entry:
+	%0 = alloca i64
+	store i64 %x, i64* %0
+	%1 = load i64, i64* %0
+	%2 = load i64, i64* %0
+	%3 = mul i64 %1, %2
+	ret i64 %3
+}
+
+define { i64 (i8*, i64)*, i8* } @main.fn.1(i8* %env, i64 %x) {
+main.fn.1.entry:
+	%0 = bitcast i8* %env to { i64* }*
+	%1 = getelementptr { i64* }, { i64* }* %0, i32 0, i32 0
+	%2 = load i64*, i64** %1
+	%3 = call i8* @malloc(i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64))
+	%4 = bitcast i8* %3 to i64*
+	store i64 %x, i64* %4
+	%5 = call i8* @malloc(i64 ptrtoint ({ i64*, i64* }* getelementptr ({ i64*, i64* }, { i64*, i64* }* null, i32 1) to i64))
+	%6 = bitcast i8* %5 to { i64*, i64* }*
+	%7 = getelementptr { i64*, i64* }, { i64*, i64* }* %6, i32 0, i32 0
+	store i64* %4, i64** %7
+	%8 = getelementptr { i64*, i64* }, { i64*, i64* }* %6, i32 0, i32 1
+	store i64* %2, i64** %8
+	%9 = insertvalue { i64 (i8*, i64)*, i8* } undef, i64 (i8*, i64)* @main.fn.1.fn.0, 0
+	%10 = insertvalue { i64 (i8*, i64)*, i8* } %9, i8* %5, 1
+	ret { i64 (i8*, i64)*, i8* } %10
+}
+
+define i64 @main.fn.1.fn.0(i8* %env, i64 %y) {
+main.fn.1.fn.0.entry:
+	%0 = alloca i64
+	%1 = bitcast i8* %env to { i64*, i64* }*
+	%2 = getelementptr { i64*, i64* }, { i64*, i64* }* %1, i32 0, i32 0
+	%3 = load i64*, i64** %2
+	%4 = getelementptr { i64*, i64* }, { i64*, i64* }* %1, i32 0, i32 1
+	%5 = load i64*, i64** %4
+	store i64 %y, i64* %0
+	%6 = load i64, i64* %3
+	%7 = load i64, i64* %0
+	%8 = add i64 %6, %7
+	%9 = load i64, i64* %5
+	%10 = add i64 %8, %9
+	ret i64 %10
+}
@@ -0,0 +1,296 @@
+@.str.0 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.1 = private unnamed_addr constant [5 x i8] c"4:13\00"
+@.str.2 = private unnamed_addr constant [5 x i8] c"11:3\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"13:11\00"
+@.str.4 = private unnamed_addr constant [6 x i8] c"13:19\00"
+@.str.5 = private unnamed_addr constant [5 x i8] c"13:3\00"
+@.str.6 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.7 = private unnamed_addr constant [7 x i8] c"donkey\00"
+@.str.8 = private unnamed_addr constant [5 x i8] c"17:3\00"
+@.str.9 = private unnamed_addr constant [6 x i8] c"18:11\00"
+@.str.10 = private unnamed_addr constant [6 x i8] c"18:24\00"
+@.str.11 = private unnamed_addr constant [6 x i8] c"19:10\00"
+
+define i64 @sum({ i64, i64* } %xs) {
+sum.entry:
+	%0 = alloca { i64, i64* }
+	%1 = alloca i64
+	%2 = alloca i64
+	store { i64, i64* } %xs, { i64, i64* }* %0
+	store i64 0, i64* %1
+	store i64 0, i64* %2
+	br label %for.header
+
+for.header:
+	%3 = load i64, i64* %2
+	%4 = load { i64, i64* }, { i64, i64* }* %0
+	%5 = extractvalue { i64, i64* } %4, 0
+	%6 = icmp slt i64 %3, %5
+	br i1 %6, label %for.body, label %for.exit
+
+for.body:
+	%7 = load i64, i64* %1
+	%8 = load { i64, i64* }, { i64, i64* }* %0
+	%9 = load i64, i64* %2
+	%10 = extractvalue { i64, i64* } %8, 0
+	%11 = icmp ult i64 %9, %10
+	br i1 %11, label %index.ok, label %index.fail
+
+index.ok:
+	%12 = extractvalue { i64, i64* } %8, 1
+	%13 = getelementptr i64, i64* %12, i64 %9
+	%14 = load i64, i64* %13
+	%15 = add i64 %7, %14
+	store i64 %15, i64* %1
+	br label %for.latch
+
+index.fail:
+	%16 = call i32 @fflush(i8* null)
+	%17 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i64 %9, i64 %10)
+	call void @llvm.trap()
+	unreachable
+
+for.latch:
+	%18 = load i64, i64* %2
+	%19 = add i64 %18, 1
+	store i64 %19, i64* %2
+	br label %for.header
+
+for.exit:
+	%20 = load i64, i64* %1
+	ret i64 %20
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca { i64, i64* }
+	%1 = alloca { i64, i64* }
+	%2 = alloca { i64, { i64, i64* }* }
+	%3 = call i8* @malloc(i64 mul (i64 4, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%4 = bitcast i8* %3 to i64*
+	%5 = getelementptr i64, i64* %4, i64 0
+	store i64 1, i64* %5
+	%6 = getelementptr i64, i64* %4, i64 1
+	store i64 2, i64* %6
+	%7 = getelementptr i64, i64* %4, i64 2
+	store i64 3, i64* %7
+	%8 = getelementptr i64, i64* %4, i64 3
+	store i64 4, i64* %8
+	%9 = insertvalue { i64, i64* } undef, i64 4, 0
+	%10 = insertvalue { i64, i64* } %9, i64* %4, 1
+	store { i64, i64* } %10, { i64, i64* }* %0
+	%11 = load { i64, i64* }, { i64, i64* }* %0
+	%12 = extractvalue { i64, i64* } %11, 0
+	%13 = icmp ult i64 0, %12
+	br i1 %13, label %index.ok, label %index.fail
+
+index.ok:
+	%14 = extractvalue { i64, i64* } %11, 1
+	%15 = getelementptr i64, i64* %14, i64 0
+	store i64 10, i64* %15
+	%16 = load { i64, i64* }, { i64, i64* }* %0
+	store { i64, i64* } %16, { i64, i64* }* %1
+	%17 = load { i64, i64* }, { i64, i64* }* %0
+	%18 = extractvalue { i64, i64* } %17, 0
+	%19 = icmp ult i64 1, %18
+	br i1 %19, label %index.ok.1, label %index.fail.1
+
+index.fail:
+	%20 = call i32 @fflush(i8* null)
+	%21 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.2, i64 0, i64 0), i64 0, i64 %12)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.1:
+	%22 = extractvalue { i64, i64* } %17, 1
+	%23 = getelementptr i64, i64* %22, i64 1
+	%24 = load i64, i64* %23
+	%25 = load { i64, i64* }, { i64, i64* }* %0
+	%26 = extractvalue { i64, i64* } %25, 0
+	%27 = icmp ult i64 2, %26
+	br i1 %27, label %index.ok.2, label %index.fail.2
+
+index.fail.1:
+	%28 = call i32 @fflush(i8* null)
+	%29 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 1, i64 %18)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.2:
+	%30 = extractvalue { i64, i64* } %25, 1
+	%31 = getelementptr i64, i64* %30, i64 2
+	%32 = load i64, i64* %31
+	%33 = add i64 %24, %32
+	%34 = load { i64, i64* }, { i64, i64* }* %1
+	%35 = extractvalue { i64, i64* } %34, 0
+	%36 = icmp ult i64 3, %35
+	br i1 %36, label %index.ok.3, label %index.fail.3
+
+index.fail.2:
+	%37 = call i32 @fflush(i8* null)
+	%38 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.4, i64 0, i64 0), i64 2, i64 %26)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.3:
+	%39 = extractvalue { i64, i64* } %34, 1
+	%40 = getelementptr i64, i64* %39, i64 3
+	store i64 %33, i64* %40
+	%41 = load { i64, i64* }, { i64, i64* }* %0
+	%42 = call i64 @sum({ i64, i64* } %41)
+	%43 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.6, i64 0, i64 0), i64 %42)
+	%44 = sext i32 %43 to i64
+	%45 = call i64 @strlen(i8* getelementptr ([7 x i8], [7 x i8]* @.str.7, i64 0, i64 0))
+	%46 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.6, i64 0, i64 0), i64 %45)
+	%47 = sext i32 %46 to i64
+	%48 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint ({ i64, i64* }* getelementptr ({ i64, i64* }, { i64, i64* }* null, i32 1) to i64)))
+	%49 = bitcast i8* %48 to { i64, i64* }*
+	%50 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%51 = bitcast i8* %50 to i64*
+	%52 = getelementptr i64, i64* %51, i64 0
+	store i64 1, i64* %52
+	%53 = getelementptr i64, i64* %51, i64 1
+	store i64 2, i64* %53
+	%54 = insertvalue { i64, i64* } undef, i64 2, 0
+	%55 = insertvalue { i64, i64* } %54, i64* %51, 1
+	%56 = getelementptr { i64, i64* }, { i64, i64* }* %49, i64 0
+	store { i64, i64* } %55, { i64, i64* }* %56
+	%57 = call i8* @malloc(i64 mul (i64 1, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%58 = bitcast i8* %57 to i64*
+	%59 = getelementptr i64, i64* %58, i64 0
+	store i64 3, i64* %59
+	%60 = insertvalue { i64, i64* } undef, i64 1, 0
+	%61 = insertvalue { i64, i64* } %60, i64* %58, 1
+	%62 = getelementptr { i64, i64* }, { i64, i64* }* %49, i64 1
+	store { i64, i64* } %61, { i64, i64* }* %62
+	%63 = insertvalue { i64, { i64, i64* }* } undef, i64 2, 0
+	%64 = insertvalue { i64, { i64, i64* }* } %63, { i64, i64* }* %49, 1
+	store { i64, { i64, i64* }* } %64, { i64, { i64, i64* }* }* %2
+	%65 = load { i64, { i64, i64* }* }, { i64, { i64, i64* }* }* %2
+	%66 = extractvalue { i64, { i64, i64* }* } %65, 0
+	%67 = icmp ult i64 1, %66
+	br i1 %67, label %index.ok.4, label %index.fail.4
+
+index.fail.3:
+	%68 = call i32 @fflush(i8* null)
+	%69 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.5, i64 0, i64 0), i64 3, i64 %35)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.4:
+	%70 = extractvalue { i64, { i64, i64* }* } %65, 1
+	%71 = getelementptr { i64, i64* }, { i64, i64* }* %70, i64 1
+	%72 = load { i64, i64* }, { i64, i64* }* %71
+	%73 = extractvalue { i64, i64* } %72, 0
+	%74 = icmp ult i64 0, %73
+	br i1 %74, label %index.ok.5, label %index.fail.5
+
+index.fail.4:
+	%75 = call i32 @fflush(i8* null)
+	%76 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.8, i64 0, i64 0), i64 1, i64 %66)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.5:
+	%77 = extractvalue { i64, i64* } %72, 1
+	%78 = getelementptr i64, i64* %77, i64 0
+	store i64 7, i64* %78
+	%79 = load { i64, { i64, i64* }* }, { i64, { i64, i64* }* }* %2
+	%80 = extractvalue { i64, { i64, i64* }* } %79, 0
+	%81 = icmp ult i64 0, %80
+	br i1 %81, label %index.ok.6, label %index.fail.6
+
+index.fail.5:
+	%82 = call i32 @fflush(i8* null)
+	%83 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.8, i64 0, i64 0), i64 0, i64 %73)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.6:
+	%84 = extractvalue { i64, { i64, i64* }* } %79, 1
+	%85 = getelementptr { i64, i64* }, { i64, i64* }* %84, i64 0
+	%86 = load { i64, i64* }, { i64, i64* }* %85
+	%87 = extractvalue { i64, i64* } %86, 0
+	%88 = icmp ult i64 1, %87
+	br i1 %88, label %index.ok.7, label %index.fail.7
+
+index.fail.6:
+	%89 = call i32 @fflush(i8* null)
+	%90 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.9, i64 0, i64 0), i64 0, i64 %80)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.7:
+	%91 = extractvalue { i64, i64* } %86, 1
+	%92 = getelementptr i64, i64* %91, i64 1
+	%93 = load i64, i64* %92
+	%94 = load { i64, { i64, i64* }* }, { i64, { i64, i64* }* }* %2
+	%95 = extractvalue { i64, { i64, i64* }* } %94, 0
+	%96 = icmp ult i64 1, %95
+	br i1 %96, label %index.ok.8, label %index.fail.8
+
+index.fail.7:
+	%97 = call i32 @fflush(i8* null)
+	%98 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.9, i64 0, i64 0), i64 1, i64 %87)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.8:
+	%99 = extractvalue { i64, { i64, i64* }* } %94, 1
+	%100 = getelementptr { i64, i64* }, { i64, i64* }* %99, i64 1
+	%101 = load { i64, i64* }, { i64, i64* }* %100
+	%102 = extractvalue { i64, i64* } %101, 0
+	%103 = icmp ult i64 0, %102
+	br i1 %103, label %index.ok.9, label %index.fail.9
+
+index.fail.8:
+	%104 = call i32 @fflush(i8* null)
+	%105 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.10, i64 0, i64 0), i64 1, i64 %95)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.9:
+	%106 = extractvalue { i64, i64* } %101, 1
+	%107 = getelementptr i64, i64* %106, i64 0
+	%108 = load i64, i64* %107
+	%109 = add i64 %93, %108
+	%110 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.6, i64 0, i64 0), i64 %109)
+	%111 = sext i32 %110 to i64
+	%112 = load { i64, i64* }, { i64, i64* }* %0
+	%113 = load { i64, i64* }, { i64, i64* }* %0
+	%114 = extractvalue { i64, i64* } %113, 0
+	%115 = sub i64 %114, 1
+	%116 = extractvalue { i64, i64* } %112, 0
+	%117 = icmp ult i64 %115, %116
+	br i1 %117, label %index.ok.10, label %index.fail.10
+
+index.fail.9:
+	%118 = call i32 @fflush(i8* null)
+	%119 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.10, i64 0, i64 0), i64 0, i64 %102)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.10:
+	%120 = extractvalue { i64, i64* } %112, 1
+	%121 = getelementptr i64, i64* %120, i64 %115
+	%122 = load i64, i64* %121
+	ret i64 %122
+
+index.fail.10:
+	%123 = call i32 @fflush(i8* null)
+	%124 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.11, i64 0, i64 0), i64 %115, i64 %116)
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i8* @malloc(i64 %size)
+
+declare i32 @printf(i8* %format, ...)
+
+declare i64 @strlen(i8* %s)
@@ -0,0 +1,148 @@
+%Point = type { i64, i64 }
+
+@.str.0 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.1 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.2 = private unnamed_addr constant [5 x i8] c"19:3\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"20:15\00"
+@.str.4 = private unnamed_addr constant [6 x i8] c"20:22\00"
+
+define %Point @add(%Point %p, %Point %q) {
+add.entry:
+	%0 = alloca %Point
+	%1 = alloca %Point
+	store %Point %p, %Point* %0
+	store %Point %q, %Point* %1
+	%2 = load %Point, %Point* %0
+	%3 = extractvalue %Point %2, 0
+	%4 = load %Point, %Point* %1
+	%5 = extractvalue %Point %4, 0
+	%6 = add i64 %3, %5
+	%7 = insertvalue %Point undef, i64 %6, 0
+	%8 = load %Point, %Point* %0
+	%9 = extractvalue %Point %8, 1
+	%10 = load %Point, %Point* %1
+	%11 = extractvalue %Point %10, 1
+	%12 = add i64 %9, %11
+	%13 = insertvalue %Point %7, i64 %12, 1
+	ret %Point %13
+}
+
+define %Point @scale(%Point %p, i64 %k) {
+scale.entry:
+	%0 = alloca %Point
+	%1 = alloca i64
+	store %Point %p, %Point* %0
+	store i64 %k, i64* %1
+	%2 = load %Point, %Point* %0
+	%3 = extractvalue %Point %2, 0
+	%4 = load i64, i64* %1
+	%5 = mul i64 %3, %4
+	%6 = getelementptr %Point, %Point* %0, i32 0, i32 0
+	store i64 %5, i64* %6
+	%7 = load %Point, %Point* %0
+	%8 = extractvalue %Point %7, 1
+	%9 = load i64, i64* %1
+	%10 = mul i64 %8, %9
+	%11 = getelementptr %Point, %Point* %0, i32 0, i32 1
+	store i64 %10, i64* %11
+	%12 = load %Point, %Point* %0
+	ret %Point %12
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca %Point
+	%1 = alloca %Point
+	%2 = alloca { i64, %Point* }
+	%3 = alloca %Point
+	%4 = insertvalue %Point undef, i64 2, 1
+	%5 = insertvalue %Point %4, i64 1, 0
+	store %Point %5, %Point* %0
+	%6 = load %Point, %Point* %0
+	%7 = call %Point @scale(%Point %6, i64 10)
+	store %Point %7, %Point* %1
+	%8 = load %Point, %Point* %0
+	%9 = extractvalue %Point %8, 0
+	%10 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %9)
+	%11 = sext i32 %10 to i64
+	%12 = load %Point, %Point* %1
+	%13 = extractvalue %Point %12, 1
+	%14 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %13)
+	%15 = sext i32 %14 to i64
+	%16 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint (%Point* getelementptr (%Point, %Point* null, i32 1) to i64)))
+	%17 = bitcast i8* %16 to %Point*
+	%18 = load %Point, %Point* %0
+	%19 = getelementptr %Point, %Point* %17, i64 0
+	store %Point %18, %Point* %19
+	%20 = load %Point, %Point* %1
+	%21 = getelementptr %Point, %Point* %17, i64 1
+	store %Point %20, %Point* %21
+	%22 = insertvalue { i64, %Point* } undef, i64 2, 0
+	%23 = insertvalue { i64, %Point* } %22, %Point* %17, 1
+	store { i64, %Point* } %23, { i64, %Point* }* %2
+	%24 = load { i64, %Point* }, { i64, %Point* }* %2
+	%25 = extractvalue { i64, %Point* } %24, 0
+	%26 = icmp ult i64 1, %25
+	br i1 %26, label %index.ok, label %index.fail
+
+index.ok:
+	%27 = extractvalue { i64, %Point* } %24, 1
+	%28 = getelementptr %Point, %Point* %27, i64 1
+	%29 = getelementptr %Point, %Point* %28, i32 0, i32 0
+	store i64 7, i64* %29
+	%30 = load { i64, %Point* }, { i64, %Point* }* %2
+	%31 = extractvalue { i64, %Point* } %30, 0
+	%32 = icmp ult i64 0, %31
+	br i1 %32, label %index.ok.1, label %index.fail.1
+
+index.fail:
+	%33 = call i32 @fflush(i8* null)
+	%34 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.2, i64 0, i64 0), i64 1, i64 %25)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.1:
+	%35 = extractvalue { i64, %Point* } %30, 1
+	%36 = getelementptr %Point, %Point* %35, i64 0
+	%37 = load %Point, %Point* %36
+	%38 = load { i64, %Point* }, { i64, %Point* }* %2
+	%39 = extractvalue { i64, %Point* } %38, 0
+	%40 = icmp ult i64 1, %39
+	br i1 %40, label %index.ok.2, label %index.fail.2
+
+index.fail.1:
+	%41 = call i32 @fflush(i8* null)
+	%42 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 0, i64 %31)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.2:
+	%43 = extractvalue { i64, %Point* } %38, 1
+	%44 = getelementptr %Point, %Point* %43, i64 1
+	%45 = load %Point, %Point* %44
+	%46 = call %Point @add(%Point %37, %Point %45)
+	store %Point %46, %Point* %3
+	%47 = load %Point, %Point* %3
+	%48 = extractvalue %Point %47, 0
+	%49 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %48)
+	%50 = sext i32 %49 to i64
+	%51 = load %Point, %Point* %3
+	%52 = extractvalue %Point %51, 1
+	ret i64 %52
+
+index.fail.2:
+	%53 = call i32 @fflush(i8* null)
+	%54 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.4, i64 0, i64 0), i64 1, i64 %39)
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @printf(i8* %format, ...)
+
+declare i8* @malloc(i64 %size)
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
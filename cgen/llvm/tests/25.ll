@@ -0,0 +1,180 @@
+%Shape = type { i64, i64, i64, i64 }
+
+@.str.0 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"19:18\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"20:17\00"
+
+define i64 @area(%Shape %s) {
+area.entry:
+	%0 = alloca %Shape
+	%1 = alloca i64
+	%2 = alloca i64
+	%3 = alloca i64
+	store %Shape %s, %Shape* %0
+	%4 = load %Shape, %Shape* %0
+	%5 = extractvalue %Shape %4, 0
+	switch i64 %5, label %match.default [
+		i64 0, label %match.arm
+		i64 1, label %match.arm.1
+		i64 2, label %match.arm.2
+	]
+
+match.arm:
+	%6 = extractvalue %Shape %4, 1
+	store i64 %6, i64* %1
+	%7 = load i64, i64* %1
+	%8 = mul i64 3, %7
+	%9 = load i64, i64* %1
+	%10 = mul i64 %8, %9
+	br label %match.end
+
+match.arm.1:
+	%11 = extractvalue %Shape %4, 2
+	store i64 %11, i64* %2
+	%12 = extractvalue %Shape %4, 3
+	store i64 %12, i64* %3
+	%13 = load i64, i64* %2
+	%14 = load i64, i64* %3
+	%15 = mul i64 %13, %14
+	br label %match.end
+
+match.arm.2:
+	br label %match.end
+
+match.default:
+	unreachable
+
+match.end:
+	%16 = phi i64 [ %10, %match.arm ], [ %15, %match.arm.1 ], [ 0, %match.arm.2 ]
+	ret i64 %16
+}
+
+define i1 @isEmpty(%Shape %s) {
+isEmpty.entry:
+	%0 = alloca %Shape
+	store %Shape %s, %Shape* %0
+	%1 = load %Shape, %Shape* %0
+	%2 = extractvalue %Shape %1, 0
+	switch i64 %2, label %match.arm.1 [
+		i64 2, label %match.arm
+	]
+
+match.arm:
+	br label %match.end
+
+match.arm.1:
+	br label %match.end
+
+match.end:
+	%3 = phi i1 [ true, %match.arm ], [ false, %match.arm.1 ]
+	ret i1 %3
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca { i64, %Shape* }
+	%1 = alloca i64
+	%2 = alloca i64
+	%3 = call i8* @malloc(i64 mul (i64 3, i64 ptrtoint (%Shape* getelementptr (%Shape, %Shape* null, i32 1) to i64)))
+	%4 = bitcast i8* %3 to %Shape*
+	%5 = insertvalue %Shape undef, i64 0, 0
+	%6 = insertvalue %Shape %5, i64 2, 1
+	%7 = getelementptr %Shape, %Shape* %4, i64 0
+	store %Shape %6, %Shape* %7
+	%8 = insertvalue %Shape undef, i64 1, 0
+	%9 = insertvalue %Shape %8, i64 3, 2
+	%10 = insertvalue %Shape %9, i64 4, 3
+	%11 = getelementptr %Shape, %Shape* %4, i64 1
+	store %Shape %10, %Shape* %11
+	%12 = getelementptr %Shape, %Shape* %4, i64 2
+	store %Shape { i64 2, i64 undef, i64 undef, i64 undef }, %Shape* %12
+	%13 = insertvalue { i64, %Shape* } undef, i64 3, 0
+	%14 = insertvalue { i64, %Shape* } %13, %Shape* %4, 1
+	store { i64, %Shape* } %14, { i64, %Shape* }* %0
+	store i64 0, i64* %1
+	store i64 0, i64* %2
+	br label %for.header
+
+for.header:
+	%15 = load i64, i64* %2
+	%16 = load { i64, %Shape* }, { i64, %Shape* }* %0
+	%17 = extractvalue { i64, %Shape* } %16, 0
+	%18 = icmp slt i64 %15, %17
+	br i1 %18, label %for.body, label %for.exit
+
+for.body:
+	%19 = load { i64, %Shape* }, { i64, %Shape* }* %0
+	%20 = load i64, i64* %2
+	%21 = extractvalue { i64, %Shape* } %19, 0
+	%22 = icmp ult i64 %20, %21
+	br i1 %22, label %index.ok, label %index.fail
+
+index.ok:
+	%23 = extractvalue { i64, %Shape* } %19, 1
+	%24 = getelementptr %Shape, %Shape* %23, i64 %20
+	%25 = load %Shape, %Shape* %24
+	%26 = call i64 @area(%Shape %25)
+	%27 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %26)
+	%28 = sext i32 %27 to i64
+	%29 = load { i64, %Shape* }, { i64, %Shape* }* %0
+	%30 = load i64, i64* %2
+	%31 = extractvalue { i64, %Shape* } %29, 0
+	%32 = icmp ult i64 %30, %31
+	br i1 %32, label %index.ok.1, label %index.fail.1
+
+index.fail:
+	%33 = call i32 @fflush(i8* null)
+	%34 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %20, i64 %21)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.1:
+	%35 = extractvalue { i64, %Shape* } %29, 1
+	%36 = getelementptr %Shape, %Shape* %35, i64 %30
+	%37 = load %Shape, %Shape* %36
+	%38 = call i1 @isEmpty(%Shape %37)
+	%39 = xor i1 true, %38
+	br i1 %39, label %if.then, label %if.merge
+
+index.fail.1:
+	%40 = call i32 @fflush(i8* null)
+	%41 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 %30, i64 %31)
+	call void @llvm.trap()
+	unreachable
+
+if.then:
+	%42 = load i64, i64* %1
+	%43 = add i64 %42, 1
+	store i64 %43, i64* %1
+	br label %if.merge
+
+if.merge:
+	br label %for.latch
+
+for.latch:
+	%44 = load i64, i64* %2
+	%45 = add i64 %44, 1
+	store i64 %45, i64* %2
+	br label %for.header
+
+for.exit:
+	%46 = load i64, i64* %1
+	%47 = mul i64 %46, 10
+	%48 = insertvalue %Shape undef, i64 1, 0
+	%49 = insertvalue %Shape %48, i64 1, 2
+	%50 = insertvalue %Shape %49, i64 5, 3
+	%51 = call i64 @area(%Shape %50)
+	%52 = add i64 %47, %51
+	ret i64 %52
+}
+
+declare i8* @malloc(i64 %size)
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
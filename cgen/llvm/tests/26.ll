@@ -0,0 +1,104 @@
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.2 = private unnamed_addr constant [27 x i8] c"Negative exponent [%lld].\0A\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = call i64 @rem.i64(i64 17, i64 5)
+	%2 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %1)
+	%3 = sext i32 %2 to i64
+	%4 = sub i64 0, 17
+	%5 = call i64 @rem.i64(i64 %4, i64 5)
+	%6 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %5)
+	%7 = sext i32 %6 to i64
+	%8 = call i64 @pow.i64(i64 2, i64 10)
+	%9 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %8)
+	%10 = sext i32 %9 to i64
+	%11 = call i64 @pow.i64(i64 3, i64 2)
+	%12 = call i64 @pow.i64(i64 2, i64 %11)
+	%13 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %12)
+	%14 = sext i32 %13 to i64
+	%15 = call i64 @pow.i64(i64 3, i64 3)
+	%16 = sub i64 0, %15
+	%17 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %16)
+	%18 = sext i32 %17 to i64
+	%19 = and i64 12, 10
+	%20 = xor i64 -1, %19
+	%21 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %20)
+	%22 = sext i32 %21 to i64
+	%23 = or i64 12, 10
+	%24 = xor i64 -1, %23
+	%25 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %24)
+	%26 = sext i32 %25 to i64
+	%27 = xor i64 12, 10
+	%28 = xor i64 -1, %27
+	%29 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %28)
+	%30 = sext i32 %29 to i64
+	store i64 7, i64* %0
+	%31 = load i64, i64* %0
+	%32 = call i64 @pow.i64(i64 %31, i64 2)
+	%33 = call i64 @rem.i64(i64 %32, i64 10)
+	ret i64 %33
+}
+
+define i64 @rem.i64(i64 %a, i64 %b) {
+rem.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	ret i64 0
+
+div:
+	%2 = srem i64 %a, %b
+	ret i64 %2
+
+fail:
+	%3 = call i32 @fflush(i8* null)
+	%4 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
+
+define i64 @pow.i64(i64 %b, i64 %e) {
+pow.i64.entry:
+	%0 = icmp slt i64 %e, 0
+	br i1 %0, label %fail, label %loop
+
+loop:
+	%1 = phi i64 [ 1, %pow.i64.entry ], [ %7, %body ]
+	%2 = phi i64 [ %b, %pow.i64.entry ], [ %8, %body ]
+	%3 = phi i64 [ %e, %pow.i64.entry ], [ %9, %body ]
+	%4 = icmp sgt i64 %3, 0
+	br i1 %4, label %body, label %exit
+
+body:
+	%5 = trunc i64 %3 to i1
+	%6 = mul i64 %1, %2
+	%7 = select i1 %5, i64 %6, i64 %1
+	%8 = mul i64 %2, %2
+	%9 = ashr i64 %3, 1
+	br label %loop
+
+exit:
+	ret i64 %1
+
+fail:
+	%10 = call i32 @fflush(i8* null)
+	%11 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([27 x i8], [27 x i8]* @.str.2, i64 0, i64 0), i64 %e)
+	call void @llvm.trap()
+	unreachable
+}
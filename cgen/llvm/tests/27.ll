@@ -0,0 +1,219 @@
+%Acc = type { i64, i64 }
+
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.2 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.3 = private unnamed_addr constant [5 x i8] c"12:5\00"
+@.str.4 = private unnamed_addr constant [6 x i8] c"14:11\00"
+@.str.5 = private unnamed_addr constant [6 x i8] c"16:14\00"
+@.str.6 = private unnamed_addr constant [6 x i8] c"16:22\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca { i64, i64* }
+	%2 = alloca i64
+	%3 = alloca %Acc
+	%4 = alloca i64
+	%5 = alloca i64
+	store i64 5, i64* %0
+	%6 = load i64, i64* %0
+	%7 = add i64 %6, 3
+	store i64 %7, i64* %0
+	%8 = load i64, i64* %0
+	%9 = mul i64 %8, 4
+	store i64 %9, i64* %0
+	%10 = load i64, i64* %0
+	%11 = sub i64 %10, 2
+	store i64 %11, i64* %0
+	%12 = load i64, i64* %0
+	%13 = call i64 @div.i64(i64 %12, i64 3)
+	store i64 %13, i64* %0
+	%14 = load i64, i64* %0
+	%15 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %14)
+	%16 = sext i32 %15 to i64
+	%17 = call i8* @malloc(i64 mul (i64 3, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%18 = bitcast i8* %17 to i64*
+	%19 = getelementptr i64, i64* %18, i64 0
+	store i64 1, i64* %19
+	%20 = getelementptr i64, i64* %18, i64 1
+	store i64 2, i64* %20
+	%21 = getelementptr i64, i64* %18, i64 2
+	store i64 3, i64* %21
+	%22 = insertvalue { i64, i64* } undef, i64 3, 0
+	%23 = insertvalue { i64, i64* } %22, i64* %18, 1
+	store { i64, i64* } %23, { i64, i64* }* %1
+	store i64 0, i64* %2
+	br label %for.header
+
+for.header:
+	%24 = load i64, i64* %2
+	%25 = load { i64, i64* }, { i64, i64* }* %1
+	%26 = extractvalue { i64, i64* } %25, 0
+	%27 = icmp slt i64 %24, %26
+	br i1 %27, label %for.body, label %for.exit
+
+for.body:
+	%28 = load i64, i64* %2
+	%29 = load { i64, i64* }, { i64, i64* }* %1
+	%30 = load i64, i64* %2
+	%31 = extractvalue { i64, i64* } %29, 0
+	%32 = icmp ult i64 %30, %31
+	br i1 %32, label %index.ok, label %index.fail
+
+index.ok:
+	%33 = extractvalue { i64, i64* } %29, 1
+	%34 = getelementptr i64, i64* %33, i64 %30
+	%35 = load i64, i64* %34
+	%36 = shl i64 %35, %28
+	store i64 %36, i64* %34
+	br label %for.latch
+
+index.fail:
+	%37 = call i32 @fflush(i8* null)
+	%38 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.3, i64 0, i64 0), i64 %30, i64 %31)
+	call void @llvm.trap()
+	unreachable
+
+for.latch:
+	%39 = load i64, i64* %2
+	%40 = add i64 %39, 1
+	store i64 %40, i64* %2
+	br label %for.header
+
+for.exit:
+	%41 = load { i64, i64* }, { i64, i64* }* %1
+	%42 = extractvalue { i64, i64* } %41, 0
+	%43 = icmp ult i64 2, %42
+	br i1 %43, label %index.ok.1, label %index.fail.1
+
+index.ok.1:
+	%44 = extractvalue { i64, i64* } %41, 1
+	%45 = getelementptr i64, i64* %44, i64 2
+	%46 = load i64, i64* %45
+	%47 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %46)
+	%48 = sext i32 %47 to i64
+	%49 = insertvalue %Acc undef, i64 0, 0
+	%50 = insertvalue %Acc %49, i64 240, 1
+	store %Acc %50, %Acc* %3
+	%51 = load { i64, i64* }, { i64, i64* }* %1
+	%52 = extractvalue { i64, i64* } %51, 0
+	%53 = icmp ult i64 0, %52
+	br i1 %53, label %index.ok.2, label %index.fail.2
+
+index.fail.1:
+	%54 = call i32 @fflush(i8* null)
+	%55 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.4, i64 0, i64 0), i64 2, i64 %42)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.2:
+	%56 = extractvalue { i64, i64* } %51, 1
+	%57 = getelementptr i64, i64* %56, i64 0
+	%58 = load i64, i64* %57
+	%59 = load { i64, i64* }, { i64, i64* }* %1
+	%60 = extractvalue { i64, i64* } %59, 0
+	%61 = icmp ult i64 1, %60
+	br i1 %61, label %index.ok.3, label %index.fail.3
+
+index.fail.2:
+	%62 = call i32 @fflush(i8* null)
+	%63 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 0, i64 %52)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.3:
+	%64 = extractvalue { i64, i64* } %59, 1
+	%65 = getelementptr i64, i64* %64, i64 1
+	%66 = load i64, i64* %65
+	%67 = add i64 %58, %66
+	%68 = getelementptr %Acc, %Acc* %3, i32 0, i32 0
+	%69 = load i64, i64* %68
+	%70 = add i64 %69, %67
+	store i64 %70, i64* %68
+	%71 = getelementptr %Acc, %Acc* %3, i32 0, i32 1
+	%72 = load i64, i64* %71
+	%73 = lshr i64 %72, 4
+	store i64 %73, i64* %71
+	%74 = getelementptr %Acc, %Acc* %3, i32 0, i32 1
+	%75 = load i64, i64* %74
+	%76 = or i64 %75, 256
+	store i64 %76, i64* %74
+	%77 = getelementptr %Acc, %Acc* %3, i32 0, i32 1
+	%78 = load i64, i64* %77
+	%79 = xor i64 %78, 1
+	store i64 %79, i64* %77
+	%80 = getelementptr %Acc, %Acc* %3, i32 0, i32 1
+	%81 = load i64, i64* %80
+	%82 = and i64 %81, 271
+	store i64 %82, i64* %80
+	%83 = load %Acc, %Acc* %3
+	%84 = extractvalue %Acc %83, 1
+	%85 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %84)
+	%86 = sext i32 %85 to i64
+	store i64 1, i64* %4
+	%87 = load i64, i64* %4
+	%88 = lshr i64 %87, 1
+	%89 = sub i64 64, 1
+	%90 = shl i64 %87, %89
+	%91 = or i64 %88, %90
+	store i64 %91, i64* %4
+	%92 = load i64, i64* %4
+	%93 = shl i64 %92, 2
+	%94 = sub i64 64, 2
+	%95 = lshr i64 %92, %94
+	%96 = or i64 %93, %95
+	store i64 %96, i64* %4
+	%97 = sub i64 0, 64
+	store i64 %97, i64* %5
+	%98 = load i64, i64* %5
+	%99 = ashr i64 %98, 3
+	store i64 %99, i64* %5
+	%100 = load %Acc, %Acc* %3
+	%101 = extractvalue %Acc %100, 0
+	%102 = load i64, i64* %4
+	%103 = add i64 %101, %102
+	%104 = load i64, i64* %5
+	%105 = add i64 %103, %104
+	ret i64 %105
+
+index.fail.3:
+	%106 = call i32 @fflush(i8* null)
+	%107 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.6, i64 0, i64 0), i64 1, i64 %60)
+	call void @llvm.trap()
+	unreachable
+}
+
+define i64 @div.i64(i64 %a, i64 %b) {
+div.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	%2 = sub i64 0, %a
+	ret i64 %2
+
+div:
+	%3 = sdiv i64 %a, %b
+	ret i64 %3
+
+fail:
+	%4 = call i32 @fflush(i8* null)
+	%5 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
+
+declare i8* @malloc(i64 %size)
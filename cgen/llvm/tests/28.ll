@@ -0,0 +1,202 @@
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"%llu\0A\00"
+@.str.2 = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"false\00"
+@.str.4 = private unnamed_addr constant [4 x i8] c"%s\0A\00"
+@.str.5 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.6 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.7 = private unnamed_addr constant [6 x i8] c"30:11\00"
+
+define i8 @avg(i8 %a, i8 %b) {
+avg.entry:
+	%0 = alloca i8
+	%1 = alloca i8
+	store i8 %a, i8* %0
+	store i8 %b, i8* %1
+	%2 = load i8, i8* %0
+	%3 = call i8 @div.u8(i8 %2, i8 2)
+	%4 = load i8, i8* %1
+	%5 = call i8 @div.u8(i8 %4, i8 2)
+	%6 = add i8 %3, %5
+	ret i8 %6
+}
+
+define i32 @clamp(i32 %x, i32 %lo, i32 %hi) {
+clamp.entry:
+	%0 = alloca i32
+	%1 = alloca i32
+	%2 = alloca i32
+	store i32 %x, i32* %0
+	store i32 %lo, i32* %1
+	store i32 %hi, i32* %2
+	%3 = load i32, i32* %0
+	%4 = load i32, i32* %1
+	%5 = icmp slt i32 %3, %4
+	br i1 %5, label %if.then, label %if.merge
+
+if.then:
+	%6 = load i32, i32* %1
+	ret i32 %6
+
+if.merge:
+	%7 = load i32, i32* %0
+	%8 = load i32, i32* %2
+	%9 = icmp sgt i32 %7, %8
+	br i1 %9, label %if.then.1, label %if.merge.1
+
+if.then.1:
+	%10 = load i32, i32* %2
+	ret i32 %10
+
+if.merge.1:
+	%11 = load i32, i32* %0
+	ret i32 %11
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i8
+	%1 = alloca i8
+	%2 = alloca i8
+	%3 = alloca i8
+	%4 = alloca i64
+	%5 = alloca i16
+	%6 = alloca { i64, i8* }
+	store i8 200, i8* %0
+	store i8 250, i8* %1
+	%7 = load i8, i8* %0
+	%8 = load i8, i8* %1
+	%9 = call i8 @avg(i8 %7, i8 %8)
+	%10 = zext i8 %9 to i64
+	%11 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %10)
+	%12 = sext i32 %11 to i64
+	%13 = load i8, i8* %0
+	%14 = icmp ugt i8 %13, 100
+	%15 = select i1 %14, i8* getelementptr ([5 x i8], [5 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0)
+	%16 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.4, i64 0, i64 0), i8* %15)
+	%17 = sext i32 %16 to i64
+	%18 = sub i8 0, 128
+	store i8 %18, i8* %2
+	%19 = load i8, i8* %2
+	%20 = ashr i8 %19, 1
+	%21 = sext i8 %20 to i64
+	%22 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 %21)
+	%23 = sext i32 %22 to i64
+	store i8 128, i8* %3
+	%24 = load i8, i8* %3
+	%25 = lshr i8 %24, 1
+	%26 = zext i8 %25 to i64
+	%27 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %26)
+	%28 = sext i32 %27 to i64
+	%29 = load i8, i8* %2
+	%30 = zext i8 %29 to i64
+	%31 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %30)
+	%32 = sext i32 %31 to i64
+	%33 = load i8, i8* %3
+	%34 = zext i8 %33 to i64
+	%35 = add i64 %34, 1000
+	%36 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 %35)
+	%37 = sext i32 %36 to i64
+	%38 = sub i32 0, 5
+	%39 = call i32 @clamp(i32 %38, i32 0, i32 10)
+	%40 = sext i32 %39 to i64
+	%41 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.5, i64 0, i64 0), i64 %40)
+	%42 = sext i32 %41 to i64
+	%43 = sub i64 0, 1
+	store i64 %43, i64* %4
+	%44 = load i64, i64* %4
+	%45 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %44)
+	%46 = sext i32 %45 to i64
+	store i16 3, i16* %5
+	%47 = load i16, i16* %5
+	%48 = call i16 @pow.u16(i16 %47, i16 4)
+	store i16 %48, i16* %5
+	%49 = load i16, i16* %5
+	%50 = zext i16 %49 to i64
+	%51 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %50)
+	%52 = sext i32 %51 to i64
+	%53 = trunc i64 300 to i8
+	%54 = zext i8 %53 to i64
+	%55 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %54)
+	%56 = sext i32 %55 to i64
+	%57 = call i8* @malloc(i64 mul (i64 3, i64 ptrtoint (i8* getelementptr (i8, i8* null, i32 1) to i64)))
+	%58 = bitcast i8* %57 to i8*
+	%59 = getelementptr i8, i8* %58, i64 0
+	store i8 1, i8* %59
+	%60 = getelementptr i8, i8* %58, i64 1
+	store i8 2, i8* %60
+	%61 = getelementptr i8, i8* %58, i64 2
+	store i8 255, i8* %61
+	%62 = insertvalue { i64, i8* } undef, i64 3, 0
+	%63 = insertvalue { i64, i8* } %62, i8* %58, 1
+	store { i64, i8* } %63, { i64, i8* }* %6
+	%64 = load { i64, i8* }, { i64, i8* }* %6
+	%65 = extractvalue { i64, i8* } %64, 0
+	%66 = icmp ult i64 2, %65
+	br i1 %66, label %index.ok, label %index.fail
+
+index.ok:
+	%67 = extractvalue { i64, i8* } %64, 1
+	%68 = getelementptr i8, i8* %67, i64 2
+	%69 = load i8, i8* %68
+	%70 = add i8 %69, 1
+	%71 = zext i8 %70 to i64
+	%72 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %71)
+	%73 = sext i32 %72 to i64
+	ret i64 0
+
+index.fail:
+	%74 = call i32 @fflush(i8* null)
+	%75 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.6, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.7, i64 0, i64 0), i64 2, i64 %65)
+	call void @llvm.trap()
+	unreachable
+}
+
+define i8 @div.u8(i8 %a, i8 %b) {
+div.u8.entry:
+	%0 = icmp eq i8 %b, 0
+	br i1 %0, label %fail, label %div
+
+div:
+	%1 = udiv i8 %a, %b
+	ret i8 %1
+
+fail:
+	%2 = call i32 @fflush(i8* null)
+	%3 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
+
+define i16 @pow.u16(i16 %b, i16 %e) {
+pow.u16.entry:
+	br label %loop
+
+loop:
+	%0 = phi i16 [ 1, %pow.u16.entry ], [ %6, %body ]
+	%1 = phi i16 [ %b, %pow.u16.entry ], [ %7, %body ]
+	%2 = phi i16 [ %e, %pow.u16.entry ], [ %8, %body ]
+	%3 = icmp ugt i16 %2, 0
+	br i1 %3, label %body, label %exit
+
+body:
+	%4 = trunc i16 %2 to i1
+	%5 = mul i16 %0, %1
+	%6 = select i1 %4, i16 %5, i16 %0
+	%7 = mul i16 %1, %1
+	%8 = lshr i16 %2, 1
+	br label %loop
+
+exit:
+	ret i16 %0
+}
+
+declare i8* @malloc(i64 %size)
@@ -0,0 +1,179 @@
+@.str.0 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.1 = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"false\00"
+@.str.3 = private unnamed_addr constant [4 x i8] c"%s\0A\00"
+@.str.4 = private unnamed_addr constant [6 x i8] c"%llu\0A\00"
+@.str.5 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.6 = private unnamed_addr constant [5 x i8] c"6:10\00"
+
+define i1 @even(i64 %n) {
+even.entry:
+	%0 = alloca i64
+	store i64 %n, i64* %0
+	%1 = load i64, i64* %0
+	%2 = icmp eq i64 %1, 0
+	br i1 %2, label %if.then, label %if.merge
+
+if.then:
+	ret i1 true
+
+if.merge:
+	%3 = load i64, i64* %0
+	%4 = sub i64 %3, 1
+	%5 = call i1 @odd(i64 %4)
+	ret i1 %5
+}
+
+define i1 @odd(i64 %n) {
+odd.entry:
+	%0 = alloca i64
+	store i64 %n, i64* %0
+	%1 = load i64, i64* %0
+	%2 = icmp eq i64 %1, 0
+	br i1 %2, label %if.then, label %if.merge
+
+if.then:
+	ret i1 false
+
+if.merge:
+	%3 = load i64, i64* %0
+	%4 = sub i64 %3, 1
+	%5 = call i1 @even(i64 %4)
+	ret i1 %5
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i8
+	%1 = call i64 @id.int(i64 42)
+	%2 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %1)
+	%3 = sext i32 %2 to i64
+	%4 = call i1 @id.bool(i1 true)
+	%5 = select i1 %4, i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0)
+	%6 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.3, i64 0, i64 0), i8* %5)
+	%7 = sext i32 %6 to i64
+	store i8 200, i8* %0
+	%8 = load i8, i8* %0
+	%9 = call i8 @id.u8(i8 %8)
+	%10 = add i8 %9, 100
+	%11 = zext i8 %10 to i64
+	%12 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.4, i64 0, i64 0), i64 %11)
+	%13 = sext i32 %12 to i64
+	%14 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%15 = bitcast i8* %14 to i64*
+	%16 = getelementptr i64, i64* %15, i64 0
+	store i64 7, i64* %16
+	%17 = getelementptr i64, i64* %15, i64 1
+	store i64 8, i64* %17
+	%18 = insertvalue { i64, i64* } undef, i64 2, 0
+	%19 = insertvalue { i64, i64* } %18, i64* %15, 1
+	%20 = call i64 @first.int({ i64, i64* } %19)
+	%21 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %20)
+	%22 = sext i32 %21 to i64
+	%23 = call { i64, i1* } @pair.int.bool(i64 1, i1 false)
+	%24 = extractvalue { i64, i1* } %23, 0
+	%25 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %24)
+	%26 = sext i32 %25 to i64
+	%27 = insertvalue { i64 (i8*, i64)*, i8* } undef, i64 (i8*, i64)* @id.int.closure, 0
+	%28 = insertvalue { i64 (i8*, i64)*, i8* } %27, i8* null, 1
+	%29 = call { i64 (i8*, i64)*, i8* } @"id.fn(int) -> int"({ i64 (i8*, i64)*, i8* } %28)
+	%30 = extractvalue { i64 (i8*, i64)*, i8* } %29, 0
+	%31 = extractvalue { i64 (i8*, i64)*, i8* } %29, 1
+	%32 = call i64 %30(i8* %31, i64 3)
+	%33 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.0, i64 0, i64 0), i64 %32)
+	%34 = sext i32 %33 to i64
+	%35 = call i1 @even(i64 10)
+	%36 = select i1 %35, i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0)
+	%37 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.3, i64 0, i64 0), i8* %36)
+	%38 = sext i32 %37 to i64
+	ret i64 0
+}
+
+define i64 @id.int(i64 %x) {
+id.int.entry:
+	%0 = alloca i64
+	store i64 %x, i64* %0
+	%1 = load i64, i64* %0
+	ret i64 %1
+}
+
+declare i32 @printf(i8* %format, ...)
+
+define i1 @id.bool(i1 %x) {
+id.bool.entry:
+	%0 = alloca i1
+	store i1 %x, i1* %0
+	%1 = load i1, i1* %0
+	ret i1 %1
+}
+
+define i8 @id.u8(i8 %x) {
+id.u8.entry:
+	%0 = alloca i8
+	store i8 %x, i8* %0
+	%1 = load i8, i8* %0
+	ret i8 %1
+}
+
+define i64 @first.int({ i64, i64* } %a) {
+first.int.entry:
+	%0 = alloca { i64, i64* }
+	store { i64, i64* } %a, { i64, i64* }* %0
+	%1 = load { i64, i64* }, { i64, i64* }* %0
+	%2 = extractvalue { i64, i64* } %1, 0
+	%3 = icmp ult i64 0, %2
+	br i1 %3, label %index.ok, label %index.fail
+
+index.ok:
+	%4 = extractvalue { i64, i64* } %1, 1
+	%5 = getelementptr i64, i64* %4, i64 0
+	%6 = load i64, i64* %5
+	ret i64 %6
+
+index.fail:
+	%7 = call i32 @fflush(i8* null)
+	%8 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.5, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.6, i64 0, i64 0), i64 0, i64 %2)
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i8* @malloc(i64 %size)
+
+define { i64, i1* } @pair.int.bool(i64 %a, i1 %b) {
+pair.int.bool.entry:
+	%0 = alloca i64
+	%1 = alloca i1
+	store i64 %a, i64* %0
+	store i1 %b, i1* %1
+	%2 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint (i1* getelementptr (i1, i1* null, i32 1) to i64)))
+	%3 = bitcast i8* %2 to i1*
+	%4 = load i1, i1* %1
+	%5 = getelementptr i1, i1* %3, i64 0
+	store i1 %4, i1* %5
+	%6 = load i1, i1* %1
+	%7 = getelementptr i1, i1* %3, i64 1
+	store i1 %6, i1* %7
+	%8 = insertvalue { i64, i1* } undef, i64 2, 0
+	%9 = insertvalue { i64, i1* } %8, i1* %3, 1
+	ret { i64, i1* } %9
+}
+
+define { i64 (i8*, i64)*, i8* } @"id.fn(int) -> int"({ i64 (i8*, i64)*, i8* } %x) {
+"id.fn(int) -> int.entry":
+	%0 = alloca { i64 (i8*, i64)*, i8* }
+	store { i64 (i8*, i64)*, i8* } %x, { i64 (i8*, i64)*, i8* }* %0
+	%1 = load { i64 (i8*, i64)*, i8* }, { i64 (i8*, i64)*, i8* }* %0
+	ret { i64 (i8*, i64)*, i8* } %1
+}
+
+define i64 @id.int.closure(i8* %env, i64 %x) {
+id.int.closure.entry:
+	%0 = call i64 @id.int(i64 %x)
+	ret i64 %0
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
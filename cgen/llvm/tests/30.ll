@@ -0,0 +1,95 @@
+@.str.0 = private unnamed_addr constant [4 x i8] c"%g\0A\00"
+@.str.1 = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"false\00"
+@.str.3 = private unnamed_addr constant [4 x i8] c"%s\0A\00"
+@.str.4 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+define double @area(double %r) {
+area.entry:
+	%0 = alloca double
+	store double %r, double* %0
+	%1 = load double, double* %0
+	%2 = call double @llvm.pow.f64(double %1, double 2.0)
+	%3 = fmul double 0x400921F9F01B866E, %2
+	ret double %3
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca float
+	%1 = alloca double
+	%2 = alloca double
+	%3 = alloca i32
+	%4 = alloca float
+	store float 1.5, float* %0
+	store double 22.5, double* %1
+	%5 = call double @area(double 2.0)
+	%6 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %5)
+	%7 = sext i32 %6 to i64
+	%8 = load float, float* %0
+	%9 = fmul float %8, 0x3FB9999980000000
+	%10 = fpext float %9 to double
+	%11 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %10)
+	%12 = sext i32 %11 to i64
+	%13 = load double, double* %1
+	%14 = fdiv double %13, 0.0
+	%15 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %14)
+	%16 = sext i32 %15 to i64
+	%17 = fdiv double 0.0, 0.0
+	store double %17, double* %2
+	%18 = load double, double* %2
+	%19 = load double, double* %2
+	%20 = fcmp oeq double %18, %19
+	%21 = select i1 %20, i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0)
+	%22 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.3, i64 0, i64 0), i8* %21)
+	%23 = sext i32 %22 to i64
+	%24 = load double, double* %2
+	%25 = load double, double* %2
+	%26 = fcmp une double %24, %25
+	%27 = select i1 %26, i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0)
+	%28 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.3, i64 0, i64 0), i8* %27)
+	%29 = sext i32 %28 to i64
+	%30 = load double, double* %1
+	%31 = fneg double %30
+	%32 = frem double %31, 4.0
+	%33 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %32)
+	%34 = sext i32 %33 to i64
+	%35 = fneg double 0x400599999999999A
+	%36 = fptosi double %35 to i64
+	%37 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.4, i64 0, i64 0), i64 %36)
+	%38 = sext i32 %37 to i64
+	%39 = sitofp i64 7 to double
+	%40 = fdiv double %39, 2.0
+	%41 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %40)
+	%42 = sext i32 %41 to i64
+	store i32 4000000000, i32* %3
+	%43 = load i32, i32* %3
+	%44 = uitofp i32 %43 to double
+	%45 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %44)
+	%46 = sext i32 %45 to i64
+	%47 = fptrunc double 0x3FB999999999999A to float
+	%48 = fptrunc double 0x3FB999999999999A to float
+	%49 = fcmp oeq float %47, %48
+	%50 = select i1 %49, i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0)
+	%51 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.3, i64 0, i64 0), i8* %50)
+	%52 = sext i32 %51 to i64
+	%53 = fptrunc double 0x3FB999999999999A to float
+	%54 = fpext float %53 to double
+	%55 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %54)
+	%56 = sext i32 %55 to i64
+	%57 = load float, float* %0
+	store float %57, float* %4
+	%58 = load float, float* %4
+	%59 = fadd float %58, 1.0
+	store float %59, float* %4
+	%60 = load float, float* %4
+	%61 = fneg float %60
+	%62 = fpext float %61 to double
+	%63 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.0, i64 0, i64 0), double %62)
+	%64 = sext i32 %63 to i64
+	ret i64 0
+}
+
+declare double @llvm.pow.f64(double %b, double %e)
+
+declare i32 @printf(i8* %format, ...)
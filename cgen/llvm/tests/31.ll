@@ -0,0 +1,40 @@
+@.str.0 = private unnamed_addr constant [15 x i8] c"%d %s %.2f %c\0A\00"
+@.str.1 = private unnamed_addr constant [3 x i8] c"ok\00"
+@.str.2 = private unnamed_addr constant [4 x i8] c"%d\0A\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+declare i32 @putchar(i32 %c)
+
+declare i32 @printf(i8* %format, ...)
+
+declare i32 @abs(i32 %n)
+
+define i32 @square(i32 %x) {
+square.entry:
+	%0 = alloca i32
+	store i32 %x, i32* %0
+	%1 = load i32, i32* %0
+	%2 = load i32, i32* %0
+	%3 = mul i32 %1, %2
+	ret i32 %3
+}
+
+define i64 @main() {
+main.entry:
+	%0 = call i32 @putchar(i32 104)
+	%1 = call i32 @putchar(i32 105)
+	%2 = call i32 @putchar(i32 10)
+	%3 = sub i32 0, 7
+	%4 = call i32 @abs(i32 %3)
+	%5 = call i32 @square(i32 %4)
+	%6 = fptrunc double 1.25 to float
+	%7 = fpext float %6 to double
+	%8 = trunc i64 33 to i8
+	%9 = zext i8 %8 to i32
+	%10 = call i32 (i8*, ...) @printf(i8* getelementptr ([15 x i8], [15 x i8]* @.str.0, i64 0, i64 0), i32 %5, i8* getelementptr ([3 x i8], [3 x i8]* @.str.1, i64 0, i64 0), double %7, i32 %9)
+	%11 = zext i1 true to i32
+	%12 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0), i32 %11)
+	%13 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 42)
+	%14 = sext i32 %13 to i64
+	ret i64 0
+}
@@ -0,0 +1,102 @@
+%Tree = type { i64, { i64, %Tree* } }
+
+@.str.0 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.1 = private unnamed_addr constant [5 x i8] c"6:14\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+define i64 @sum(%Tree %t) {
+sum.entry:
+	%0 = alloca %Tree
+	%1 = alloca i64
+	%2 = alloca i64
+	store %Tree %t, %Tree* %0
+	%3 = load %Tree, %Tree* %0
+	%4 = extractvalue %Tree %3, 0
+	store i64 %4, i64* %1
+	store i64 0, i64* %2
+	br label %for.header
+
+for.header:
+	%5 = load i64, i64* %2
+	%6 = load %Tree, %Tree* %0
+	%7 = extractvalue %Tree %6, 1
+	%8 = extractvalue { i64, %Tree* } %7, 0
+	%9 = icmp slt i64 %5, %8
+	br i1 %9, label %for.body, label %for.exit
+
+for.body:
+	%10 = load %Tree, %Tree* %0
+	%11 = extractvalue %Tree %10, 1
+	%12 = load i64, i64* %2
+	%13 = extractvalue { i64, %Tree* } %11, 0
+	%14 = icmp ult i64 %12, %13
+	br i1 %14, label %index.ok, label %index.fail
+
+index.ok:
+	%15 = extractvalue { i64, %Tree* } %11, 1
+	%16 = getelementptr %Tree, %Tree* %15, i64 %12
+	%17 = load %Tree, %Tree* %16
+	%18 = call i64 @sum(%Tree %17)
+	%19 = load i64, i64* %1
+	%20 = add i64 %19, %18
+	store i64 %20, i64* %1
+	br label %for.latch
+
+index.fail:
+	%21 = call i32 @fflush(i8* null)
+	%22 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i64 %12, i64 %13)
+	call void @llvm.trap()
+	unreachable
+
+for.latch:
+	%23 = load i64, i64* %2
+	%24 = add i64 %23, 1
+	store i64 %24, i64* %2
+	br label %for.header
+
+for.exit:
+	%25 = load i64, i64* %1
+	ret i64 %25
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca %Tree
+	%1 = alloca %Tree
+	%2 = insertvalue %Tree undef, i64 2, 0
+	%3 = insertvalue { i64, %Tree* } undef, i64 0, 0
+	%4 = insertvalue { i64, %Tree* } %3, %Tree* null, 1
+	%5 = insertvalue %Tree %2, { i64, %Tree* } %4, 1
+	store %Tree %5, %Tree* %0
+	%6 = insertvalue %Tree undef, i64 1, 0
+	%7 = call i8* @malloc(i64 mul (i64 2, i64 ptrtoint (%Tree* getelementptr (%Tree, %Tree* null, i32 1) to i64)))
+	%8 = bitcast i8* %7 to %Tree*
+	%9 = load %Tree, %Tree* %0
+	%10 = getelementptr %Tree, %Tree* %8, i64 0
+	store %Tree %9, %Tree* %10
+	%11 = insertvalue %Tree undef, i64 2, 0
+	%12 = insertvalue { i64, %Tree* } undef, i64 0, 0
+	%13 = insertvalue { i64, %Tree* } %12, %Tree* null, 1
+	%14 = insertvalue %Tree %11, { i64, %Tree* } %13, 1
+	%15 = getelementptr %Tree, %Tree* %8, i64 1
+	store %Tree %14, %Tree* %15
+	%16 = insertvalue { i64, %Tree* } undef, i64 2, 0
+	%17 = insertvalue { i64, %Tree* } %16, %Tree* %8, 1
+	%18 = insertvalue %Tree %6, { i64, %Tree* } %17, 1
+	store %Tree %18, %Tree* %1
+	%19 = load %Tree, %Tree* %1
+	%20 = call i64 @sum(%Tree %19)
+	%21 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %20)
+	%22 = sext i32 %21 to i64
+	ret i64 0
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i8* @malloc(i64 %size)
+
+declare i32 @printf(i8* %format, ...)
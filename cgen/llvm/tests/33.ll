@@ -0,0 +1,155 @@
+%List = type { i64, i64, { i64, %List* } }
+
+@.str.0 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.1 = private unnamed_addr constant [5 x i8] c"6:30\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"13:29\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+define i64 @length(%List %l) {
+length.entry:
+	%0 = alloca %List
+	%1 = alloca i64
+	%2 = alloca { i64, %List* }
+	store %List %l, %List* %0
+	%3 = load %List, %List* %0
+	%4 = extractvalue %List %3, 0
+	switch i64 %4, label %match.default [
+		i64 0, label %match.arm
+		i64 1, label %match.arm.1
+	]
+
+match.arm:
+	br label %match.end
+
+match.arm.1:
+	%5 = extractvalue %List %3, 1
+	store i64 %5, i64* %1
+	%6 = extractvalue %List %3, 2
+	store { i64, %List* } %6, { i64, %List* }* %2
+	%7 = load { i64, %List* }, { i64, %List* }* %2
+	%8 = extractvalue { i64, %List* } %7, 0
+	%9 = icmp ult i64 0, %8
+	br i1 %9, label %index.ok, label %index.fail
+
+index.ok:
+	%10 = extractvalue { i64, %List* } %7, 1
+	%11 = getelementptr %List, %List* %10, i64 0
+	%12 = load %List, %List* %11
+	%13 = call i64 @length(%List %12)
+	%14 = add i64 1, %13
+	br label %match.end
+
+index.fail:
+	%15 = call i32 @fflush(i8* null)
+	%16 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.1, i64 0, i64 0), i64 0, i64 %8)
+	call void @llvm.trap()
+	unreachable
+
+match.default:
+	unreachable
+
+match.end:
+	%17 = phi i64 [ 0, %match.arm ], [ %14, %index.ok ]
+	ret i64 %17
+}
+
+define i64 @total(%List %l) {
+total.entry:
+	%0 = alloca %List
+	%1 = alloca i64
+	%2 = alloca { i64, %List* }
+	store %List %l, %List* %0
+	%3 = load %List, %List* %0
+	%4 = extractvalue %List %3, 0
+	switch i64 %4, label %match.default [
+		i64 0, label %match.arm
+		i64 1, label %match.arm.1
+	]
+
+match.arm:
+	br label %match.end
+
+match.arm.1:
+	%5 = extractvalue %List %3, 1
+	store i64 %5, i64* %1
+	%6 = extractvalue %List %3, 2
+	store { i64, %List* } %6, { i64, %List* }* %2
+	%7 = load i64, i64* %1
+	%8 = load { i64, %List* }, { i64, %List* }* %2
+	%9 = extractvalue { i64, %List* } %8, 0
+	%10 = icmp ult i64 0, %9
+	br i1 %10, label %index.ok, label %index.fail
+
+index.ok:
+	%11 = extractvalue { i64, %List* } %8, 1
+	%12 = getelementptr %List, %List* %11, i64 0
+	%13 = load %List, %List* %12
+	%14 = call i64 @total(%List %13)
+	%15 = add i64 %7, %14
+	br label %match.end
+
+index.fail:
+	%16 = call i32 @fflush(i8* null)
+	%17 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 0, i64 %9)
+	call void @llvm.trap()
+	unreachable
+
+match.default:
+	unreachable
+
+match.end:
+	%18 = phi i64 [ 0, %match.arm ], [ %15, %index.ok ]
+	ret i64 %18
+}
+
+define i64 @main() {
+main.entry:
+	%0 = alloca %List
+	%1 = insertvalue %List undef, i64 1, 0
+	%2 = insertvalue %List %1, i64 1, 1
+	%3 = call i8* @malloc(i64 mul (i64 1, i64 ptrtoint (%List* getelementptr (%List, %List* null, i32 1) to i64)))
+	%4 = bitcast i8* %3 to %List*
+	%5 = insertvalue %List undef, i64 1, 0
+	%6 = insertvalue %List %5, i64 2, 1
+	%7 = call i8* @malloc(i64 mul (i64 1, i64 ptrtoint (%List* getelementptr (%List, %List* null, i32 1) to i64)))
+	%8 = bitcast i8* %7 to %List*
+	%9 = insertvalue %List undef, i64 1, 0
+	%10 = insertvalue %List %9, i64 3, 1
+	%11 = call i8* @malloc(i64 mul (i64 1, i64 ptrtoint (%List* getelementptr (%List, %List* null, i32 1) to i64)))
+	%12 = bitcast i8* %11 to %List*
+	%13 = getelementptr %List, %List* %12, i64 0
+	store %List { i64 0, i64 undef, { i64, %List* } undef }, %List* %13
+	%14 = insertvalue { i64, %List* } undef, i64 1, 0
+	%15 = insertvalue { i64, %List* } %14, %List* %12, 1
+	%16 = insertvalue %List %10, { i64, %List* } %15, 2
+	%17 = getelementptr %List, %List* %8, i64 0
+	store %List %16, %List* %17
+	%18 = insertvalue { i64, %List* } undef, i64 1, 0
+	%19 = insertvalue { i64, %List* } %18, %List* %8, 1
+	%20 = insertvalue %List %6, { i64, %List* } %19, 2
+	%21 = getelementptr %List, %List* %4, i64 0
+	store %List %20, %List* %21
+	%22 = insertvalue { i64, %List* } undef, i64 1, 0
+	%23 = insertvalue { i64, %List* } %22, %List* %4, 1
+	%24 = insertvalue %List %2, { i64, %List* } %23, 2
+	store %List %24, %List* %0
+	%25 = load %List, %List* %0
+	%26 = call i64 @length(%List %25)
+	%27 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 %26)
+	%28 = sext i32 %27 to i64
+	%29 = load %List, %List* %0
+	%30 = call i64 @total(%List %29)
+	%31 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 %30)
+	%32 = sext i32 %31 to i64
+	ret i64 0
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i8* @malloc(i64 %size)
+
+declare i32 @printf(i8* %format, ...)
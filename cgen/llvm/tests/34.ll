@@ -0,0 +1,234 @@
+@.str.0 = private unnamed_addr constant [1 x i8] c"\00"
+@.str.1 = private unnamed_addr constant [2 x i8] c"-\00"
+@.str.2 = private unnamed_addr constant [22 x i8] c"%s%llu%019llu%019llu\0A\00"
+@.str.3 = private unnamed_addr constant [15 x i8] c"%s%llu%019llu\0A\00"
+@.str.4 = private unnamed_addr constant [8 x i8] c"%s%llu\0A\00"
+@.str.5 = private unnamed_addr constant [21 x i8] c"%s%llu%019llu%019llu\00"
+@.str.6 = private unnamed_addr constant [14 x i8] c"%s%llu%019llu\00"
+@.str.7 = private unnamed_addr constant [7 x i8] c"%s%llu\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i128
+	%1 = alloca i128
+	%2 = alloca i128
+	store i128 1, i128* %0
+	%3 = load i128, i128* %0
+	%4 = shl i128 %3, 100
+	store i128 %4, i128* %0
+	%5 = load i128, i128* %0
+	%6 = icmp slt i128 %5, 0
+	%7 = select i1 %6, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%8 = sub i128 0, %5
+	%9 = select i1 %6, i128 %8, i128 %5
+	%10 = udiv i128 %9, 10000000000000000000
+	%11 = udiv i128 %10, 10000000000000000000
+	%12 = trunc i128 %11 to i64
+	%13 = urem i128 %10, 10000000000000000000
+	%14 = trunc i128 %13 to i64
+	%15 = urem i128 %9, 10000000000000000000
+	%16 = trunc i128 %15 to i64
+	%17 = icmp ne i64 %12, 0
+	%18 = icmp ne i64 %14, 0
+	%19 = select i1 %18, i64 %14, i64 %16
+	%20 = select i1 %17, i64 %12, i64 %19
+	%21 = select i1 %17, i64 %14, i64 %16
+	%22 = select i1 %18, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%23 = select i1 %17, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %22
+	%24 = call i32 (i8*, ...) @printf(i8* %23, i8* %7, i64 %20, i64 %21, i64 %16)
+	%25 = sext i32 %24 to i64
+	%26 = load i128, i128* %0
+	%27 = sub i128 0, %26
+	%28 = icmp slt i128 %27, 0
+	%29 = select i1 %28, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%30 = sub i128 0, %27
+	%31 = select i1 %28, i128 %30, i128 %27
+	%32 = udiv i128 %31, 10000000000000000000
+	%33 = udiv i128 %32, 10000000000000000000
+	%34 = trunc i128 %33 to i64
+	%35 = urem i128 %32, 10000000000000000000
+	%36 = trunc i128 %35 to i64
+	%37 = urem i128 %31, 10000000000000000000
+	%38 = trunc i128 %37 to i64
+	%39 = icmp ne i64 %34, 0
+	%40 = icmp ne i64 %36, 0
+	%41 = select i1 %40, i64 %36, i64 %38
+	%42 = select i1 %39, i64 %34, i64 %41
+	%43 = select i1 %39, i64 %36, i64 %38
+	%44 = select i1 %40, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%45 = select i1 %39, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %44
+	%46 = call i32 (i8*, ...) @printf(i8* %45, i8* %29, i64 %42, i64 %43, i64 %38)
+	%47 = sext i32 %46 to i64
+	store i128 0, i128* %1
+	%48 = load i128, i128* %1
+	%49 = xor i128 -1, %48
+	store i128 %49, i128* %1
+	%50 = load i128, i128* %1
+	%51 = udiv i128 %50, 10000000000000000000
+	%52 = udiv i128 %51, 10000000000000000000
+	%53 = trunc i128 %52 to i64
+	%54 = urem i128 %51, 10000000000000000000
+	%55 = trunc i128 %54 to i64
+	%56 = urem i128 %50, 10000000000000000000
+	%57 = trunc i128 %56 to i64
+	%58 = icmp ne i64 %53, 0
+	%59 = icmp ne i64 %55, 0
+	%60 = select i1 %59, i64 %55, i64 %57
+	%61 = select i1 %58, i64 %53, i64 %60
+	%62 = select i1 %58, i64 %55, i64 %57
+	%63 = select i1 %59, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%64 = select i1 %58, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %63
+	%65 = call i32 (i8*, ...) @printf(i8* %64, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %61, i64 %62, i64 %57)
+	%66 = sext i32 %65 to i64
+	store i128 1, i128* %2
+	%67 = load i128, i128* %2
+	%68 = shl i128 %67, 127
+	store i128 %68, i128* %2
+	%69 = load i128, i128* %2
+	%70 = icmp slt i128 %69, 0
+	%71 = select i1 %70, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%72 = sub i128 0, %69
+	%73 = select i1 %70, i128 %72, i128 %69
+	%74 = udiv i128 %73, 10000000000000000000
+	%75 = udiv i128 %74, 10000000000000000000
+	%76 = trunc i128 %75 to i64
+	%77 = urem i128 %74, 10000000000000000000
+	%78 = trunc i128 %77 to i64
+	%79 = urem i128 %73, 10000000000000000000
+	%80 = trunc i128 %79 to i64
+	%81 = icmp ne i64 %76, 0
+	%82 = icmp ne i64 %78, 0
+	%83 = select i1 %82, i64 %78, i64 %80
+	%84 = select i1 %81, i64 %76, i64 %83
+	%85 = select i1 %81, i64 %78, i64 %80
+	%86 = select i1 %82, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%87 = select i1 %81, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %86
+	%88 = call i32 (i8*, ...) @printf(i8* %87, i8* %71, i64 %84, i64 %85, i64 %80)
+	%89 = sext i32 %88 to i64
+	%90 = load i128, i128* %2
+	%91 = sext i64 1 to i128
+	%92 = sub i128 %90, %91
+	%93 = icmp slt i128 %92, 0
+	%94 = select i1 %93, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%95 = sub i128 0, %92
+	%96 = select i1 %93, i128 %95, i128 %92
+	%97 = udiv i128 %96, 10000000000000000000
+	%98 = udiv i128 %97, 10000000000000000000
+	%99 = trunc i128 %98 to i64
+	%100 = urem i128 %97, 10000000000000000000
+	%101 = trunc i128 %100 to i64
+	%102 = urem i128 %96, 10000000000000000000
+	%103 = trunc i128 %102 to i64
+	%104 = icmp ne i64 %99, 0
+	%105 = icmp ne i64 %101, 0
+	%106 = select i1 %105, i64 %101, i64 %103
+	%107 = select i1 %104, i64 %99, i64 %106
+	%108 = select i1 %104, i64 %101, i64 %103
+	%109 = select i1 %105, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%110 = select i1 %104, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %109
+	%111 = call i32 (i8*, ...) @printf(i8* %110, i8* %94, i64 %107, i64 %108, i64 %103)
+	%112 = sext i32 %111 to i64
+	%113 = sub i64 0, 5
+	%114 = sext i64 %113 to i128
+	%115 = icmp slt i128 %114, 0
+	%116 = select i1 %115, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%117 = sub i128 0, %114
+	%118 = select i1 %115, i128 %117, i128 %114
+	%119 = udiv i128 %118, 10000000000000000000
+	%120 = udiv i128 %119, 10000000000000000000
+	%121 = trunc i128 %120 to i64
+	%122 = urem i128 %119, 10000000000000000000
+	%123 = trunc i128 %122 to i64
+	%124 = urem i128 %118, 10000000000000000000
+	%125 = trunc i128 %124 to i64
+	%126 = icmp ne i64 %121, 0
+	%127 = icmp ne i64 %123, 0
+	%128 = select i1 %127, i64 %123, i64 %125
+	%129 = select i1 %126, i64 %121, i64 %128
+	%130 = select i1 %126, i64 %123, i64 %125
+	%131 = select i1 %127, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%132 = select i1 %126, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %131
+	%133 = call i32 (i8*, ...) @printf(i8* %132, i8* %116, i64 %129, i64 %130, i64 %125)
+	%134 = sext i32 %133 to i64
+	%135 = sext i64 0 to i128
+	%136 = udiv i128 %135, 10000000000000000000
+	%137 = udiv i128 %136, 10000000000000000000
+	%138 = trunc i128 %137 to i64
+	%139 = urem i128 %136, 10000000000000000000
+	%140 = trunc i128 %139 to i64
+	%141 = urem i128 %135, 10000000000000000000
+	%142 = trunc i128 %141 to i64
+	%143 = icmp ne i64 %138, 0
+	%144 = icmp ne i64 %140, 0
+	%145 = select i1 %144, i64 %140, i64 %142
+	%146 = select i1 %143, i64 %138, i64 %145
+	%147 = select i1 %143, i64 %140, i64 %142
+	%148 = select i1 %144, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%149 = select i1 %143, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %148
+	%150 = call i32 (i8*, ...) @printf(i8* %149, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %146, i64 %147, i64 %142)
+	%151 = sext i32 %150 to i64
+	%152 = sext i64 1000000000 to i128
+	%153 = sext i64 10000000000 to i128
+	%154 = mul i128 %152, %153
+	%155 = udiv i128 %154, 10000000000000000000
+	%156 = udiv i128 %155, 10000000000000000000
+	%157 = trunc i128 %156 to i64
+	%158 = urem i128 %155, 10000000000000000000
+	%159 = trunc i128 %158 to i64
+	%160 = urem i128 %154, 10000000000000000000
+	%161 = trunc i128 %160 to i64
+	%162 = icmp ne i64 %157, 0
+	%163 = icmp ne i64 %159, 0
+	%164 = select i1 %163, i64 %159, i64 %161
+	%165 = select i1 %162, i64 %157, i64 %164
+	%166 = select i1 %162, i64 %159, i64 %161
+	%167 = select i1 %163, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%168 = select i1 %162, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %167
+	%169 = call i32 (i8*, ...) @printf(i8* %168, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %165, i64 %166, i64 %161)
+	%170 = sext i32 %169 to i64
+	%171 = sext i64 42 to i128
+	%172 = icmp slt i128 %171, 0
+	%173 = select i1 %172, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%174 = sub i128 0, %171
+	%175 = select i1 %172, i128 %174, i128 %171
+	%176 = udiv i128 %175, 10000000000000000000
+	%177 = udiv i128 %176, 10000000000000000000
+	%178 = trunc i128 %177 to i64
+	%179 = urem i128 %176, 10000000000000000000
+	%180 = trunc i128 %179 to i64
+	%181 = urem i128 %175, 10000000000000000000
+	%182 = trunc i128 %181 to i64
+	%183 = icmp ne i64 %178, 0
+	%184 = icmp ne i64 %180, 0
+	%185 = select i1 %184, i64 %180, i64 %182
+	%186 = select i1 %183, i64 %178, i64 %185
+	%187 = select i1 %183, i64 %180, i64 %182
+	%188 = select i1 %184, i8* getelementptr ([14 x i8], [14 x i8]* @.str.6, i64 0, i64 0), i8* getelementptr ([7 x i8], [7 x i8]* @.str.7, i64 0, i64 0)
+	%189 = select i1 %183, i8* getelementptr ([21 x i8], [21 x i8]* @.str.5, i64 0, i64 0), i8* %188
+	%190 = call i32 (i8*, ...) @printf(i8* %189, i8* %173, i64 %186, i64 %187, i64 %182)
+	%191 = sext i32 %190 to i64
+	%192 = sext i64 7 to i128
+	%193 = icmp slt i128 %192, 0
+	%194 = select i1 %193, i8* getelementptr ([2 x i8], [2 x i8]* @.str.1, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%195 = sub i128 0, %192
+	%196 = select i1 %193, i128 %195, i128 %192
+	%197 = udiv i128 %196, 10000000000000000000
+	%198 = udiv i128 %197, 10000000000000000000
+	%199 = trunc i128 %198 to i64
+	%200 = urem i128 %197, 10000000000000000000
+	%201 = trunc i128 %200 to i64
+	%202 = urem i128 %196, 10000000000000000000
+	%203 = trunc i128 %202 to i64
+	%204 = icmp ne i64 %199, 0
+	%205 = icmp ne i64 %201, 0
+	%206 = select i1 %205, i64 %201, i64 %203
+	%207 = select i1 %204, i64 %199, i64 %206
+	%208 = select i1 %204, i64 %201, i64 %203
+	%209 = select i1 %205, i8* getelementptr ([15 x i8], [15 x i8]* @.str.3, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.4, i64 0, i64 0)
+	%210 = select i1 %204, i8* getelementptr ([22 x i8], [22 x i8]* @.str.2, i64 0, i64 0), i8* %209
+	%211 = call i32 (i8*, ...) @printf(i8* %210, i8* %194, i64 %207, i64 %208, i64 %203)
+	%212 = sext i32 %211 to i64
+	ret i64 0
+}
+
+declare i32 @printf(i8* %format, ...)
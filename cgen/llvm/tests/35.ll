@@ -0,0 +1,63 @@
+@.str.0 = private unnamed_addr constant [27 x i8] c"Negative exponent [%lld].\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i32
+	%1 = trunc i64 3 to i32
+	store i32 %1, i32* %0
+	%2 = trunc i64 2 to i32
+	%3 = load i32, i32* %0
+	%4 = call i32 @pow.i32(i32 %2, i32 %3)
+	%5 = sext i32 %4 to i64
+	%6 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %5)
+	%7 = sext i32 %6 to i64
+	%8 = sub i32 0, 1
+	store i32 %8, i32* %0
+	%9 = trunc i64 2 to i32
+	%10 = load i32, i32* %0
+	%11 = call i32 @pow.i32(i32 %9, i32 %10)
+	%12 = sext i32 %11 to i64
+	%13 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %12)
+	%14 = sext i32 %13 to i64
+	ret i64 0
+}
+
+define i32 @pow.i32(i32 %b, i32 %e) {
+pow.i32.entry:
+	%0 = icmp slt i32 %e, 0
+	br i1 %0, label %fail, label %loop
+
+loop:
+	%1 = phi i32 [ 1, %pow.i32.entry ], [ %7, %body ]
+	%2 = phi i32 [ %b, %pow.i32.entry ], [ %8, %body ]
+	%3 = phi i32 [ %e, %pow.i32.entry ], [ %9, %body ]
+	%4 = icmp sgt i32 %3, 0
+	br i1 %4, label %body, label %exit
+
+body:
+	%5 = trunc i32 %3 to i1
+	%6 = mul i32 %1, %2
+	%7 = select i1 %5, i32 %6, i32 %1
+	%8 = mul i32 %2, %2
+	%9 = ashr i32 %3, 1
+	br label %loop
+
+exit:
+	ret i32 %1
+
+fail:
+	%10 = sext i32 %e to i64
+	%11 = call i32 @fflush(i8* null)
+	%12 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([27 x i8], [27 x i8]* @.str.0, i64 0, i64 0), i64 %10)
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
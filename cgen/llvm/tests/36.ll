@@ -0,0 +1,214 @@
+%P = type { i64 }
+
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+@.str.1 = private unnamed_addr constant [27 x i8] c"Negative exponent [%lld].\0A\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.3 = private unnamed_addr constant [6 x i8] c"%llu\0A\00"
+@.str.4 = private unnamed_addr constant [4 x i8] c"%g\0A\00"
+@.str.5 = private unnamed_addr constant [53 x i8] c"%s: Index [%lld] is out of range for length [%lld].\0A\00"
+@.str.6 = private unnamed_addr constant [5 x i8] c"17:3\00"
+@.str.7 = private unnamed_addr constant [6 x i8] c"18:11\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i8
+	%2 = alloca double
+	%3 = alloca { i64, i64* }
+	%4 = alloca %P
+	%5 = alloca i64
+	%6 = alloca i64
+	store i64 17, i64* %0
+	%7 = load i64, i64* %0
+	%8 = call i64 @rem.i64(i64 %7, i64 5)
+	store i64 %8, i64* %0
+	%9 = load i64, i64* %0
+	%10 = call i64 @pow.i64(i64 %9, i64 3)
+	store i64 %10, i64* %0
+	%11 = load i64, i64* %0
+	%12 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %11)
+	%13 = sext i32 %12 to i64
+	%14 = trunc i64 250 to i8
+	store i8 %14, i8* %1
+	%15 = load i8, i8* %1
+	%16 = add i8 %15, 1
+	store i8 %16, i8* %1
+	%17 = load i8, i8* %1
+	%18 = call i8 @rem.u8(i8 %17, i8 7)
+	store i8 %18, i8* %1
+	%19 = load i8, i8* %1
+	%20 = zext i8 %19 to i64
+	%21 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.3, i64 0, i64 0), i64 %20)
+	%22 = sext i32 %21 to i64
+	store double 1.5, double* %2
+	%23 = load double, double* %2
+	%24 = frem double %23, 1.0
+	store double %24, double* %2
+	%25 = load double, double* %2
+	%26 = call double @llvm.pow.f64(double %25, double 2.0)
+	store double %26, double* %2
+	%27 = load double, double* %2
+	%28 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.4, i64 0, i64 0), double %27)
+	%29 = sext i32 %28 to i64
+	%30 = call i8* @malloc(i64 mul (i64 3, i64 ptrtoint (i64* getelementptr (i64, i64* null, i32 1) to i64)))
+	%31 = bitcast i8* %30 to i64*
+	%32 = getelementptr i64, i64* %31, i64 0
+	store i64 1, i64* %32
+	%33 = getelementptr i64, i64* %31, i64 1
+	store i64 2, i64* %33
+	%34 = getelementptr i64, i64* %31, i64 2
+	store i64 3, i64* %34
+	%35 = insertvalue { i64, i64* } undef, i64 3, 0
+	%36 = insertvalue { i64, i64* } %35, i64* %31, 1
+	store { i64, i64* } %36, { i64, i64* }* %3
+	%37 = load { i64, i64* }, { i64, i64* }* %3
+	%38 = extractvalue { i64, i64* } %37, 0
+	%39 = icmp ult i64 1, %38
+	br i1 %39, label %index.ok, label %index.fail
+
+index.ok:
+	%40 = extractvalue { i64, i64* } %37, 1
+	%41 = getelementptr i64, i64* %40, i64 1
+	%42 = load i64, i64* %41
+	%43 = add i64 %42, 1
+	store i64 %43, i64* %41
+	%44 = load { i64, i64* }, { i64, i64* }* %3
+	%45 = extractvalue { i64, i64* } %44, 0
+	%46 = icmp ult i64 1, %45
+	br i1 %46, label %index.ok.1, label %index.fail.1
+
+index.fail:
+	%47 = call i32 @fflush(i8* null)
+	%48 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.5, i64 0, i64 0), i8* getelementptr ([5 x i8], [5 x i8]* @.str.6, i64 0, i64 0), i64 1, i64 %38)
+	call void @llvm.trap()
+	unreachable
+
+index.ok.1:
+	%49 = extractvalue { i64, i64* } %44, 1
+	%50 = getelementptr i64, i64* %49, i64 1
+	%51 = load i64, i64* %50
+	%52 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %51)
+	%53 = sext i32 %52 to i64
+	%54 = insertvalue %P undef, i64 10, 0
+	store %P %54, %P* %4
+	%55 = getelementptr %P, %P* %4, i32 0, i32 0
+	%56 = load i64, i64* %55
+	%57 = sub i64 %56, 1
+	store i64 %57, i64* %55
+	%58 = load %P, %P* %4
+	%59 = extractvalue %P %58, 0
+	%60 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %59)
+	%61 = sext i32 %60 to i64
+	store i64 0, i64* %5
+	store i64 0, i64* %6
+	br label %for.header
+
+index.fail.1:
+	%62 = call i32 @fflush(i8* null)
+	%63 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([53 x i8], [53 x i8]* @.str.5, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.7, i64 0, i64 0), i64 1, i64 %45)
+	call void @llvm.trap()
+	unreachable
+
+for.header:
+	%64 = load i64, i64* %6
+	%65 = icmp slt i64 %64, 5
+	br i1 %65, label %for.body, label %for.exit
+
+for.body:
+	%66 = load i64, i64* %6
+	%67 = load i64, i64* %5
+	%68 = add i64 %67, %66
+	store i64 %68, i64* %5
+	br label %for.latch
+
+for.latch:
+	%69 = load i64, i64* %6
+	%70 = add i64 %69, 1
+	store i64 %70, i64* %6
+	br label %for.header
+
+for.exit:
+	%71 = load i64, i64* %5
+	ret i64 %71
+}
+
+define i64 @rem.i64(i64 %a, i64 %b) {
+rem.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	ret i64 0
+
+div:
+	%2 = srem i64 %a, %b
+	ret i64 %2
+
+fail:
+	%3 = call i32 @fflush(i8* null)
+	%4 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+define i64 @pow.i64(i64 %b, i64 %e) {
+pow.i64.entry:
+	%0 = icmp slt i64 %e, 0
+	br i1 %0, label %fail, label %loop
+
+loop:
+	%1 = phi i64 [ 1, %pow.i64.entry ], [ %7, %body ]
+	%2 = phi i64 [ %b, %pow.i64.entry ], [ %8, %body ]
+	%3 = phi i64 [ %e, %pow.i64.entry ], [ %9, %body ]
+	%4 = icmp sgt i64 %3, 0
+	br i1 %4, label %body, label %exit
+
+body:
+	%5 = trunc i64 %3 to i1
+	%6 = mul i64 %1, %2
+	%7 = select i1 %5, i64 %6, i64 %1
+	%8 = mul i64 %2, %2
+	%9 = ashr i64 %3, 1
+	br label %loop
+
+exit:
+	ret i64 %1
+
+fail:
+	%10 = call i32 @fflush(i8* null)
+	%11 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([27 x i8], [27 x i8]* @.str.1, i64 0, i64 0), i64 %e)
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @printf(i8* %format, ...)
+
+define i8 @rem.u8(i8 %a, i8 %b) {
+rem.u8.entry:
+	%0 = icmp eq i8 %b, 0
+	br i1 %0, label %fail, label %div
+
+div:
+	%1 = urem i8 %a, %b
+	ret i8 %1
+
+fail:
+	%2 = call i32 @fflush(i8* null)
+	%3 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare double @llvm.pow.f64(double %b, double %e)
+
+declare i8* @malloc(i64 %size)
@@ -0,0 +1,39 @@
+@.str.0 = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"false\00"
+@.str.2 = private unnamed_addr constant [4 x i8] c"%s\0A\00"
+
+declare void @srand(i32 %seed)
+
+declare i32 @rand()
+
+declare void @exit(i32 %code)
+
+define i64 @main() {
+main.entry:
+	%0 = alloca { void (i8*, i32)*, i8* }
+	%1 = trunc i64 7 to i32
+	call void @srand(i32 %1)
+	%2 = call i32 @rand()
+	%3 = trunc i64 0 to i32
+	%4 = icmp sge i32 %2, %3
+	%5 = select i1 %4, i8* getelementptr ([5 x i8], [5 x i8]* @.str.0, i64 0, i64 0), i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0)
+	%6 = call i32 (i8*, ...) @printf(i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0), i8* %5)
+	%7 = sext i32 %6 to i64
+	%8 = insertvalue { void (i8*, i32)*, i8* } undef, void (i8*, i32)* @exit.closure, 0
+	%9 = insertvalue { void (i8*, i32)*, i8* } %8, i8* null, 1
+	store { void (i8*, i32)*, i8* } %9, { void (i8*, i32)*, i8* }* %0
+	%10 = load { void (i8*, i32)*, i8* }, { void (i8*, i32)*, i8* }* %0
+	%11 = trunc i64 3 to i32
+	%12 = extractvalue { void (i8*, i32)*, i8* } %10, 0
+	%13 = extractvalue { void (i8*, i32)*, i8* } %10, 1
+	call void %12(i8* %13, i32 %11)
+	ret i64 1
+}
+
+declare i32 @printf(i8* %format, ...)
+
+define void @exit.closure(i8* %env, i32 %code) {
+exit.closure.entry:
+	call void @exit(i32 %code)
+	ret void
+}
@@ -0,0 +1,139 @@
+@.str.0 = private unnamed_addr constant [1 x i8] c"\00"
+@.str.1 = private unnamed_addr constant [22 x i8] c"%s%llu%019llu%019llu\0A\00"
+@.str.2 = private unnamed_addr constant [15 x i8] c"%s%llu%019llu\0A\00"
+@.str.3 = private unnamed_addr constant [8 x i8] c"%s%llu\0A\00"
+@.str.4 = private unnamed_addr constant [2 x i8] c"-\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i128
+	%1 = alloca i128
+	%2 = alloca i128
+	%3 = alloca i128
+	store i128 u0x10000000000000000, i128* %0
+	%4 = load i128, i128* %0
+	%5 = udiv i128 %4, 10000000000000000000
+	%6 = udiv i128 %5, 10000000000000000000
+	%7 = trunc i128 %6 to i64
+	%8 = urem i128 %5, 10000000000000000000
+	%9 = trunc i128 %8 to i64
+	%10 = urem i128 %4, 10000000000000000000
+	%11 = trunc i128 %10 to i64
+	%12 = icmp ne i64 %7, 0
+	%13 = icmp ne i64 %9, 0
+	%14 = select i1 %13, i64 %9, i64 %11
+	%15 = select i1 %12, i64 %7, i64 %14
+	%16 = select i1 %12, i64 %9, i64 %11
+	%17 = select i1 %13, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%18 = select i1 %12, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %17
+	%19 = call i32 (i8*, ...) @printf(i8* %18, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %15, i64 %16, i64 %11)
+	%20 = sext i32 %19 to i64
+	%21 = load i128, i128* %0
+	%22 = sext i64 3 to i128
+	%23 = mul i128 %21, %22
+	%24 = udiv i128 %23, 10000000000000000000
+	%25 = udiv i128 %24, 10000000000000000000
+	%26 = trunc i128 %25 to i64
+	%27 = urem i128 %24, 10000000000000000000
+	%28 = trunc i128 %27 to i64
+	%29 = urem i128 %23, 10000000000000000000
+	%30 = trunc i128 %29 to i64
+	%31 = icmp ne i64 %26, 0
+	%32 = icmp ne i64 %28, 0
+	%33 = select i1 %32, i64 %28, i64 %30
+	%34 = select i1 %31, i64 %26, i64 %33
+	%35 = select i1 %31, i64 %28, i64 %30
+	%36 = select i1 %32, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%37 = select i1 %31, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %36
+	%38 = call i32 (i8*, ...) @printf(i8* %37, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %34, i64 %35, i64 %30)
+	%39 = sext i32 %38 to i64
+	store i128 -1, i128* %1
+	%40 = load i128, i128* %1
+	%41 = udiv i128 %40, 10000000000000000000
+	%42 = udiv i128 %41, 10000000000000000000
+	%43 = trunc i128 %42 to i64
+	%44 = urem i128 %41, 10000000000000000000
+	%45 = trunc i128 %44 to i64
+	%46 = urem i128 %40, 10000000000000000000
+	%47 = trunc i128 %46 to i64
+	%48 = icmp ne i64 %43, 0
+	%49 = icmp ne i64 %45, 0
+	%50 = select i1 %49, i64 %45, i64 %47
+	%51 = select i1 %48, i64 %43, i64 %50
+	%52 = select i1 %48, i64 %45, i64 %47
+	%53 = select i1 %49, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%54 = select i1 %48, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %53
+	%55 = call i32 (i8*, ...) @printf(i8* %54, i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0), i64 %51, i64 %52, i64 %47)
+	%56 = sext i32 %55 to i64
+	%57 = sub i128 0, -170141183460469231731687303715884105728
+	store i128 %57, i128* %2
+	%58 = load i128, i128* %2
+	%59 = icmp slt i128 %58, 0
+	%60 = select i1 %59, i8* getelementptr ([2 x i8], [2 x i8]* @.str.4, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%61 = sub i128 0, %58
+	%62 = select i1 %59, i128 %61, i128 %58
+	%63 = udiv i128 %62, 10000000000000000000
+	%64 = udiv i128 %63, 10000000000000000000
+	%65 = trunc i128 %64 to i64
+	%66 = urem i128 %63, 10000000000000000000
+	%67 = trunc i128 %66 to i64
+	%68 = urem i128 %62, 10000000000000000000
+	%69 = trunc i128 %68 to i64
+	%70 = icmp ne i64 %65, 0
+	%71 = icmp ne i64 %67, 0
+	%72 = select i1 %71, i64 %67, i64 %69
+	%73 = select i1 %70, i64 %65, i64 %72
+	%74 = select i1 %70, i64 %67, i64 %69
+	%75 = select i1 %71, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%76 = select i1 %70, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %75
+	%77 = call i32 (i8*, ...) @printf(i8* %76, i8* %60, i64 %73, i64 %74, i64 %69)
+	%78 = sext i32 %77 to i64
+	%79 = load i128, i128* %2
+	%80 = add i128 %79, u0x7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF
+	%81 = icmp slt i128 %80, 0
+	%82 = select i1 %81, i8* getelementptr ([2 x i8], [2 x i8]* @.str.4, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%83 = sub i128 0, %80
+	%84 = select i1 %81, i128 %83, i128 %80
+	%85 = udiv i128 %84, 10000000000000000000
+	%86 = udiv i128 %85, 10000000000000000000
+	%87 = trunc i128 %86 to i64
+	%88 = urem i128 %85, 10000000000000000000
+	%89 = trunc i128 %88 to i64
+	%90 = urem i128 %84, 10000000000000000000
+	%91 = trunc i128 %90 to i64
+	%92 = icmp ne i64 %87, 0
+	%93 = icmp ne i64 %89, 0
+	%94 = select i1 %93, i64 %89, i64 %91
+	%95 = select i1 %92, i64 %87, i64 %94
+	%96 = select i1 %92, i64 %89, i64 %91
+	%97 = select i1 %93, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%98 = select i1 %92, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %97
+	%99 = call i32 (i8*, ...) @printf(i8* %98, i8* %82, i64 %95, i64 %96, i64 %91)
+	%100 = sext i32 %99 to i64
+	store i128 u0x20000000000000000, i128* %3
+	%101 = load i128, i128* %3
+	%102 = sub i128 0, %101
+	%103 = icmp slt i128 %102, 0
+	%104 = select i1 %103, i8* getelementptr ([2 x i8], [2 x i8]* @.str.4, i64 0, i64 0), i8* getelementptr ([1 x i8], [1 x i8]* @.str.0, i64 0, i64 0)
+	%105 = sub i128 0, %102
+	%106 = select i1 %103, i128 %105, i128 %102
+	%107 = udiv i128 %106, 10000000000000000000
+	%108 = udiv i128 %107, 10000000000000000000
+	%109 = trunc i128 %108 to i64
+	%110 = urem i128 %107, 10000000000000000000
+	%111 = trunc i128 %110 to i64
+	%112 = urem i128 %106, 10000000000000000000
+	%113 = trunc i128 %112 to i64
+	%114 = icmp ne i64 %109, 0
+	%115 = icmp ne i64 %111, 0
+	%116 = select i1 %115, i64 %111, i64 %113
+	%117 = select i1 %114, i64 %109, i64 %116
+	%118 = select i1 %114, i64 %111, i64 %113
+	%119 = select i1 %115, i8* getelementptr ([15 x i8], [15 x i8]* @.str.2, i64 0, i64 0), i8* getelementptr ([8 x i8], [8 x i8]* @.str.3, i64 0, i64 0)
+	%120 = select i1 %114, i8* getelementptr ([22 x i8], [22 x i8]* @.str.1, i64 0, i64 0), i8* %119
+	%121 = call i32 (i8*, ...) @printf(i8* %120, i8* %104, i64 %117, i64 %118, i64 %113)
+	%122 = sext i32 %121 to i64
+	ret i64 0
+}
+
+declare i32 @printf(i8* %format, ...)
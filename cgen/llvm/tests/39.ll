@@ -0,0 +1,178 @@
+@.str.0 = private unnamed_addr constant [19 x i8] c"Division by zero.\0A\00"
+@.str.1 = private unnamed_addr constant [6 x i8] c"%lld\0A\00"
+@.str.2 = private unnamed_addr constant [6 x i8] c"%llu\0A\00"
+
+define i64 @main() {
+main.entry:
+	%0 = alloca i64
+	%1 = alloca i64
+	%2 = alloca i8
+	%3 = alloca i8
+	%4 = alloca i64
+	%5 = sub i64 0, u0x7FFFFFFFFFFFFFFF
+	%6 = sub i64 %5, 1
+	store i64 %6, i64* %0
+	%7 = sub i64 0, 1
+	store i64 %7, i64* %1
+	%8 = load i64, i64* %0
+	%9 = load i64, i64* %1
+	%10 = call i64 @div.i64(i64 %8, i64 %9)
+	%11 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %10)
+	%12 = sext i32 %11 to i64
+	%13 = load i64, i64* %0
+	%14 = load i64, i64* %1
+	%15 = call i64 @rem.i64(i64 %13, i64 %14)
+	%16 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %15)
+	%17 = sext i32 %16 to i64
+	%18 = sub i8 0, 128
+	store i8 %18, i8* %2
+	%19 = sub i64 0, 1
+	%20 = trunc i64 %19 to i8
+	%21 = load i8, i8* %2
+	%22 = call i8 @div.i8(i8 %21, i8 %20)
+	store i8 %22, i8* %2
+	%23 = load i8, i8* %2
+	%24 = sext i8 %23 to i64
+	%25 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %24)
+	%26 = sext i32 %25 to i64
+	store i8 250, i8* %3
+	%27 = trunc i64 7 to i8
+	%28 = load i8, i8* %3
+	%29 = call i8 @rem.u8(i8 %28, i8 %27)
+	store i8 %29, i8* %3
+	%30 = load i8, i8* %3
+	%31 = zext i8 %30 to i64
+	%32 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %31)
+	%33 = sext i32 %32 to i64
+	%34 = trunc i64 4000000000 to i32
+	%35 = trunc i64 3 to i32
+	%36 = call i32 @div.u32(i32 %34, i32 %35)
+	%37 = zext i32 %36 to i64
+	%38 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.2, i64 0, i64 0), i64 %37)
+	%39 = sext i32 %38 to i64
+	%40 = sub i64 0, 7
+	%41 = call i64 @div.i64(i64 %40, i64 2)
+	%42 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %41)
+	%43 = sext i32 %42 to i64
+	%44 = sub i64 0, 7
+	%45 = call i64 @rem.i64(i64 %44, i64 2)
+	%46 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %45)
+	%47 = sext i32 %46 to i64
+	store i64 0, i64* %4
+	%48 = load i64, i64* %4
+	%49 = call i64 @div.i64(i64 1, i64 %48)
+	%50 = call i32 (i8*, ...) @printf(i8* getelementptr ([6 x i8], [6 x i8]* @.str.1, i64 0, i64 0), i64 %49)
+	%51 = sext i32 %50 to i64
+	ret i64 0
+}
+
+define i64 @div.i64(i64 %a, i64 %b) {
+div.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	%2 = sub i64 0, %a
+	ret i64 %2
+
+div:
+	%3 = sdiv i64 %a, %b
+	ret i64 %3
+
+fail:
+	%4 = call i32 @fflush(i8* null)
+	%5 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+declare i32 @fflush(i8* %stream)
+
+declare i32 @dprintf(i32 %fd, i8* %format, ...)
+
+declare void @llvm.trap()
+
+declare i32 @printf(i8* %format, ...)
+
+define i64 @rem.i64(i64 %a, i64 %b) {
+rem.i64.entry:
+	%0 = icmp eq i64 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i64 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	ret i64 0
+
+div:
+	%2 = srem i64 %a, %b
+	ret i64 %2
+
+fail:
+	%3 = call i32 @fflush(i8* null)
+	%4 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+define i8 @div.i8(i8 %a, i8 %b) {
+div.i8.entry:
+	%0 = icmp eq i8 %b, 0
+	br i1 %0, label %fail, label %check
+
+check:
+	%1 = icmp eq i8 %b, -1
+	br i1 %1, label %neg, label %div
+
+neg:
+	%2 = sub i8 0, %a
+	ret i8 %2
+
+div:
+	%3 = sdiv i8 %a, %b
+	ret i8 %3
+
+fail:
+	%4 = call i32 @fflush(i8* null)
+	%5 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+define i8 @rem.u8(i8 %a, i8 %b) {
+rem.u8.entry:
+	%0 = icmp eq i8 %b, 0
+	br i1 %0, label %fail, label %div
+
+div:
+	%1 = urem i8 %a, %b
+	ret i8 %1
+
+fail:
+	%2 = call i32 @fflush(i8* null)
+	%3 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
+
+define i32 @div.u32(i32 %a, i32 %b) {
+div.u32.entry:
+	%0 = icmp eq i32 %b, 0
+	br i1 %0, label %fail, label %div
+
+div:
+	%1 = udiv i32 %a, %b
+	ret i32 %1
+
+fail:
+	%2 = call i32 @fflush(i8* null)
+	%3 = call i32 (i32, i8*, ...) @dprintf(i32 2, i8* getelementptr ([19 x i8], [19 x i8]* @.str.0, i64 0, i64 0))
+	call void @llvm.trap()
+	unreachable
+}
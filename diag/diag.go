@@ -0,0 +1,187 @@
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/mhoertnagl/donkey/token"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Info
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	}
+	return "info"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Pos is a 1-based line and column position in a source file.
+type Pos struct {
+	Line int `json:"line"`
+	Col  int `json:"col"`
+}
+
+// Span is the range of source text a diagnostic refers to. The end
+// position is exclusive.
+type Span struct {
+	Start Pos `json:"start"`
+	End   Pos `json:"end"`
+}
+
+// TokenSpan returns the span covered by the token. The end of tokens
+// without an end position is derived from their literal.
+func TokenSpan(tok token.Token) Span {
+	end := Pos{Line: tok.EndLine, Col: tok.EndCol}
+	if tok.EndLine == 0 {
+		end = Pos{Line: tok.Line, Col: tok.Col + utf8.RuneCountInString(tok.Literal)}
+	}
+	return Span{Start: Pos{Line: tok.Line, Col: tok.Col}, End: end}
+}
+
+// Node is a syntax tree node that covers a range of the source.
+type Node interface {
+	Pos() token.Position
+	End() token.Position
+}
+
+// NodeSpan returns the span covered by the node.
+func NodeSpan(n Node) Span {
+	start, end := n.Pos(), n.End()
+	return Span{
+		Start: Pos{Line: start.Line, Col: start.Col},
+		End:   Pos{Line: end.Line, Col: end.Col},
+	}
+}
+
+// Location is a span in a source file.
+type Location struct {
+	File string
+	Span Span
+}
+
+// TokenLocation returns the location of the token.
+func TokenLocation(tok token.Token) Location {
+	return Location{File: tok.File, Span: TokenSpan(tok)}
+}
+
+// NodeLocation returns the location covered by the node.
+func NodeLocation(n Node) Location {
+	return Location{File: n.Pos().File, Span: NodeSpan(n)}
+}
+
+// Note attaches additional information to a diagnostic. The location of a
+// note is optional and may be in another file than the diagnostic, e.g.
+// the declaration in an imported module.
+type Note struct {
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Span    *Span  `json:"span,omitempty"`
+}
+
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Span     Span     `json:"span"`
+	Notes    []Note   `json:"notes,omitempty"`
+}
+
+// String formats the diagnostic as file:line:col: severity[code]: message.
+// This is the format most editors and CI systems know how to parse.
+func (d *Diagnostic) String() string {
+	var buf bytes.Buffer
+	if d.File != "" {
+		buf.WriteString(d.File)
+		buf.WriteString(":")
+	}
+	buf.WriteString(fmt.Sprintf("%d:%d: ", d.Span.Start.Line, d.Span.Start.Col))
+	buf.WriteString(d.Severity.String())
+	if d.Code != "" {
+		buf.WriteString(fmt.Sprintf("[%s]", d.Code))
+	}
+	buf.WriteString(": ")
+	buf.WriteString(d.Message)
+	return buf.String()
+}
+
+// AddNote attaches a note to the diagnostic. The location may be nil.
+func (d *Diagnostic) AddNote(loc *Location, format string, a ...any) *Diagnostic {
+	note := Note{Message: fmt.Sprintf(format, a...)}
+	if loc != nil {
+		span := loc.Span
+		note.File = loc.File
+		note.Span = &span
+	}
+	d.Notes = append(d.Notes, note)
+	return d
+}
+
+// List collects the diagnostics reported by the individual compiler
+// stages.
+type List struct {
+	items []*Diagnostic
+}
+
+func NewList() *List {
+	return &List{items: []*Diagnostic{}}
+}
+
+func (l *List) Add(d *Diagnostic) *Diagnostic {
+	l.items = append(l.items, d)
+	return d
+}
+
+// Errorf reports an error at the span and returns the new diagnostic so
+// that notes can be attached to it.
+func (l *List) Errorf(code string, file string, span Span, format string, a ...any) *Diagnostic {
+	return l.Add(&Diagnostic{
+		Severity: Error,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		File:     file,
+		Span:     span,
+	})
+}
+
+// TokenErrorf reports an error at the position of the token.
+func (l *List) TokenErrorf(code string, tok token.Token, format string, a ...any) *Diagnostic {
+	return l.Errorf(code, tok.File, TokenSpan(tok), format, a...)
+}
+
+// NodeErrorf reports an error that covers the whole node.
+func (l *List) NodeErrorf(code string, n Node, format string, a ...any) *Diagnostic {
+	return l.Errorf(code, n.Pos().File, NodeSpan(n), format, a...)
+}
+
+func (l *List) Items() []*Diagnostic {
+	return l.items
+}
+
+func (l *List) Len() int {
+	return len(l.items)
+}
+
+func (l *List) HasErrors() bool {
+	for _, d := range l.items {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package diag_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+func TestString(t *testing.T) {
+	tok := token.Token{Typ: token.ID, Literal: "foo", File: "a.dk", Line: 3, Col: 7}
+	l := diag.NewList()
+	d := l.TokenErrorf("P0001", tok, "Unexpected [%s].", tok.Literal)
+	expect(t, d.String(), "a.dk:3:7: error[P0001]: Unexpected [foo].")
+	if d.Span.End != (diag.Pos{Line: 3, Col: 10}) {
+		t.Errorf("Unexpected span end [%v].", d.Span.End)
+	}
+	if !l.HasErrors() {
+		t.Errorf("Expected errors.")
+	}
+}
+
+func TestRender(t *testing.T) {
+	src := "fn main() {\n\tlet a = 1 let b = 2;\n}"
+	tok := token.Token{Typ: token.LET, Literal: "let", File: "a.dk", Line: 2, Col: 12}
+	d := diag.NewList().TokenErrorf("P0001", tok, "Expecting [;] but got [let].")
+	d.AddNote(nil, "Statements end with [;].")
+
+	r := diag.NewRenderer()
+	r.AddSource("a.dk", src)
+	var buf bytes.Buffer
+	r.Render(&buf, d)
+
+	expect(t, buf.String(), `a.dk:2:12: error[P0001]: Expecting [;] but got [let].
+   |
+ 2 | 	let a = 1 let b = 2;
+   | 	          ^^^
+ = note: Statements end with [;].
+`)
+}
+
+func TestRenderNoteInOtherFile(t *testing.T) {
+	use := token.Token{Typ: token.ID, Literal: "f", File: "a.dk", Line: 1, Col: 5}
+	decl := token.Token{Typ: token.ID, Literal: "f", File: "b.dk", Line: 2, Col: 4}
+	d := diag.NewList().TokenErrorf("R0012", use, "[f] is not public in module [b].")
+	loc := diag.TokenLocation(decl)
+	d.AddNote(&loc, "[f] is defined here.")
+
+	r := diag.NewRenderer()
+	r.AddSource("a.dk", "b.f();")
+	r.AddSource("b.dk", "pub fn g() { }\nfn f() { }")
+	var buf bytes.Buffer
+	r.Render(&buf, d)
+
+	expect(t, buf.String(), `a.dk:1:5: error[R0012]: [f] is not public in module [b].
+   |
+ 1 | b.f();
+   |     ^
+ = note: b.dk:2:4: [f] is defined here.
+   |
+ 2 | fn f() { }
+   |    ^
+`)
+}
+
+func TestRenderUnicode(t *testing.T) {
+	src := "let größe = ½;"
+	tok := token.Token{Typ: token.ILLEGAL, Literal: "½", File: "a.dk", Line: 1, Col: 13}
+	d := diag.NewList().TokenErrorf("L0001", tok, "Illegal character [½].")
+
+	r := diag.NewRenderer()
+	r.AddSource("a.dk", src)
+	var buf bytes.Buffer
+	r.Render(&buf, d)
+
+	expect(t, buf.String(), `a.dk:1:13: error[L0001]: Illegal character [½].
+   |
+ 1 | let größe = ½;
+   |             ^
+`)
+}
+
+func TestRenderUTF16(t *testing.T) {
+	src := `let s = "😀"; ½;`
+	// The emoji takes two UTF-16 columns.
+	tok := token.Token{Typ: token.ILLEGAL, Literal: "½", File: "a.dk", Line: 1, Col: 15}
+	d := diag.NewList().TokenErrorf("L0001", tok, "Illegal character [½].")
+
+	r := diag.NewRendererWithUnit(token.UTF16)
+	r.AddSource("a.dk", src)
+	var buf bytes.Buffer
+	r.Render(&buf, d)
+
+	expect(t, buf.String(), `a.dk:1:15: error[L0001]: Illegal character [½].
+   |
+ 1 | let s = "😀"; ½;
+   |              ^
+`)
+}
+
+func TestRenderWithoutSource(t *testing.T) {
+	d := &diag.Diagnostic{Severity: diag.Warning, Message: "Unused.", Span: diag.Span{Start: diag.Pos{Line: 1, Col: 1}}}
+	var buf bytes.Buffer
+	diag.NewRenderer().Render(&buf, d)
+	expect(t, buf.String(), "1:1: warning: Unused.\n")
+}
+
+func expect(t *testing.T, actual, expected string) {
+	t.Helper()
+	if actual != expected {
+		t.Errorf("Expected [\n%s\n] but got [\n%s\n].", expected, actual)
+	}
+}
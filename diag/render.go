@@ -0,0 +1,141 @@
+package diag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mhoertnagl/donkey/token"
+)
+
+// Renderer prints diagnostics together with the offending source line and
+// a caret underline:
+//
+//	foo.dk:2:13: error[P0001]: Expecting [;] but got [let].
+//	   |
+//	 2 |   let a = 1 let b = 2;
+//	   |             ^^^
+type Renderer struct {
+	sources map[string][]string
+	unit    token.ColumnUnit
+}
+
+// NewRenderer creates a renderer for diagnostics with columns in runes.
+func NewRenderer() *Renderer {
+	return NewRendererWithUnit(token.Runes)
+}
+
+// NewRendererWithUnit creates a renderer for diagnostics with columns in
+// the given unit.
+func NewRendererWithUnit(unit token.ColumnUnit) *Renderer {
+	return &Renderer{sources: make(map[string][]string), unit: unit}
+}
+
+// AddSource registers the source text of a file. Diagnostics for files
+// without registered source are rendered without a snippet.
+func (r *Renderer) AddSource(file, src string) {
+	r.sources[file] = strings.Split(src, "\n")
+}
+
+func (r *Renderer) Render(w io.Writer, d *Diagnostic) {
+	fmt.Fprintf(w, "%s\n", d)
+	lines, ok := r.sources[d.File]
+	if ok {
+		r.renderSpan(w, lines, d.Span)
+	}
+	// Notes are rendered with the source of their own file.
+	for _, n := range d.Notes {
+		if n.Span != nil {
+			fmt.Fprintf(w, " = note: %s%d:%d: %s\n", filePrefix(n.File), n.Span.Start.Line, n.Span.Start.Col, n.Message)
+			if lines, ok := r.sources[n.File]; ok {
+				r.renderSpan(w, lines, *n.Span)
+			}
+		} else {
+			fmt.Fprintf(w, " = note: %s\n", n.Message)
+		}
+	}
+}
+
+// filePrefix returns the file followed by a colon or the empty string if
+// the file has no name.
+func filePrefix(file string) string {
+	if file == "" {
+		return ""
+	}
+	return file + ":"
+}
+
+func (r *Renderer) RenderAll(w io.Writer, ds []*Diagnostic) {
+	for _, d := range ds {
+		r.Render(w, d)
+	}
+}
+
+func (r *Renderer) renderSpan(w io.Writer, lines []string, span Span) {
+	if span.Start.Line < 1 || span.Start.Line > len(lines) {
+		return
+	}
+	line := []rune(strings.TrimRight(lines[span.Start.Line-1], "\r"))
+	num := fmt.Sprintf("%d", span.Start.Line)
+	gutter := strings.Repeat(" ", len(num))
+
+	// Spans that continue on the following lines are underlined up to the
+	// end of the first line.
+	start := r.runeIndex(line, span.Start.Col)
+	end := len(line)
+	if span.End.Line == span.Start.Line {
+		end = clamp(r.runeIndex(line, span.End.Col), start, len(line))
+	}
+	width := end - start
+	if width < 1 {
+		width = 1
+	}
+
+	fmt.Fprintf(w, " %s |\n", gutter)
+	fmt.Fprintf(w, " %s | %s\n", num, string(line))
+	fmt.Fprintf(w, " %s | %s%s\n", gutter, indentation(line[:start]), strings.Repeat("^", width))
+}
+
+// runeIndex returns the index of the rune of the line at the column. In
+// UTF-16 columns code points outside of the basic multilingual plane take
+// two columns.
+func (r *Renderer) runeIndex(line []rune, col int) int {
+	if r.unit == token.Runes {
+		return clamp(col-1, 0, len(line))
+	}
+	n := 1
+	for i, c := range line {
+		if n >= col {
+			return i
+		}
+		n++
+		if c > 0xFFFF {
+			n++
+		}
+	}
+	return len(line)
+}
+
+// indentation replaces every character of the prefix with a space except
+// for tabs so that the caret lines up with the source line.
+func indentation(prefix []rune) string {
+	var b strings.Builder
+	for _, c := range prefix {
+		if c == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
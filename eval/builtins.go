@@ -0,0 +1,128 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// intTypes maps the names of the integer types to the types. int is the
+// same type as i64.
+var intTypes = map[string]*IntType{
+	"int":  i64,
+	"i8":   {Name: "i8", Bits: 8},
+	"i16":  {Name: "i16", Bits: 16},
+	"i32":  {Name: "i32", Bits: 32},
+	"i64":  i64,
+	"i128": {Name: "i128", Bits: 128},
+	"u8":   {Name: "u8", Bits: 8, Unsigned: true},
+	"u16":  {Name: "u16", Bits: 16, Unsigned: true},
+	"u32":  {Name: "u32", Bits: 32, Unsigned: true},
+	"u64":  {Name: "u64", Bits: 64, Unsigned: true},
+	"u128": {Name: "u128", Bits: 128, Unsigned: true},
+}
+
+var i64 = &IntType{Name: "i64", Bits: 64}
+
+var builtins = map[string]*Builtin{
+	"print":   {Name: "print", Fn: printer("print", "")},
+	"println": {Name: "println", Fn: printer("println", "\n")},
+	"len":     {Name: "len", Fn: length},
+	"int":     {Name: "int", Fn: converter("int")},
+	"i8":      {Name: "i8", Fn: converter("i8")},
+	"i16":     {Name: "i16", Fn: converter("i16")},
+	"i32":     {Name: "i32", Fn: converter("i32")},
+	"i64":     {Name: "i64", Fn: converter("i64")},
+	"i128":    {Name: "i128", Fn: converter("i128")},
+	"u8":      {Name: "u8", Fn: converter("u8")},
+	"u16":     {Name: "u16", Fn: converter("u16")},
+	"u32":     {Name: "u32", Fn: converter("u32")},
+	"u64":     {Name: "u64", Fn: converter("u64")},
+	"u128":    {Name: "u128", Fn: converter("u128")},
+	"f32":     {Name: "f32", Fn: floatConverter("f32", 32)},
+	"f64":     {Name: "f64", Fn: floatConverter("f64", 64)},
+}
+
+// length returns the number of elements of an array or the number of
+// bytes of a string.
+func length(e *Evaluator, args ...Object) Object {
+	if len(args) != 1 {
+		return newError("Function [%s] expects [%d] arguments but got [%d].", "len", 1, len(args))
+	}
+	switch arg := args[0].(type) {
+	case *Array:
+		return &Integer{Value: int64(len(arg.Elements))}
+	case *String:
+		return &Integer{Value: int64(len(arg.Value))}
+	}
+	return newError("Cannot take the length of [%s].", args[0].Inspect())
+}
+
+// printer returns a builtin function that prints its single argument
+// followed by the suffix. It returns the number of bytes written.
+func printer(name string, suffix string) BuiltinFunction {
+	return func(e *Evaluator, args ...Object) Object {
+		if len(args) != 1 {
+			return newError("Function [%s] expects [%d] arguments but got [%d].", name, 1, len(args))
+		}
+		switch args[0].(type) {
+		case *Integer, *Float, *Boolean, *String:
+		default:
+			return newError("Cannot print [%s].", args[0].Inspect())
+		}
+		n, err := fmt.Fprintf(e.out, "%s%s", args[0].Inspect(), suffix)
+		if err != nil {
+			return newError("%s", err)
+		}
+		return &Integer{Value: int64(n)}
+	}
+}
+
+// converter returns a builtin function that converts an integer or a
+// floating-point number to the integer type with the name. Floating-point
+// numbers are truncated toward zero. The value wraps around to the range of
+// the type.
+func converter(name string) BuiltinFunction {
+	kind := intTypes[name]
+	return func(e *Evaluator, args ...Object) Object {
+		if len(args) != 1 {
+			return newError("Function [%s] expects [%d] arguments but got [%d].", name, 1, len(args))
+		}
+		switch v := args[0].(type) {
+		case *Integer:
+			return v.convert(kind)
+		case *Float:
+			if math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+				break
+			}
+			i, _ := big.NewFloat(v.Value).Int(nil)
+			return newInteger(i, kind)
+		}
+		return newError("Cannot convert [%s] to [%s].", args[0].Inspect(), name)
+	}
+}
+
+// floatConverter returns a builtin function that converts an integer or a
+// floating-point number to the floating-point type of the given size. The
+// evaluator represents all floating-point numbers with 64 bits. Conversions
+// to f32 round the value to single precision.
+func floatConverter(name string, bits uint) BuiltinFunction {
+	return func(e *Evaluator, args ...Object) Object {
+		if len(args) != 1 {
+			return newError("Function [%s] expects [%d] arguments but got [%d].", name, 1, len(args))
+		}
+		var v float64
+		switch arg := args[0].(type) {
+		case *Integer:
+			v, _ = new(big.Float).SetInt(arg.bigInt()).Float64()
+		case *Float:
+			v = arg.Value
+		default:
+			return newError("Cannot convert [%s] to [%s].", args[0].Inspect(), name)
+		}
+		if bits == 32 {
+			v = float64(float32(v))
+		}
+		return &Float{Value: v}
+	}
+}
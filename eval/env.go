@@ -0,0 +1,67 @@
+package eval
+
+// Env maps names to values. Each function call and block creates a new
+// environment that is enclosed by the environment it has been created in.
+type Env struct {
+	store map[string]Object
+	funcs map[string]bool // Names bound by function definitions.
+	outer *Env
+}
+
+func NewEnv() *Env {
+	return &Env{store: make(map[string]Object), funcs: make(map[string]bool)}
+}
+
+func NewEnclosedEnv(outer *Env) *Env {
+	env := NewEnv()
+	env.outer = outer
+	return env
+}
+
+// Get looks up the name in this environment and all enclosing
+// environments.
+func (env *Env) Get(name string) (Object, bool) {
+	for e := env; e != nil; e = e.outer {
+		if v, ok := e.store[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Set binds the name to the value in this environment.
+func (env *Env) Set(name string, val Object) Object {
+	env.store[name] = val
+	delete(env.funcs, name)
+	return val
+}
+
+// SetFunction binds the name to the function of a function definition in
+// this environment. Such names cannot be assigned to.
+func (env *Env) SetFunction(name string, fun *Function) Object {
+	env.Set(name, fun)
+	env.funcs[name] = true
+	return fun
+}
+
+// IsFunction returns true iff the name is bound by a function definition.
+func (env *Env) IsFunction(name string) bool {
+	for e := env; e != nil; e = e.outer {
+		if _, ok := e.store[name]; ok {
+			return e.funcs[name]
+		}
+	}
+	return false
+}
+
+// Assign rebinds the name in the environment that defines it. It returns
+// false if the name is undefined.
+func (env *Env) Assign(name string, val Object) bool {
+	for e := env; e != nil; e = e.outer {
+		if _, ok := e.store[name]; ok {
+			e.store[name] = val
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,839 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+var _true = &Boolean{Value: true}
+var _false = &Boolean{Value: false}
+
+// Evaluator is a tree-walking interpreter for donkey programs. The global
+// environment persists between calls to Eval so that definitions made in
+// one program are visible in subsequent ones.
+//
+// The evaluator does not check types. Integers carry their integer type
+// and wrap around like in compiled programs. Values get the integer type
+// of the type annotations of variables, parameters, results and fields.
+type Evaluator struct {
+	env *Env
+	out io.Writer
+}
+
+func NewEvaluator() *Evaluator {
+	return &Evaluator{env: NewEnv(), out: os.Stdout}
+}
+
+// SetOutput sets the writer the builtin functions print to.
+func (e *Evaluator) SetOutput(out io.Writer) {
+	e.out = out
+}
+
+// Eval evaluates the program and returns the value of the last statement
+// or nil if the last statement does not produce a value.
+func (e *Evaluator) Eval(n *parser.Program) Object {
+	e.collectDefinitions(n.Statements, e.env)
+	res := e.stmts(n.Statements, e.env)
+	if ret, ok := res.(*ReturnValue); ok {
+		return ret.Value
+	}
+	return res
+}
+
+// collectDefinitions binds all function, struct and enum definitions in
+// advance so that they can be used before they are defined.
+func (e *Evaluator) collectDefinitions(ns []parser.Statement, env *Env) {
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.FunDefStatement:
+			e.funDefStmt(n, env)
+		case *parser.StructDefStatement:
+			e.structDefStmt(n, env)
+		case *parser.EnumDefStatement:
+			e.enumDefStmt(n, env)
+		}
+	}
+}
+
+func (e *Evaluator) stmts(ns []parser.Statement, env *Env) Object {
+	var res Object = nil
+	for _, s := range ns {
+		res = e.stmt(s, env)
+		switch res.(type) {
+		case *ReturnValue, *Break, *Continue, *Error:
+			return res
+		}
+	}
+	return res
+}
+
+func (e *Evaluator) stmt(n parser.Statement, env *Env) Object {
+	switch n := n.(type) {
+	case *parser.LetStatement:
+		return e.letStmt(n, env)
+	case *parser.AssignStatement:
+		return e.assignStmt(n, env)
+	case *parser.FunDefStatement:
+		return e.funDefStmt(n, env)
+	case *parser.StructDefStatement:
+		return e.structDefStmt(n, env)
+	case *parser.EnumDefStatement:
+		return e.enumDefStmt(n, env)
+	case *parser.BlockStatement:
+		return e.blockStmt(n, env)
+	case *parser.IfStatement:
+		return e.ifStmt(n, env)
+	case *parser.WhileStatement:
+		return e.whileStmt(n, env)
+	case *parser.ForStatement:
+		return e.forStmt(n, env)
+	case *parser.BreakStatement:
+		return &Break{}
+	case *parser.ContinueStatement:
+		return &Continue{}
+	case *parser.ReturnStatement:
+		return e.returnStmt(n, env)
+	case *parser.ExpressionStatement:
+		return e.expr(n.Value, env)
+	}
+	return newError("Unsupported statement [%s].", n)
+}
+
+func (e *Evaluator) letStmt(n *parser.LetStatement, env *Env) Object {
+	val := e.expr(n.Value, env)
+	if isError(val) {
+		return val
+	}
+	env.Set(n.Name.Value, conform(val, n.Type))
+	return nil
+}
+
+// conform converts integers to the integer type of the type annotation.
+// The elements of arrays are converted in place. Other values are returned
+// as is.
+func conform(val Object, t parser.TypeExpr) Object {
+	switch t := t.(type) {
+	case *parser.Identifier:
+		if i, ok := val.(*Integer); ok {
+			if kind, ok := intTypes[t.Value]; ok {
+				return i.convert(kind)
+			}
+		}
+	case *parser.ArrayType:
+		if arr, ok := val.(*Array); ok {
+			for i, elem := range arr.Elements {
+				arr.Elements[i] = conform(elem, t.Elem)
+			}
+		}
+	}
+	return val
+}
+
+// typeAt returns the i-th type annotation or nil if there is none.
+func typeAt(types []parser.TypeExpr, i int) parser.TypeExpr {
+	if i < len(types) {
+		return types[i]
+	}
+	return nil
+}
+
+// assignStmt evaluates the value before the target of the assignment.
+func (e *Evaluator) assignStmt(n *parser.AssignStatement, env *Env) Object {
+	if id, ok := n.Target.(*parser.Identifier); ok && env.IsFunction(id.Value) {
+		return newError("Cannot assign to function [%s].", id.Value)
+	}
+	val := e.expr(n.Value, env)
+	if isError(val) {
+		return val
+	}
+	cur, set, err := e.location(n.Target, env)
+	if err != nil {
+		return err
+	}
+	if n.Operator != "" {
+		val = binaryOp(n.Operator, cur, val)
+		if isError(val) {
+			return val
+		}
+	}
+	// Integers without a type get the type of the target.
+	if c, ok := cur.(*Integer); ok {
+		if v, ok := val.(*Integer); ok && v.Kind == nil {
+			val = v.convert(c.Kind)
+		}
+	}
+	return set(val)
+}
+
+// location evaluates the target of an assignment. It returns the current
+// value of the target and a function that replaces it. Assigning a field
+// replaces the struct it is selected from with a copy.
+func (e *Evaluator) location(n parser.Expression, env *Env) (Object, func(Object) Object, Object) {
+	switch n := n.(type) {
+	case *parser.Identifier:
+		val, ok := env.Get(n.Value)
+		if !ok {
+			return nil, nil, newError("Undefined identifier [%s].", n.Value)
+		}
+		return val, func(v Object) Object {
+			env.Assign(n.Value, v)
+			return nil
+		}, nil
+	case *parser.IndexExpression:
+		arr, i, err := e.element(n, env)
+		if err != nil {
+			return nil, nil, err
+		}
+		return arr.Elements[i], func(v Object) Object {
+			arr.Elements[i] = v
+			return nil
+		}, nil
+	case *parser.SelectorExpression:
+		left, set, err := e.location(n.Left, env)
+		if err != nil {
+			return nil, nil, err
+		}
+		s, i, err := field(left, n)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s.Values[i], func(v Object) Object {
+			return set(s.With(i, v))
+		}, nil
+	}
+	return nil, nil, newError("Cannot assign to [%s].", n)
+}
+
+func (e *Evaluator) funDefStmt(n *parser.FunDefStatement, env *Env) Object {
+	// Extern functions are only available in compiled programs.
+	if n.Extern {
+		return newError("Cannot evaluate extern function [%s].", n.Name.Value)
+	}
+	env.SetFunction(n.Name.Value, &Function{
+		Name:       n.Name.Value,
+		Params:     n.Params,
+		ParamTypes: n.ParamTypes,
+		Result:     n.Result,
+		Body:       n.Body,
+		Env:        env,
+	})
+	return nil
+}
+
+// funLit creates a closure. The function body sees the variables of the
+// environment the literal is evaluated in, even after the enclosing
+// function has returned.
+func (e *Evaluator) funLit(n *parser.FunctionLiteral, env *Env) Object {
+	return &Function{Params: n.Params, ParamTypes: n.ParamTypes, Result: n.Result, Body: n.Body, Env: env}
+}
+
+func (e *Evaluator) structDefStmt(n *parser.StructDefStatement, env *Env) Object {
+	fields := make([]string, len(n.Fields))
+	for i, id := range n.Fields {
+		fields[i] = id.Value
+	}
+	env.Set(n.Name.Value, &StructDef{Name: n.Name.Value, Fields: fields, FieldTypes: n.FieldTypes})
+	return nil
+}
+
+// enumDefStmt binds the enum and its variants.
+func (e *Evaluator) enumDefStmt(n *parser.EnumDefStatement, env *Env) Object {
+	def := &EnumDef{Name: n.Name.Value}
+	for _, v := range n.Variants {
+		variant := &VariantDef{Name: v.Name.Value, FieldTypes: v.FieldTypes}
+		for _, id := range v.Fields {
+			variant.Fields = append(variant.Fields, id.Value)
+		}
+		def.Variants = append(def.Variants, variant)
+		if len(variant.Fields) == 0 {
+			env.Set(variant.Name, &EnumValue{Variant: variant})
+		} else {
+			env.Set(variant.Name, variant)
+		}
+	}
+	env.Set(def.Name, def)
+	return nil
+}
+
+func (e *Evaluator) blockStmt(n *parser.BlockStatement, env *Env) Object {
+	return e.stmts(n.Statements, NewEnclosedEnv(env))
+}
+
+func (e *Evaluator) ifStmt(n *parser.IfStatement, env *Env) Object {
+	cond, err := e.condition(n.Condition, env)
+	if err != nil {
+		return err
+	}
+	if cond {
+		return e.stmt(n.Consequence, env)
+	}
+	if n.Alternative != nil {
+		return e.stmt(n.Alternative, env)
+	}
+	return nil
+}
+
+func (e *Evaluator) whileStmt(n *parser.WhileStatement, env *Env) Object {
+	for {
+		cond, err := e.condition(n.Condition, env)
+		if err != nil {
+			return err
+		}
+		if !cond {
+			return nil
+		}
+		if res, done := loopBody(e.blockStmt(n.Body, env)); done {
+			return res
+		}
+	}
+}
+
+func (e *Evaluator) forStmt(n *parser.ForStatement, env *Env) Object {
+	// Variables declared in the init statement are only visible in the
+	// loop.
+	env = NewEnclosedEnv(env)
+	if n.Init != nil {
+		if res := e.stmt(n.Init, env); isError(res) {
+			return res
+		}
+	}
+	for {
+		if n.Condition != nil {
+			cond, err := e.condition(n.Condition, env)
+			if err != nil {
+				return err
+			}
+			if !cond {
+				return nil
+			}
+		}
+		if res, done := loopBody(e.blockStmt(n.Body, env)); done {
+			return res
+		}
+		if n.Post != nil {
+			if res := e.stmt(n.Post, env); isError(res) {
+				return res
+			}
+		}
+	}
+}
+
+// loopBody interprets the result of a loop body. It returns true if the
+// loop is done and the result that the loop statement evaluates to.
+func loopBody(res Object) (Object, bool) {
+	switch res.(type) {
+	case *Break:
+		return nil, true
+	case *ReturnValue, *Error:
+		return res, true
+	}
+	return nil, false
+}
+
+// condition evaluates the condition of an if statement or loop.
+func (e *Evaluator) condition(n parser.Expression, env *Env) (bool, Object) {
+	cond := e.expr(n, env)
+	if isError(cond) {
+		return false, cond
+	}
+	b, ok := cond.(*Boolean)
+	if !ok {
+		return false, newError("Condition [%s] is not a boolean.", n)
+	}
+	return b.Value, nil
+}
+
+func (e *Evaluator) returnStmt(n *parser.ReturnStatement, env *Env) Object {
+	val := e.expr(n.Value, env)
+	if isError(val) {
+		return val
+	}
+	return &ReturnValue{Value: val}
+}
+
+func (e *Evaluator) expr(n parser.Expression, env *Env) Object {
+	switch n := n.(type) {
+	case *parser.Boolean:
+		return nativeBool(n.Value)
+	case *parser.Integer:
+		return literal(n.Value)
+	case *parser.Float:
+		return &Float{Value: n.Value}
+	case *parser.String:
+		return &String{Value: n.Value}
+	case *parser.Identifier:
+		return e.identifier(n, env)
+	case *parser.CallExpression:
+		return e.callExpr(n, env)
+	case *parser.FunctionLiteral:
+		return e.funLit(n, env)
+	case *parser.ArrayLiteral:
+		return e.arrayLit(n, env)
+	case *parser.IndexExpression:
+		return e.indexExpr(n, env)
+	case *parser.StructLiteral:
+		return e.structLit(n, env)
+	case *parser.SelectorExpression:
+		return e.selector(n, env)
+	case *parser.MatchExpression:
+		return e.match(n, env)
+	case *parser.BinaryExpression:
+		return e.binaryExpr(n, env)
+	case *parser.PrefixExpression:
+		return e.prefixExpr(n, env)
+	}
+	return newError("Unsupported expression [%s].", n)
+}
+
+func (e *Evaluator) identifier(n *parser.Identifier, env *Env) Object {
+	if val, ok := env.Get(n.Value); ok {
+		return val
+	}
+	if builtin, ok := builtins[n.Value]; ok {
+		return builtin
+	}
+	return newError("Undefined identifier [%s].", n.Value)
+}
+
+func (e *Evaluator) callExpr(n *parser.CallExpression, env *Env) Object {
+	callee := e.expr(n.Function, env)
+	if isError(callee) {
+		return callee
+	}
+	if builtin, ok := callee.(*Builtin); ok {
+		return e.builtinCall(builtin, n, env)
+	}
+	if variant, ok := callee.(*VariantDef); ok {
+		return e.variantCall(variant, n, env)
+	}
+	fun, ok := callee.(*Function)
+	if !ok {
+		return newError("[%s] is not a function.", n.Function)
+	}
+	if len(n.Args) != len(fun.Params) {
+		return newError("Function [%s] expects [%d] arguments but got [%d].", n.Function, len(fun.Params), len(n.Args))
+	}
+	// Arguments are evaluated in the caller's environment and bound in a
+	// new environment enclosed by the function's defining environment.
+	funEnv := NewEnclosedEnv(fun.Env)
+	for i, arg := range n.Args {
+		val := e.expr(arg, env)
+		if isError(val) {
+			return val
+		}
+		funEnv.Set(fun.Params[i].Value, conform(val, typeAt(fun.ParamTypes, i)))
+	}
+	res := e.stmts(fun.Body.Statements, funEnv)
+	if ret, ok := res.(*ReturnValue); ok {
+		return conform(ret.Value, fun.Result)
+	}
+	if isError(res) {
+		return res
+	}
+	return newError("Function [%s] did not return a value.", n.Function)
+}
+
+func (e *Evaluator) arrayLit(n *parser.ArrayLiteral, env *Env) Object {
+	elems := make([]Object, len(n.Elements))
+	for i, elem := range n.Elements {
+		elems[i] = e.expr(elem, env)
+		if isError(elems[i]) {
+			return elems[i]
+		}
+	}
+	return &Array{Elements: elems}
+}
+
+func (e *Evaluator) indexExpr(n *parser.IndexExpression, env *Env) Object {
+	arr, i, err := e.element(n, env)
+	if err != nil {
+		return err
+	}
+	return arr.Elements[i]
+}
+
+// element evaluates the array and the index of the index expression and
+// checks that the index is within the bounds of the array.
+func (e *Evaluator) element(n *parser.IndexExpression, env *Env) (*Array, int64, Object) {
+	left := e.expr(n.Left, env)
+	if isError(left) {
+		return nil, 0, left
+	}
+	index := e.expr(n.Index, env)
+	if isError(index) {
+		return nil, 0, index
+	}
+	arr, ok := left.(*Array)
+	if !ok {
+		return nil, 0, newError("Cannot index [%s].", left.Inspect())
+	}
+	i, ok := index.(*Integer)
+	if !ok {
+		return nil, 0, newError("Index [%s] is not an integer.", index.Inspect())
+	}
+	if i.Value < 0 || i.Value >= int64(len(arr.Elements)) {
+		return nil, 0, newError("%s: Index [%d] is out of range for length [%d].", n.Pos(), i.Value, len(arr.Elements))
+	}
+	return arr, i.Value, nil
+}
+
+// structLit evaluates the values in the order of the literal and stores
+// them in the order of the fields of the struct.
+func (e *Evaluator) structLit(n *parser.StructLiteral, env *Env) Object {
+	obj, ok := env.Get(n.Name.Value)
+	def, isDef := obj.(*StructDef)
+	if !ok || !isDef {
+		return newError("[%s] is not a struct.", n.Name.Value)
+	}
+	values := make([]Object, len(def.Fields))
+	for i, id := range n.Fields {
+		j := def.FieldIndex(id.Value)
+		if j < 0 {
+			return newError("Struct [%s] has no field [%s].", def.Name, id.Value)
+		}
+		values[j] = e.expr(n.Values[i], env)
+		if isError(values[j]) {
+			return values[j]
+		}
+		values[j] = conform(values[j], typeAt(def.FieldTypes, j))
+	}
+	for i, f := range def.Fields {
+		if values[i] == nil {
+			return newError("Missing field [%s] in literal of struct [%s].", f, def.Name)
+		}
+	}
+	return &Struct{Def: def, Values: values}
+}
+
+func (e *Evaluator) selector(n *parser.SelectorExpression, env *Env) Object {
+	left := e.expr(n.Left, env)
+	if isError(left) {
+		return left
+	}
+	s, i, err := field(left, n)
+	if err != nil {
+		return err
+	}
+	return s.Values[i]
+}
+
+// field returns the struct and the index of the field the selector
+// expression refers to.
+func field(left Object, n *parser.SelectorExpression) (*Struct, int, Object) {
+	s, ok := left.(*Struct)
+	if !ok {
+		return nil, 0, newError("Cannot select field [%s] of [%s].", n.Field.Value, left.Inspect())
+	}
+	i := s.Def.FieldIndex(n.Field.Value)
+	if i < 0 {
+		return nil, 0, newError("Struct [%s] has no field [%s].", s.Def.Name, n.Field.Value)
+	}
+	return s, i, nil
+}
+
+func (e *Evaluator) variantCall(variant *VariantDef, n *parser.CallExpression, env *Env) Object {
+	if len(n.Args) != len(variant.Fields) {
+		return newError("Variant [%s] expects [%d] values but got [%d].", variant.Name, len(variant.Fields), len(n.Args))
+	}
+	values := make([]Object, len(n.Args))
+	for i, arg := range n.Args {
+		values[i] = e.expr(arg, env)
+		if isError(values[i]) {
+			return values[i]
+		}
+		values[i] = conform(values[i], typeAt(variant.FieldTypes, i))
+	}
+	return &EnumValue{Variant: variant, Values: values}
+}
+
+// match evaluates the body of the first arm whose pattern matches the
+// value. The fields of the variant are bound in a new environment.
+func (e *Evaluator) match(n *parser.MatchExpression, env *Env) Object {
+	val := e.expr(n.Value, env)
+	if isError(val) {
+		return val
+	}
+	ev, ok := val.(*EnumValue)
+	if !ok {
+		return newError("Cannot match [%s].", val.Inspect())
+	}
+	for _, arm := range n.Arms {
+		if !arm.IsWildcard() && e.variant(arm.Pattern, env) != ev.Variant {
+			continue
+		}
+		armEnv := NewEnclosedEnv(env)
+		for i, id := range arm.Bindings {
+			if i < len(ev.Values) {
+				armEnv.Set(id.Value, ev.Values[i])
+			}
+		}
+		return e.expr(arm.Body, armEnv)
+	}
+	return newError("No pattern matches [%s].", ev.Inspect())
+}
+
+// variant returns the definition of the variant the pattern refers to or
+// nil if it does not refer to a variant.
+func (e *Evaluator) variant(id *parser.Identifier, env *Env) *VariantDef {
+	switch obj, _ := env.Get(id.Value); obj := obj.(type) {
+	case *VariantDef:
+		return obj
+	case *EnumValue:
+		return obj.Variant
+	}
+	return nil
+}
+
+func (e *Evaluator) builtinCall(builtin *Builtin, n *parser.CallExpression, env *Env) Object {
+	args := make([]Object, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = e.expr(arg, env)
+		if isError(args[i]) {
+			return args[i]
+		}
+	}
+	return builtin.Fn(e, args...)
+}
+
+func (e *Evaluator) binaryExpr(n *parser.BinaryExpression, env *Env) Object {
+	l := e.expr(n.Left, env)
+	if isError(l) {
+		return l
+	}
+
+	// The right hand side of && and || is only evaluated if the left hand
+	// side does not determine the result already.
+	switch n.Operator {
+	case token.CONJ, token.DISJ:
+		lb, ok := l.(*Boolean)
+		if !ok {
+			return newError("Operator [%s] expects boolean operands.", n.Operator)
+		}
+		if lb.Value == (n.Operator == token.DISJ) {
+			return lb
+		}
+		r := e.expr(n.Right, env)
+		if isError(r) {
+			return r
+		}
+		if _, ok := r.(*Boolean); !ok {
+			return newError("Operator [%s] expects boolean operands.", n.Operator)
+		}
+		return r
+	}
+
+	r := e.expr(n.Right, env)
+	if isError(r) {
+		return r
+	}
+	return binaryOp(n.Operator, l, r)
+}
+
+// binaryOp applies the binary operator to the values. Both operands have
+// been evaluated already.
+func binaryOp(op token.TokenType, l, r Object) Object {
+	switch l := l.(type) {
+	case *Integer:
+		if r, ok := r.(*Integer); ok {
+			return intOp(op, l, r)
+		}
+	case *Float:
+		if r, ok := r.(*Float); ok {
+			return floatOp(op, l.Value, r.Value)
+		}
+	case *Boolean:
+		if r, ok := r.(*Boolean); ok {
+			return boolOp(op, l.Value, r.Value)
+		}
+	}
+	return newError("Operator [%s] is not defined for [%s] and [%s].", op, l.Type(), r.Type())
+}
+
+// intOp applies the operator to integers. Both operands are converted to
+// the type of the typed operand first. The result wraps around to the range
+// of the type. Shifts by at least the size of the type shift out all bits.
+func intOp(op token.TokenType, l, r *Integer) Object {
+	kind := l.Kind
+	if kind == nil {
+		kind = r.Kind
+	}
+	l, r = l.convert(kind), r.convert(kind)
+	x, y := l.bigInt(), r.bigInt()
+	bits, _ := l.bits()
+	mod := new(big.Int).Lsh(big.NewInt(1), bits)
+	res := new(big.Int)
+	switch op {
+	case token.PLUS:
+		res.Add(x, y)
+	case token.MINUS:
+		res.Sub(x, y)
+	case token.TIMES:
+		res.Mul(x, y)
+	case token.DIV:
+		if y.Sign() == 0 {
+			return newError("Division by zero.")
+		}
+		res.Quo(x, y)
+	case token.MOD:
+		if y.Sign() == 0 {
+			return newError("Division by zero.")
+		}
+		res.Rem(x, y)
+	case token.POW:
+		if y.Sign() < 0 {
+			return newError("Negative exponent [%s].", r.Inspect())
+		}
+		res.Exp(new(big.Int).Mod(x, mod), y, mod)
+
+	case token.AND:
+		res.And(x, y)
+	case token.OR:
+		res.Or(x, y)
+	case token.XOR:
+		res.Xor(x, y)
+	case token.NAND:
+		res.Not(res.And(x, y))
+	case token.NOR:
+		res.Not(res.Or(x, y))
+	case token.XNOR:
+		res.Not(res.Xor(x, y))
+
+	case token.SLL:
+		res.Lsh(x, shift(y, bits))
+	case token.SRL:
+		res.Rsh(res.Mod(x, mod), shift(y, bits))
+	case token.SRA:
+		// Unsigned values are not negative and shifted in logically.
+		res.Rsh(x, shift(y, bits))
+	case token.ROL, token.ROR:
+		n := uint(new(big.Int).Mod(y, big.NewInt(int64(bits))).Uint64())
+		if op == token.ROR {
+			n = (bits - n) % bits
+		}
+		u := new(big.Int).Mod(x, mod)
+		hi := new(big.Int).Lsh(u, n)
+		lo := new(big.Int).Rsh(u, bits-n)
+		res.Or(hi, lo)
+
+	case token.EQU:
+		return nativeBool(x.Cmp(y) == 0)
+	case token.NEQ:
+		return nativeBool(x.Cmp(y) != 0)
+	case token.LT:
+		return nativeBool(x.Cmp(y) < 0)
+	case token.LE:
+		return nativeBool(x.Cmp(y) <= 0)
+	case token.GT:
+		return nativeBool(x.Cmp(y) > 0)
+	case token.GE:
+		return nativeBool(x.Cmp(y) >= 0)
+	default:
+		return newError("Operator [%s] is not defined for [%s].", op, INTEGER)
+	}
+	return newInteger(res, kind)
+}
+
+// shift returns the shift amount. Amounts that are negative or not less
+// than the size of the type are clamped to the size.
+func shift(y *big.Int, bits uint) uint {
+	if y.Sign() < 0 || y.Cmp(big.NewInt(int64(bits))) >= 0 {
+		return bits
+	}
+	return uint(y.Uint64())
+}
+
+// floatOp applies the operator to floating-point numbers. Division by zero
+// results in an infinity or NaN as IEEE 754 requires. The remainder has the
+// sign of the dividend like C's fmod.
+func floatOp(op token.TokenType, l, r float64) Object {
+	switch op {
+	case token.PLUS:
+		return &Float{Value: l + r}
+	case token.MINUS:
+		return &Float{Value: l - r}
+	case token.TIMES:
+		return &Float{Value: l * r}
+	case token.DIV:
+		return &Float{Value: l / r}
+	case token.MOD:
+		return &Float{Value: math.Mod(l, r)}
+	case token.POW:
+		return &Float{Value: math.Pow(l, r)}
+
+	case token.EQU:
+		return nativeBool(l == r)
+	case token.NEQ:
+		return nativeBool(l != r)
+	case token.LT:
+		return nativeBool(l < r)
+	case token.LE:
+		return nativeBool(l <= r)
+	case token.GT:
+		return nativeBool(l > r)
+	case token.GE:
+		return nativeBool(l >= r)
+	}
+	return newError("Operator [%s] is not defined for [%s].", op, FLOAT)
+}
+
+func boolOp(op token.TokenType, l, r bool) Object {
+	switch op {
+	case token.EQU:
+		return nativeBool(l == r)
+	case token.NEQ:
+		return nativeBool(l != r)
+	}
+	return newError("Operator [%s] is not defined for [%s].", op, BOOLEAN)
+}
+
+func (e *Evaluator) prefixExpr(n *parser.PrefixExpression, env *Env) Object {
+	v := e.expr(n.Value, env)
+	if isError(v) {
+		return v
+	}
+	switch v := v.(type) {
+	case *Integer:
+		switch n.Operator {
+		case token.MINUS:
+			if v.Kind == nil && v.Big != nil {
+				return literal(new(big.Int).Neg(v.Big))
+			}
+			return newInteger(new(big.Int).Neg(v.bigInt()), v.Kind)
+		case token.INV:
+			return newInteger(new(big.Int).Not(v.bigInt()), v.Kind)
+		}
+	case *Float:
+		switch n.Operator {
+		case token.MINUS:
+			return &Float{Value: -v.Value}
+		}
+	case *Boolean:
+		switch n.Operator {
+		case token.NOT:
+			return nativeBool(!v.Value)
+		}
+	}
+	return newError("Operator [%s] is not defined for [%s].", n.Operator, v.Type())
+}
+
+func nativeBool(b bool) *Boolean {
+	if b {
+		return _true
+	}
+	return _false
+}
+
+func newError(format string, a ...any) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...)}
+}
+
+func isError(o Object) bool {
+	_, ok := o.(*Error)
+	return ok
+}
@@ -0,0 +1,341 @@
+package eval_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mhoertnagl/donkey/eval"
+	"github.com/mhoertnagl/donkey/lexer"
+	"github.com/mhoertnagl/donkey/parser"
+)
+
+func TestLiterals(t *testing.T) {
+	test(t, "0;", "0")
+	test(t, "42;", "42")
+	test(t, "true;", "true")
+	test(t, "false;", "false")
+}
+
+func TestPrefixExpressions(t *testing.T) {
+	test(t, "-15;", "-15")
+	test(t, "- -15;", "15")
+	test(t, "~0;", "-1")
+	test(t, "!true;", "false")
+	test(t, "!!true;", "true")
+	test(t, "!0;", "ERROR: Operator [!] is not defined for [INTEGER].")
+	test(t, "-true;", "ERROR: Operator [-] is not defined for [BOOLEAN].")
+}
+
+func TestBinaryExpressions(t *testing.T) {
+	test(t, "1 + 2;", "3")
+	test(t, "1 - 2;", "-1")
+	test(t, "3 * 4;", "12")
+	test(t, "7 / 2;", "3")
+	test(t, "1 / 0;", "ERROR: Division by zero.")
+	test(t, "7 % 3;", "1")
+	test(t, "-7 % 3;", "-1")
+	test(t, "7 % -3;", "1")
+	test(t, "1 % 0;", "ERROR: Division by zero.")
+	test(t, "2 ** 10;", "1024")
+	test(t, "2 ** 3 ** 2;", "512")
+	test(t, "-2 ** 2;", "-4")
+	test(t, "(-2) ** 3;", "-8")
+	test(t, "5 ** 0;", "1")
+	test(t, "2 ** 64;", "0")
+	test(t, "2 ** -1;", "ERROR: Negative exponent [-1].")
+	test(t, "let e = 3; 2 ** e; e = -1; 2 ** e;", "ERROR: Negative exponent [-1].")
+	test(t, "12 & 10;", "8")
+	test(t, "12 | 10;", "14")
+	test(t, "12 ^ 10;", "6")
+	test(t, "12 ~& 10;", "-9")
+	test(t, "12 ~| 10;", "-15")
+	test(t, "12 ~^ 10;", "-7")
+	test(t, "1 << 4;", "16")
+	test(t, "-16 >> 60;", "15")
+	test(t, "-16 >>> 2;", "-4")
+	test(t, "1 <<> 63;", "-9223372036854775808")
+	test(t, "(1 <<> 63) <<> 1;", "1")
+	test(t, "1 <>> 1;", "-9223372036854775808")
+	test(t, "3 <>> 64;", "3")
+	test(t, "0xFF & 0b1010;", "10")
+	test(t, "0x8000_0000_0000_0000 == -9223372036854775808;", "true")
+	test(t, "0xFFFF_FFFF_FFFF_FFFF >> 60;", "15")
+
+	test(t, "1 == 1;", "true")
+	test(t, "1 != 1;", "false")
+	test(t, "1 < 2;", "true")
+	test(t, "2 <= 1;", "false")
+	test(t, "2 > 1;", "true")
+	test(t, "1 >= 1;", "true")
+	test(t, "true == false;", "false")
+	test(t, "true != false;", "true")
+
+	test(t, "true && false;", "false")
+	test(t, "false || true;", "true")
+	test(t, "1 + true;", "ERROR: Operator [+] is not defined for [INTEGER] and [BOOLEAN].")
+	test(t, "true < false;", "ERROR: Operator [<] is not defined for [BOOLEAN].")
+}
+
+func TestShortCircuit(t *testing.T) {
+	test(t, "false && 1 / 0 == 1;", "false")
+	test(t, "true || 1 / 0 == 1;", "true")
+	test(t, "true && 1 / 0 == 1;", "ERROR: Division by zero.")
+}
+
+func TestLetStatements(t *testing.T) {
+	test(t, "let a = 5; a;", "5")
+	test(t, "let a = 5; let b = a * 2; b;", "10")
+	test(t, "a;", "ERROR: Undefined identifier [a].")
+}
+
+func TestAssignStatements(t *testing.T) {
+	test(t, "let a = 5; a = a + 1; a;", "6")
+	test(t, "let a = 5; if true { a = 7; } a;", "7")
+	test(t, "let a = 1; fn f() { return 2; } if true { let a = 2; a = 3; } a;", "1")
+	test(t, "a = 1;", "ERROR: Undefined identifier [a].")
+	test(t, "fn f() { return 1; } f = 2;", "ERROR: Cannot assign to function [f].")
+}
+
+func TestCompoundAssignStatements(t *testing.T) {
+	test(t, "let a = 5; a += 2; a;", "7")
+	test(t, "let a = 5; a -= 2; a *= 3; a /= 2; a;", "4")
+	test(t, "let a = 12; a &= 10; a |= 1; a ^= 3; a;", "10")
+	test(t, "let a = 1; a <<= 4; a >>= 1; a;", "8")
+	test(t, "let a = -16; a >>>= 2; a;", "-4")
+	test(t, "let a = 1; a <>>= 1; a <<>= 2; a;", "2")
+	test(t, "let a = 17; a %= 5; a **= 3; a;", "8")
+	test(t, "let a = 2; a **= -1;", "ERROR: Negative exponent [-1].")
+	test(t, "let a = 1; a++; a++; a--; a;", "2")
+	test(t, "let a = [1, 2]; a[0]--; a[0];", "0")
+	test(t, "let a = [1, 2]; a[1] += 10; a[1];", "12")
+	test(t, "struct P { x } let p = P { x: 1 }; p.x -= 3; p.x;", "-2")
+	test(t, "let a = 1; a /= 0;", "ERROR: Division by zero.")
+	test(t, "let a = true; a += 1;", "ERROR: Operator [+] is not defined for [BOOLEAN] and [INTEGER].")
+	test(t, "a += 1;", "ERROR: Undefined identifier [a].")
+}
+
+func TestIfStatements(t *testing.T) {
+	test(t, "if true { 1; }", "1")
+	test(t, "if false { 1; }", "")
+	test(t, "if 1 < 2 { 1; } else { 2; }", "1")
+	test(t, "if 1 > 2 { 1; } else { 2; }", "2")
+	test(t, "if false { 1; } else if true { 2; } else { 3; }", "2")
+	test(t, "if 1 { 1; }", "ERROR: Condition [1] is not a boolean.")
+	test(t, "if true { let a = 1; } a;", "ERROR: Undefined identifier [a].")
+}
+
+func TestLoops(t *testing.T) {
+	test(t, "let i = 0; let s = 0; while i < 10 { i = i + 1; s = s + i; } s;", "55")
+	test(t, "let s = 0; for let i = 0; i < 10; i = i + 1 { s = s + i; } s;", "45")
+	test(t, "let s = 0; for let i = 0; i < 10; i = i + 1 { if i == 3 { continue; } if i > 6 { break; } s = s + i; } s;", "18")
+	test(t, "let n = 0; for ;; { n = n + 1; if n == 5 { break; } } n;", "5")
+	test(t, "for let i = 0; i < 1; i = i + 1 { } i;", "ERROR: Undefined identifier [i].")
+	test(t, "fn f() { while true { return 7; } } f();", "7")
+	test(t, "while 1 { }", "ERROR: Condition [1] is not a boolean.")
+}
+
+func TestFunctions(t *testing.T) {
+	test(t, "fn one() { return 1; } one();", "1")
+	test(t, "fn add(a, b) { return a + b; } add(1, 2);", "3")
+	test(t, "fn main() { return foo(3); } fn foo(a) { return a + 1; } main();", "4")
+	test(t, "let a = foo(3); fn foo(a) { return a + 1; } a;", "4")
+	test(t, "fn fib(n) { if n < 2 { return n; } return fib(n - 1) + fib(n - 2); } fib(15);", "610")
+	test(t, "fn max(a, b) { if a > b { return a; } else { return b; } } max(3, 7);", "7")
+	test(t, "fn foo(a) { return a; } foo();", "ERROR: Function [foo] expects [1] arguments but got [0].")
+	test(t, "fn foo() { 1; } foo();", "ERROR: Function [foo] did not return a value.")
+	test(t, "let a = 1; a();", "ERROR: [a] is not a function.")
+	test(t, "return 1; 2;", "1")
+}
+
+func TestClosures(t *testing.T) {
+	test(t, "let add = fn(a, b) { return a + b; }; add(1, 2);", "3")
+	test(t, "fn(a) { return a * 2; }(21);", "42")
+	test(t, "fn adder(a) { return fn(b) { return a + b; }; } let inc = adder(1); inc(41);", "42")
+	test(t, "fn apply(f, x) { return f(x); } apply(fn(a) { return a * a; }, 7);", "49")
+	test(t, "fn twice(f) { return fn(x) { return f(f(x)); }; } twice(fn(x) { return x + 3; })(1);", "7")
+	test(t, "fn counter() { let n = 0; return fn() { n = n + 1; return n; }; } let c = counter(); c(); c(); c();", "3")
+	test(t, "let a = 1; let f = fn() { return a; }; a = 2; f();", "2")
+	test(t, "fn one() { return 1; } let f = one; f = fn() { return 2; }; f();", "2")
+	test(t, "fn one() { return 1; } one = fn() { return 2; };", "ERROR: Cannot assign to function [one].")
+	test(t, "let f = fn(a) { return a; }; f();", "ERROR: Function [f] expects [1] arguments but got [0].")
+	test(t, "fn(a, b) { return a; };", "fn(a, b)")
+}
+
+func TestArrays(t *testing.T) {
+	test(t, "[1, 2 + 3, 4];", "[1, 5, 4]")
+	test(t, "[];", "[]")
+	test(t, "let a = [1, 2, 3]; a[1];", "2")
+	test(t, "let a = [[1], [2, 3]]; a[1][0];", "2")
+	test(t, "let a = [1, 2, 3]; a[0] = 7; a;", "[7, 2, 3]")
+	test(t, "let a = [1]; let b = a; b[0] = 2; a[0];", "2")
+	test(t, "fn sum(xs) { let s = 0; for let i = 0; i < len(xs); i = i + 1 { s = s + xs[i]; } return s; } sum([1, 2, 3]);", "6")
+	test(t, `len("abc") + len([1, 2]);`, "5")
+	test(t, "let a = [1, 2, 3];\na[3];", "ERROR: 2:1: Index [3] is out of range for length [3].")
+	test(t, "let a = [1]; a[-1] = 0;", "ERROR: 1:14: Index [-1] is out of range for length [1].")
+	test(t, "let a = 1; a[0];", "ERROR: Cannot index [1].")
+	test(t, "len(1);", "ERROR: Cannot take the length of [1].")
+}
+
+func TestStructs(t *testing.T) {
+	test(t, "struct P { x, y } P { y: 1, x: 2 };", "P { x: 2, y: 1 }")
+	test(t, "let p = P { x: 1, y: 2 }; struct P { x, y } p.x + p.y;", "3")
+	test(t, "struct P { x, y } let p = P { x: 1, y: 2 }; let q = p; q.x = 5; p.x * 10 + q.x;", "15")
+	test(t, "struct P { x } struct L { a, b } let l = L { a: P { x: 1 }, b: P { x: 2 } }; l.b.x = 7; l;", "L { a: P { x: 1 }, b: P { x: 7 } }")
+	test(t, "struct P { x } let ps = [P { x: 1 }]; ps[0].x = 3; ps;", "[P { x: 3 }]")
+	test(t, "struct P { x } fn inc(p) { p.x = p.x + 1; return p; } let p = P { x: 1 }; inc(p).x * 10 + p.x;", "21")
+	test(t, "struct P { x } P;", "struct P { x }")
+	test(t, "struct P { x } P { x: 1 }.y;", "ERROR: Struct [P] has no field [y].")
+	test(t, "let a = 1; a.x = 2;", "ERROR: Cannot select field [x] of [1].")
+	test(t, "struct P { x, y } P { x: 1 };", "ERROR: Missing field [y] in literal of struct [P].")
+}
+
+func TestEnums(t *testing.T) {
+	test(t, "enum E { A(x, y), B } A(1, 2 + 3);", "A(1, 5)")
+	test(t, "enum E { A(x, y), B } B;", "B")
+	test(t, "enum Shape { Circle(r), Rect(w, h) } Shape;", "enum Shape { Circle(r), Rect(w, h) }")
+	test(t, "fn area(s) { return match s { Circle(r) => 3 * r * r, Rect(w, h) => w * h }; } enum Shape { Circle(r), Rect(w, h) } area(Circle(2)) + area(Rect(3, 4));", "24")
+	test(t, "enum E { A, B, C } let r = 0; for let i = 0; i < 3; i = i + 1 { r = r * 10 + match [A, B, C][i] { B => 2, _ => 1 }; } r;", "121")
+	test(t, "enum E { A(x) } let x = 1; match A(2) { A(x) => x } * 10 + x;", "21")
+	test(t, "enum E { A(x) } A(1, 2);", "ERROR: Variant [A] expects [1] values but got [2].")
+	test(t, "match 1 { _ => 1 };", "ERROR: Cannot match [1].")
+	test(t, "enum E { A, B } match B { A => 1 };", "ERROR: No pattern matches [B].")
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	test(t, "let a: u8 = 200; fn f(x: u8) -> u8 { return x + 1; } f(a);", "201")
+	test(t, "struct P { x: i32 } P { x: 1 };", "P { x: 1 }")
+	test(t, "u8(300);", "44")
+	test(t, "u8(-1);", "255")
+	test(t, "i8(200);", "-56")
+	test(t, "i16(-5);", "-5")
+	test(t, "u64(-1) == i64(-1);", "true")
+	test(t, "u8(true);", "ERROR: Cannot convert [true] to [u8].")
+}
+
+func TestIntegerTypes(t *testing.T) {
+	test(t, "let x: u8 = 200; x + 100;", "44")
+	test(t, "let x: u8 = 200; x += 100; x;", "44")
+	test(t, "let x: u8 = 0; x = 300; x;", "44")
+	test(t, "i8(127) + 1;", "-128")
+	test(t, "u64(18446744073709551615);", "18446744073709551615")
+	test(t, "u64(18446744073709551615) / 2;", "9223372036854775807")
+	test(t, "u64(18446744073709551615) % 10;", "5")
+	test(t, "let u = u64(18446744073709551615); u > 1;", "true")
+	test(t, "u32(1) < u32(-1);", "true")
+	test(t, "i32(-1) < i32(1);", "true")
+	test(t, "u8(240) >> 4;", "15")
+	test(t, "u8(240) >>> 4;", "15")
+	test(t, "i8(-16) >>> 2;", "-4")
+	test(t, "i8(-16) >> 2;", "60")
+	test(t, "u8(1) << 9;", "0")
+	test(t, "u8(129) <<> 1;", "3")
+	test(t, "u8(3) <>> 1;", "129")
+	test(t, "~u8(0);", "255")
+	test(t, "-u16(1);", "65535")
+	test(t, "u8(3) ** 5;", "243")
+	test(t, "i16(3) ** 11;", "-19461")
+	test(t, "let m = -1; (-9223372036854775807 - 1) / m;", "-9223372036854775808")
+	test(t, "let m = -1; (-9223372036854775807 - 1) % m;", "0")
+	test(t, "i8(-128) / i8(-1);", "-128")
+	test(t, "u8(7) / u8(0);", "ERROR: Division by zero.")
+	test(t, "i128(1) << 100;", "1267650600228229401496703205376")
+	test(t, "u128(0) - 1;", "340282366920938463463374607431768211455")
+	test(t, "let m = i128(1) << 127; m - 1;", "170141183460469231731687303715884105727")
+	test(t, "let a: u128 = 18446744073709551616; a * 3;", "55340232221128654848")
+	test(t, "let a: i128 = -170141183460469231731687303715884105728; a;", "-170141183460469231731687303715884105728")
+	test(t, "u128(0xFFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF) + 2;", "1")
+	test(t, "18446744073709551617 + 1;", "2")
+	test(t, "fn f(x: u16) -> u8 { return x; } f(65535);", "255")
+	test(t, "struct P { x: u8 } let p = P { x: 255 }; p.x + 1;", "0")
+	test(t, "enum E { A(x: i8) } match A(200) { A(x) => x };", "-56")
+	test(t, "let xs: [u8] = [255, 256]; xs[0] + xs[1];", "255")
+	test(t, "u8(1.5e3);", "220")
+	test(t, "f64(u64(18446744073709551615));", "1.84467e+19")
+}
+
+func TestFloats(t *testing.T) {
+	test(t, "1.5;", "1.5")
+	test(t, "1.5e-3;", "0.0015")
+	test(t, "1e20;", "1e+20")
+	test(t, "-2.5;", "-2.5")
+	test(t, "0.1 + 0.2;", "0.3")
+	test(t, "0.1 + 0.2 == 0.3;", "false")
+	test(t, "7.5 / 2.0;", "3.75")
+	test(t, "-7.5 % 2.0;", "-1.5")
+	test(t, "2.0 ** 0.5;", "1.41421")
+	test(t, "1.0 / 0.0;", "inf")
+	test(t, "-1.0 / 0.0;", "-inf")
+	test(t, "let nan = 0.0 / 0.0; nan == nan;", "false")
+	test(t, "let nan = 0.0 / 0.0; nan != nan;", "true")
+	test(t, "1.5 < 2.5;", "true")
+	test(t, "1.5 + 1;", "ERROR: Operator [+] is not defined for [FLOAT] and [INTEGER].")
+	test(t, "1.5 & 1.0;", "ERROR: Operator [&] is not defined for [FLOAT].")
+	test(t, "~1.5;", "ERROR: Operator [~] is not defined for [FLOAT].")
+	test(t, "f64(3) / 2.0;", "1.5")
+	test(t, "int(-2.7);", "-2")
+	test(t, "u8(300.5);", "44")
+	test(t, "f32(0.1) == 0.1;", "false")
+	test(t, "f64(f32(0.5)) == 0.5;", "true")
+	test(t, "f64(true);", "ERROR: Cannot convert [true] to [f64].")
+}
+
+func TestStrings(t *testing.T) {
+	test(t, `"hello";`, "hello")
+	test(t, `"a\tb\x41\u{e9}";`, "a\tbAé")
+	test(t, `let s = "x"; s;`, "x")
+	test(t, `print;`, "builtin print")
+}
+
+func TestPrint(t *testing.T) {
+	var out bytes.Buffer
+	e := eval.NewEvaluator()
+	e.SetOutput(&out)
+	expect(t, evaluate(t, e, `print("a\n");`), "2")
+	expect(t, evaluate(t, e, `println(42);`), "3")
+	expect(t, evaluate(t, e, `println(1 > 2);`), "6")
+	expect(t, evaluate(t, e, `println(2.5);`), "4")
+	expect(t, evaluate(t, e, `println();`), "ERROR: Function [println] expects [1] arguments but got [0].")
+	expect(t, evaluate(t, e, `print(print);`), "ERROR: Cannot print [builtin print].")
+	if actual := out.String(); actual != "a\n42\nfalse\n2.5\n" {
+		t.Errorf("Expected [a\\n42\\nfalse\\n2.5\\n] but got [%q].", actual)
+	}
+}
+
+func TestExterns(t *testing.T) {
+	test(t, "extern fn putchar(c: i32) -> i32; putchar(65);", "ERROR: Cannot evaluate extern function [putchar].")
+	test(t, "export fn f(x) { return x + 1; } f(1);", "2")
+}
+
+func TestPersistentEnvironment(t *testing.T) {
+	e := eval.NewEvaluator()
+	evaluate(t, e, "let a = 2;")
+	evaluate(t, e, "fn double(x) { return x * 2; }")
+	expect(t, evaluate(t, e, "double(a);"), "4")
+}
+
+func test(t *testing.T, input string, expected string) {
+	t.Helper()
+	res := evaluate(t, eval.NewEvaluator(), input)
+	expect(t, res, expected)
+}
+
+func evaluate(t *testing.T, e *eval.Evaluator, input string) eval.Object {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	prog := p.Parse()
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", p.Errors())
+	}
+	return e.Eval(prog)
+}
+
+func expect(t *testing.T, res eval.Object, expected string) {
+	t.Helper()
+	actual := ""
+	if res != nil {
+		actual = res.Inspect()
+	}
+	if actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
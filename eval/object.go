@@ -0,0 +1,345 @@
+package eval
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mhoertnagl/donkey/parser"
+)
+
+type ObjectType string
+
+const (
+	INTEGER  ObjectType = "INTEGER"
+	FLOAT    ObjectType = "FLOAT"
+	BOOLEAN  ObjectType = "BOOLEAN"
+	STRING   ObjectType = "STRING"
+	ARRAY    ObjectType = "ARRAY"
+	STRUCT   ObjectType = "STRUCT"
+	ENUM     ObjectType = "ENUM"
+	TYPE     ObjectType = "TYPE"
+	FUNCTION ObjectType = "FUNCTION"
+	BUILTIN  ObjectType = "BUILTIN"
+	RETURN   ObjectType = "RETURN"
+	BREAK    ObjectType = "BREAK"
+	CONTINUE ObjectType = "CONTINUE"
+	ERROR    ObjectType = "ERROR"
+)
+
+type Object interface {
+	Type() ObjectType
+	Inspect() string
+}
+
+// IntType is the size in bits and the signedness of an integer type.
+type IntType struct {
+	Name     string
+	Bits     uint
+	Unsigned bool
+}
+
+// Integer is a value of an integer type. Values wrap around to the range of
+// their type like in compiled programs. Values of types of up to 64 bits are
+// held in Value. Unsigned values are stored as the int64 with the same bits.
+// Values of 128 bit types are held in Big. Integers without a type are of
+// type int unless they are combined with an integer of another type.
+// Literals outside the range of int keep their exact value in Big until
+// they are given a type.
+type Integer struct {
+	Value int64
+	Big   *big.Int
+	Kind  *IntType
+}
+
+func (o *Integer) Type() ObjectType { return INTEGER }
+func (o *Integer) Inspect() string {
+	switch {
+	case o.Big != nil:
+		return o.Big.String()
+	case o.Kind != nil && o.Kind.Unsigned:
+		return strconv.FormatUint(uint64(o.Value), 10)
+	}
+	return strconv.FormatInt(o.Value, 10)
+}
+
+// bits returns the size of the type of the integer and whether it is
+// unsigned.
+func (o *Integer) bits() (uint, bool) {
+	if o.Kind == nil {
+		return 64, false
+	}
+	return o.Kind.Bits, o.Kind.Unsigned
+}
+
+// bigInt returns the value of the integer. The result must not be
+// modified.
+func (o *Integer) bigInt() *big.Int {
+	if o.Big != nil {
+		return o.Big
+	}
+	if _, unsigned := o.bits(); unsigned {
+		return new(big.Int).SetUint64(uint64(o.Value))
+	}
+	return big.NewInt(o.Value)
+}
+
+// convert returns the integer as a value of the integer type.
+func (o *Integer) convert(kind *IntType) *Integer {
+	if o.Kind == kind && (kind != nil || o.Big == nil) {
+		return o
+	}
+	return newInteger(o.bigInt(), kind)
+}
+
+// literal returns the integer literal without a type.
+func literal(v *big.Int) *Integer {
+	if v.IsInt64() {
+		return &Integer{Value: v.Int64()}
+	}
+	return &Integer{Big: new(big.Int).Set(v)}
+}
+
+// newInteger returns the value wrapped around to the range of the integer
+// type.
+func newInteger(v *big.Int, kind *IntType) *Integer {
+	o := &Integer{Kind: kind}
+	bits, unsigned := o.bits()
+	mod := new(big.Int).Lsh(big.NewInt(1), bits)
+	w := new(big.Int).Mod(v, mod)
+	if !unsigned && w.Bit(int(bits-1)) == 1 {
+		w.Sub(w, mod)
+	}
+	switch {
+	case bits > 64:
+		o.Big = w
+	case unsigned:
+		o.Value = int64(w.Uint64())
+	default:
+		o.Value = w.Int64()
+	}
+	return o
+}
+
+// Float is an IEEE 754 double-precision number.
+type Float struct {
+	Value float64
+}
+
+func (o *Float) Type() ObjectType { return FLOAT }
+
+// Inspect formats the number like the %g verb of C's printf does so that
+// the evaluator prints the same as compiled programs.
+func (o *Float) Inspect() string {
+	switch {
+	case math.IsNaN(o.Value):
+		return "nan"
+	case math.IsInf(o.Value, 1):
+		return "inf"
+	case math.IsInf(o.Value, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(o.Value, 'g', 6, 64)
+}
+
+type Boolean struct {
+	Value bool
+}
+
+func (o *Boolean) Type() ObjectType { return BOOLEAN }
+func (o *Boolean) Inspect() string  { return fmt.Sprintf("%t", o.Value) }
+
+type String struct {
+	Value string
+}
+
+func (o *String) Type() ObjectType { return STRING }
+func (o *String) Inspect() string  { return o.Value }
+
+// Array is a sequence of values. Arrays are shared by reference.
+type Array struct {
+	Elements []Object
+}
+
+func (o *Array) Type() ObjectType { return ARRAY }
+func (o *Array) Inspect() string {
+	elems := []string{}
+	for _, e := range o.Elements {
+		elems = append(elems, e.Inspect())
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+// StructDef is the definition of a struct. It is bound to the name of the
+// struct.
+type StructDef struct {
+	Name       string
+	Fields     []string
+	FieldTypes []parser.TypeExpr
+}
+
+func (o *StructDef) Type() ObjectType { return TYPE }
+func (o *StructDef) Inspect() string {
+	return "struct " + o.Name + " { " + strings.Join(o.Fields, ", ") + " }"
+}
+
+// FieldIndex returns the index of the field with the name or -1 if the
+// struct has no such field.
+func (o *StructDef) FieldIndex(name string) int {
+	for i, f := range o.Fields {
+		if f == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Struct is a value of a struct type. Structs are values. They are never
+// modified but replaced by a copy with the new field value instead.
+type Struct struct {
+	Def    *StructDef
+	Values []Object
+}
+
+func (o *Struct) Type() ObjectType { return STRUCT }
+func (o *Struct) Inspect() string {
+	fields := []string{}
+	for i, f := range o.Def.Fields {
+		fields = append(fields, f+": "+o.Values[i].Inspect())
+	}
+	return o.Def.Name + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// With returns a copy of the struct with the value of the i-th field
+// replaced.
+func (o *Struct) With(i int, val Object) *Struct {
+	values := make([]Object, len(o.Values))
+	copy(values, o.Values)
+	values[i] = val
+	return &Struct{Def: o.Def, Values: values}
+}
+
+// EnumDef is the definition of an enum. It is bound to the name of the
+// enum.
+type EnumDef struct {
+	Name     string
+	Variants []*VariantDef
+}
+
+func (o *EnumDef) Type() ObjectType { return TYPE }
+func (o *EnumDef) Inspect() string {
+	variants := []string{}
+	for _, v := range o.Variants {
+		variants = append(variants, v.Inspect())
+	}
+	return "enum " + o.Name + " { " + strings.Join(variants, ", ") + " }"
+}
+
+// VariantDef is the definition of a variant of an enum. Variants with
+// fields are bound to their definition and construct values when they are
+// called. Variants without fields are bound to their only value.
+type VariantDef struct {
+	Name       string
+	Fields     []string
+	FieldTypes []parser.TypeExpr
+}
+
+func (o *VariantDef) Type() ObjectType { return TYPE }
+func (o *VariantDef) Inspect() string {
+	if len(o.Fields) == 0 {
+		return o.Name
+	}
+	return o.Name + "(" + strings.Join(o.Fields, ", ") + ")"
+}
+
+// EnumValue is a value of an enum. It holds the variant and the values of
+// its fields.
+type EnumValue struct {
+	Variant *VariantDef
+	Values  []Object
+}
+
+func (o *EnumValue) Type() ObjectType { return ENUM }
+func (o *EnumValue) Inspect() string {
+	if len(o.Values) == 0 {
+		return o.Variant.Name
+	}
+	values := []string{}
+	for _, v := range o.Values {
+		values = append(values, v.Inspect())
+	}
+	return o.Variant.Name + "(" + strings.Join(values, ", ") + ")"
+}
+
+// Function is a function definition or a closure. The name of closures is
+// empty.
+type Function struct {
+	Name       string
+	Params     []*parser.Identifier
+	ParamTypes []parser.TypeExpr
+	Result     parser.TypeExpr
+	Body       *parser.BlockStatement
+	Env        *Env
+}
+
+func (o *Function) Type() ObjectType { return FUNCTION }
+func (o *Function) Inspect() string {
+	params := []string{}
+	for _, id := range o.Params {
+		params = append(params, id.String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("fn")
+	if o.Name != "" {
+		buf.WriteString(" ")
+		buf.WriteString(o.Name)
+	}
+	buf.WriteString("(")
+	buf.WriteString(strings.Join(params, ", "))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+// BuiltinFunction implements a builtin function. The arguments have been
+// evaluated already.
+type BuiltinFunction func(e *Evaluator, args ...Object) Object
+
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (o *Builtin) Type() ObjectType { return BUILTIN }
+func (o *Builtin) Inspect() string  { return "builtin " + o.Name }
+
+// ReturnValue wraps the value of a return statement while it propagates
+// up to the enclosing function call.
+type ReturnValue struct {
+	Value Object
+}
+
+func (o *ReturnValue) Type() ObjectType { return RETURN }
+func (o *ReturnValue) Inspect() string  { return o.Value.Inspect() }
+
+// Break signals a break statement to the innermost enclosing loop.
+type Break struct{}
+
+func (o *Break) Type() ObjectType { return BREAK }
+func (o *Break) Inspect() string  { return "break" }
+
+// Continue signals a continue statement to the innermost enclosing loop.
+type Continue struct{}
+
+func (o *Continue) Type() ObjectType { return CONTINUE }
+func (o *Continue) Inspect() string  { return "continue" }
+
+type Error struct {
+	Message string
+}
+
+func (o *Error) Type() ObjectType { return ERROR }
+func (o *Error) Inspect() string  { return "ERROR: " + o.Message }
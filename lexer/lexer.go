@@ -1,30 +1,64 @@
 package lexer
 
 import (
-	//"fmt"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mhoertnagl/donkey/diag"
 	"github.com/mhoertnagl/donkey/token"
 )
 
 // TODO: use "text/scanner"
-// TODO: runes support.
-// TODO: track positional information.
 // TODO: turn into a library. See https://yourbasic.org/golang/inheritance-object-oriented/
 
+// Lexer splits UTF-8 encoded input into tokens.
 type Lexer struct {
+	file  *token.File
 	input string
-	len   int // Input length.
-	pos   int
-	line  int // Token line number.
-	col   int // Token column.
-	ch    byte
+	len   int // Input length in bytes.
+	pos   int // Byte offset of the current character.
+	width int // Width of the current character in bytes.
+	ch    rune
+	diags *diag.List
 }
 
 func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input, len: len(input), pos: -1, line: 1, col: 1}
+	return NewFileLexer("", input)
+}
+
+// NewFileLexer creates a lexer for the input of the named file. The file
+// name is attached to every token and diagnostic. Columns are counted in
+// runes.
+func NewFileLexer(name string, input string) *Lexer {
+	return NewLexerForFile(token.NewFile(name, input, token.Runes))
+}
+
+// NewLexerForFile creates a lexer for the source of the file. The file
+// determines the lines and columns of the tokens.
+func NewLexerForFile(file *token.File) *Lexer {
+	l := &Lexer{
+		file:  file,
+		input: file.Source(),
+		len:   file.Size(),
+		diags: diag.NewList(),
+	}
 	l.read()
 	return l
 }
 
+func (l *Lexer) File() string {
+	return l.file.Name()
+}
+
+// Diagnostics returns the list the lexer reports into. Later stages may
+// report into the same list.
+func (l *Lexer) Diagnostics() *diag.List {
+	return l.diags
+}
+
 func (l *Lexer) Next() token.Token {
 	var tok token.Token
 
@@ -37,32 +71,86 @@ func (l *Lexer) Next() token.Token {
 
 	switch {
 	case l.ch == 0:
-		tok = l.emit2(token.EOF, "")
+		tok = l.emitAt(token.EOF, l.pos)
 	case l.peeksIs("=="):
 		l.read()
 		tok = l.emit2(token.EQU, "==")
+	case l.peeksIs("=>"):
+		l.read()
+		tok = l.emit2(token.DARROW, "=>")
 	case l.ch == '=':
 		tok = l.emit(token.ASSIGN)
+	case l.peeksIs("++"):
+		l.read()
+		tok = l.emit2(token.INC, "++")
+	case l.peeksIs("+="):
+		l.read()
+		tok = l.emit2(token.PLUSEQ, "+=")
 	case l.ch == '+':
 		tok = l.emit(token.PLUS)
+	case l.peeksIs("->"):
+		l.read()
+		tok = l.emit2(token.ARROW, "->")
+	case l.peeksIs("--"):
+		l.read()
+		tok = l.emit2(token.DEC, "--")
+	case l.peeksIs("-="):
+		l.read()
+		tok = l.emit2(token.MINUSEQ, "-=")
 	case l.ch == '-':
 		tok = l.emit(token.MINUS)
+	case l.peeksIs("**="):
+		l.read()
+		l.read()
+		tok = l.emit2(token.POWEQ, "**=")
+	case l.peeksIs("**"):
+		l.read()
+		tok = l.emit2(token.POW, "**")
+	case l.peeksIs("*="):
+		l.read()
+		tok = l.emit2(token.TIMESEQ, "*=")
 	case l.ch == '*':
 		tok = l.emit(token.TIMES)
+	case l.peeksIs("/="):
+		l.read()
+		tok = l.emit2(token.DIVEQ, "/=")
 	case l.ch == '/':
 		tok = l.emit(token.DIV)
+	case l.peeksIs("%="):
+		l.read()
+		tok = l.emit2(token.MODEQ, "%=")
+	case l.ch == '%':
+		tok = l.emit(token.MOD)
+	case l.peeksIs("~&"):
+		l.read()
+		tok = l.emit2(token.NAND, "~&")
+	case l.peeksIs("~|"):
+		l.read()
+		tok = l.emit2(token.NOR, "~|")
+	case l.peeksIs("~^"):
+		l.read()
+		tok = l.emit2(token.XNOR, "~^")
 	case l.ch == '~':
 		tok = l.emit(token.INV)
 	case l.peeksIs("&&"):
 		l.read()
 		tok = l.emit2(token.CONJ, "&&")
+	case l.peeksIs("&="):
+		l.read()
+		tok = l.emit2(token.ANDEQ, "&=")
 	case l.ch == '&':
 		tok = l.emit(token.AND)
 	case l.peeksIs("||"):
 		l.read()
 		tok = l.emit2(token.DISJ, "||")
+	case l.peeksIs("|="):
+		l.read()
+		tok = l.emit2(token.OREQ, "|=")
 	case l.ch == '|':
 		tok = l.emit(token.OR)
+	case l.peeksIs("^="):
+		l.read()
+		tok = l.emit2(token.XOREQ, "^=")
 	case l.ch == '^':
 		tok = l.emit(token.XOR)
 	case l.peeksIs("!="):
@@ -70,6 +158,16 @@ func (l *Lexer) Next() token.Token {
 		tok = l.emit2(token.NEQ, "!=")
 	case l.ch == '!':
 		tok = l.emit(token.NOT)
+	case l.peeksIs("<<>="):
+		l.read()
+		l.read()
+		l.read()
+		tok = l.emit2(token.ROLEQ, "<<>=")
+	case l.peeksIs("<>>="):
+		l.read()
+		l.read()
+		l.read()
+		tok = l.emit2(token.ROREQ, "<>>=")
 	case l.peeksIs("<<>"):
 		l.read()
 		l.read()
@@ -78,6 +176,10 @@ func (l *Lexer) Next() token.Token {
 		l.read()
 		l.read()
 		tok = l.emit2(token.ROR, "<>>")
+	case l.peeksIs("<<="):
+		l.read()
+		l.read()
+		tok = l.emit2(token.SLLEQ, "<<=")
 	case l.peeksIs("<<"):
 		l.read()
 		tok = l.emit2(token.SLL, "<<")
@@ -86,10 +188,19 @@ func (l *Lexer) Next() token.Token {
 		tok = l.emit2(token.LE, "<=")
 	case l.ch == '<':
 		tok = l.emit2(token.LT, "<")
+	case l.peeksIs(">>>="):
+		l.read()
+		l.read()
+		l.read()
+		tok = l.emit2(token.SRAEQ, ">>>=")
 	case l.peeksIs(">>>"):
 		l.read()
 		l.read()
 		tok = l.emit2(token.SRA, ">>>")
+	case l.peeksIs(">>="):
+		l.read()
+		l.read()
+		tok = l.emit2(token.SRLEQ, ">>=")
 	case l.peeksIs(">>"):
 		l.read()
 		tok = l.emit2(token.SRL, ">>")
@@ -106,51 +217,68 @@ func (l *Lexer) Next() token.Token {
 		tok = l.emit(token.LBRA)
 	case l.ch == '}':
 		tok = l.emit(token.RBRA)
+	case l.ch == '[':
+		tok = l.emit(token.LBRK)
+	case l.ch == ']':
+		tok = l.emit(token.RBRK)
 	case l.ch == ',':
 		tok = l.emit(token.COMMA)
 	case l.ch == ';':
 		tok = l.emit(token.SCOLON)
-	case isAlpha(l.ch):
-		return l.readID()
-	case l.peeksIs("0x"):
-		return l.readHex()
+	case l.ch == ':':
+		tok = l.emit(token.COLON)
+	case l.peeksIs("..."):
+		l.read()
+		l.read()
+		tok = l.emit2(token.ELLIPSIS, "...")
+	case l.ch == '.':
+		tok = l.emit(token.DOT)
+	case l.ch == '_':
+		tok = l.emit(token.BLANK)
 	case isDec(l.ch):
-		return l.readDec()
+		return l.readNumber()
+	case l.ch == '"':
+		return l.readString()
+	case isLetter(l.ch):
+		return l.readID()
+	case l.ch == utf8.RuneError && l.width == 1:
+		// Invalid encodings have been reported by read.
+		tok = l.emit2(token.ILLEGAL, l.input[l.pos:l.pos+1])
 	default:
-		return l.emit(token.ILLEGAL)
+		tok = l.emit(token.ILLEGAL)
+		l.diags.TokenErrorf("L0001", tok, "Illegal character [%s].", tok.Literal)
 	}
 
 	l.read()
 	return tok
 }
 
+// read decodes the next character. Invalid UTF-8 encodings are reported
+// once for every invalid byte.
 func (l *Lexer) read() {
-	l.ch = l.peek()
-	l.pos++
-	if l.ch == '\n' {
-		l.col = 1
-		l.line++
-	} else {
-		l.col++
+	l.pos += l.width
+	l.ch, l.width = 0, 0
+	if l.pos < l.len {
+		l.ch, l.width = utf8.DecodeRuneInString(l.input[l.pos:])
+	}
+	if l.ch == utf8.RuneError && l.width == 1 {
+		tok := l.newToken(token.ILLEGAL, l.pos, l.pos+1)
+		l.diags.TokenErrorf("L0008", tok, "Invalid UTF-8 encoding [%#x].", l.input[l.pos])
 	}
 }
 
-func (l *Lexer) readWhile(pred func(byte) bool) {
+func (l *Lexer) readWhile(pred func(rune) bool) {
 	for pred(l.ch) {
 		l.read()
 	}
 }
 
-func (l *Lexer) peek() byte {
-	return l.peekAt(1)
-}
-
-func (l *Lexer) peekAt(n uint) byte {
-	posAt := l.pos + int(n)
-	if posAt >= l.len {
+func (l *Lexer) peek() rune {
+	if l.pos+l.width >= l.len {
 		return 0
 	}
-	return l.input[posAt]
+	c, _ := utf8.DecodeRuneInString(l.input[l.pos+l.width:])
+	return c
 }
 
 func (l *Lexer) peeks(n uint) string {
@@ -173,12 +301,35 @@ func (l *Lexer) emit(typ token.TokenType) token.Token {
 	return l.emit2(typ, string(l.ch))
 }
 
+// emit2 creates a token for the literal that ends with the current
+// character.
 func (l *Lexer) emit2(typ token.TokenType, literal string) token.Token {
+	end := l.pos + l.width
+	return l.newToken(typ, end-len(literal), end)
+}
+
+// emitAt creates a token that starts at the given byte offset and ends
+// before the current character. Tokens that are read past their last
+// character use this to record their start position.
+func (l *Lexer) emitAt(typ token.TokenType, start int) token.Token {
+	return l.newToken(typ, start, l.pos)
+}
+
+// newToken creates a token for the input between the start and end byte
+// offsets.
+func (l *Lexer) newToken(typ token.TokenType, start, end int) token.Token {
+	from := l.file.Position(start)
+	to := l.file.Position(end)
 	return token.Token{
-		Typ:     typ,
-		Literal: literal,
-		Line:    l.line,
-		Col:     l.col - len(literal),
+		Typ:       typ,
+		Literal:   l.input[start:end],
+		File:      from.File,
+		Line:      from.Line,
+		Col:       from.Col,
+		Offset:    start,
+		EndLine:   to.Line,
+		EndCol:    to.Col,
+		EndOffset: end,
 	}
 }
 
@@ -196,9 +347,13 @@ func (l *Lexer) skipSingleLineComment(start string) {
 
 func (l *Lexer) skipMultiLineComment(start string, end string) {
 	if l.peeksIs(start) {
+		tok := l.emit(token.ILLEGAL)
 		for l.peeksIsNot(end) && l.ch != 0 {
 			l.read()
 		}
+		if l.ch == 0 {
+			l.diags.TokenErrorf("L0002", tok, "Unterminated comment.")
+		}
 		l.read() // [*]
 		l.read() // [/]
 	}
@@ -206,48 +361,303 @@ func (l *Lexer) skipMultiLineComment(start string, end string) {
 
 func (l *Lexer) readID() token.Token {
 	start := l.pos
-	l.read() // [a-zA-Z]
-	l.readWhile(isAlphaNum)
+	l.read() // [\pL]
+	l.readWhile(isLetterOrDigit)
 	literal := l.input[start:l.pos]
 	typ := token.LookupId(literal)
-	return l.emit2(typ, literal)
+	return l.emitAt(typ, start)
+}
+
+// readNumber reads a decimal, hexadecimal (0x), binary (0b) or octal (0o)
+// integer literal or a decimal floating-point literal. Digits may be
+// separated by underscores. Malformed literals are reported and returned
+// as illegal tokens.
+func (l *Lexer) readNumber() token.Token {
+	start := l.pos
+	base := numberBase(l.peeks(2))
+	if base == 10 {
+		l.readWhile(isDecOrSep)
+		if l.ch == '.' && isDec(l.peek()) || l.isExponent() {
+			return l.readFloat(start)
+		}
+	} else {
+		l.read() // [0]
+		l.read() // [xbo]
+		// Read all letters to report invalid digits as part of the literal.
+		l.readWhile(isAlphaNumOrSep)
+	}
+	tok := l.emitAt(token.INT, start)
+	if code, msg := checkNumber(tok.Literal, base); code != "" {
+		tok.Typ = token.ILLEGAL
+		l.diags.TokenErrorf(code, tok, "%s", msg)
+	}
+	return tok
+}
+
+// readFloat reads the fraction and the exponent of a floating-point
+// literal whose integer part starts at the given offset. Either of them
+// may be missing but not both: [1.5], [1e9] and [1.5e-3] are floating-point
+// literals.
+func (l *Lexer) readFloat(start int) token.Token {
+	if l.ch == '.' {
+		l.read() // [.]
+		l.readWhile(isDecOrSep)
+	}
+	if l.isExponent() {
+		l.read() // [eE]
+		if l.ch == '+' || l.ch == '-' {
+			l.read()
+		}
+		l.readWhile(isDecOrSep)
+	}
+	tok := l.emitAt(token.FLOAT, start)
+	if !separatedDigits(tok.Literal) {
+		tok.Typ = token.ILLEGAL
+		l.diags.TokenErrorf("L0005", tok, "Misplaced separator in [%s].", tok.Literal)
+	}
+	return tok
+}
+
+// isExponent returns true iff the current character starts the exponent
+// of a floating-point literal, i.e. [e] or [E] followed by a decimal digit
+// or by a sign and a decimal digit.
+func (l *Lexer) isExponent() bool {
+	if l.ch != 'e' && l.ch != 'E' {
+		return false
+	}
+	s := l.peeks(3)
+	return isDec(l.peek()) || len(s) == 3 && (s[1] == '+' || s[1] == '-') && isDec(rune(s[2]))
+}
+
+// readString reads a string literal. The literal of the token includes the
+// quotes and the escape sequences as written in the source. Use Unquote to
+// get the value of the string.
+func (l *Lexer) readString() token.Token {
+	start := l.pos
+	valid := true
+	l.read() // ["]
+	for l.ch != '"' {
+		// Strings must not span multiple lines.
+		if l.ch == 0 || l.ch == '\n' {
+			tok := l.emitAt(token.ILLEGAL, start)
+			l.diags.TokenErrorf("L0006", tok, "Unterminated string.")
+			return tok
+		}
+		if l.ch == '\\' {
+			valid = l.readEscape() && valid
+		} else {
+			l.read()
+		}
+	}
+	l.read() // ["]
+	tok := l.emitAt(token.STR, start)
+	if !valid {
+		tok.Typ = token.ILLEGAL
+	}
+	return tok
 }
 
-func (l *Lexer) readDec() token.Token {
+// readEscape reads an escape sequence and reports it if it is invalid. The
+// valid escape sequences are [\n], [\t], [\r], [\0], [\\], [\"], [\xHH]
+// with two hexadecimal digits and [\u{H...}] with up to six hexadecimal
+// digits that denote a Unicode code point.
+func (l *Lexer) readEscape() bool {
 	start := l.pos
-	l.readWhile(isDec)
-	return l.emit2(token.INT, l.input[start:l.pos])
+	l.read() // [\]
+	valid := true
+	switch l.ch {
+	case 'n', 't', 'r', '0', '\\', '"':
+		l.read()
+	case 'x':
+		l.read()
+		for i := 0; i < 2 && valid; i++ {
+			valid = isHex(l.ch)
+			if valid {
+				l.read()
+			}
+		}
+	case 'u':
+		l.read()
+		valid = l.readCodePoint()
+	default:
+		valid = false
+		if l.ch != 0 && l.ch != '\n' && l.ch != '"' {
+			l.read()
+		}
+	}
+	if !valid {
+		tok := l.emitAt(token.ILLEGAL, start)
+		l.diags.TokenErrorf("L0007", tok, "Invalid escape sequence [%s].", tok.Literal)
+	}
+	return valid
 }
 
-func (l *Lexer) readHex() token.Token {
+// readCodePoint reads the [{H...}] part of a Unicode escape sequence.
+func (l *Lexer) readCodePoint() bool {
+	if l.ch != '{' {
+		return false
+	}
+	l.read() // [{]
 	start := l.pos
-	l.read() // [0]
-	l.read() // [x]
 	l.readWhile(isHex)
-	return l.emit2(token.INT, l.input[start:l.pos])
+	digits := l.input[start:l.pos]
+	if l.ch != '}' {
+		return false
+	}
+	l.read() // [}]
+	if len(digits) == 0 || len(digits) > 6 {
+		return false
+	}
+	cp, _ := strconv.ParseUint(digits, 16, 32)
+	return utf8.ValidRune(rune(cp))
+}
+
+// Unquote returns the value of a string literal that has been read by the
+// lexer. The literal must not contain invalid escape sequences.
+func Unquote(literal string) string {
+	s := literal[1 : len(literal)-1]
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++ // [\]
+		switch s[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 't':
+			buf.WriteByte('\t')
+		case 'r':
+			buf.WriteByte('\r')
+		case '0':
+			buf.WriteByte(0)
+		case 'x':
+			b, _ := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			buf.WriteByte(byte(b))
+			i += 2
+		case 'u':
+			end := i + strings.IndexByte(s[i:], '}')
+			cp, _ := strconv.ParseUint(s[i+2:end], 16, 32)
+			buf.WriteRune(rune(cp))
+			i = end
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String()
+}
+
+// numberBase returns the base of a number literal that starts with the
+// prefix.
+func numberBase(prefix string) int {
+	switch prefix {
+	case "0x":
+		return 16
+	case "0b":
+		return 2
+	case "0o":
+		return 8
+	}
+	return 10
+}
+
+var baseNames = map[int]string{
+	2:  "binary",
+	8:  "octal",
+	10: "decimal",
+	16: "hexadecimal",
+}
+
+// checkNumber returns the code and message of the first error in the
+// number literal or empty strings if the literal is well-formed.
+func checkNumber(literal string, base int) (string, string) {
+	digits := literal
+	if base != 10 {
+		digits = literal[2:]
+	}
+	if strings.Trim(digits, "_") == "" {
+		return "L0003", fmt.Sprintf("Missing digits in %s literal [%s].", baseNames[base], literal)
+	}
+	for _, c := range digits {
+		if c != '_' && digitValue(c) >= base {
+			return "L0004", fmt.Sprintf("Invalid digit [%c] in %s literal [%s].", c, baseNames[base], literal)
+		}
+	}
+	// Separators may only appear between digits or after the prefix.
+	if strings.HasSuffix(digits, "_") || strings.Contains(digits, "__") {
+		return "L0005", fmt.Sprintf("Misplaced separator in [%s].", literal)
+	}
+	return "", ""
+}
+
+// separatedDigits returns true iff every separator of the literal is
+// between two decimal digits.
+func separatedDigits(literal string) bool {
+	for i := 0; i < len(literal); i++ {
+		if literal[i] == '_' && (i == len(literal)-1 || !isDec(rune(literal[i-1])) || !isDec(rune(literal[i+1]))) {
+			return false
+		}
+	}
+	return true
+}
+
+// digitValue returns the value of a hexadecimal digit or 16 if the
+// character is not a hexadecimal digit.
+func digitValue(c rune) int {
+	switch {
+	case isDec(c):
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c-'a') + 10
+	case 'A' <= c && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return 16
 }
 
 // isWhitespace returns true iff the character is one of [ \t\r\n].
-func isWhitespace(c byte) bool {
+func isWhitespace(c rune) bool {
 	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
 }
 
 // isDec returns true iff the character is a decimal digit.
-func isDec(c byte) bool {
+func isDec(c rune) bool {
 	return '0' <= c && c <= '9'
 }
 
 // isHex returns true iff the character is a hexadecimal digit.
-func isHex(c byte) bool {
-	return isDec(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+func isHex(c rune) bool {
+	return digitValue(c) < 16
+}
+
+// isDecOrSep returns true iff the character is one of [0-9_].
+func isDecOrSep(c rune) bool {
+	return isDec(c) || c == '_'
+}
+
+// isAlphaNumOrSep returns true iff the character is one of [a-zA-Z0-9_].
+func isAlphaNumOrSep(c rune) bool {
+	return isAlphaNum(c) || c == '_'
 }
 
 // isAlpha returns true iff the character is one of [a-zA-Z].
-func isAlpha(c byte) bool {
+func isAlpha(c rune) bool {
 	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
 }
 
 // isAlphaNum returns true iff the character is one of [a-zA-Z0-9].
-func isAlphaNum(c byte) bool {
+func isAlphaNum(c rune) bool {
 	return isAlpha(c) || isDec(c)
 }
+
+// isLetter returns true iff the character is a Unicode letter.
+func isLetter(c rune) bool {
+	return unicode.IsLetter(c)
+}
+
+// isLetterOrDigit returns true iff the character is a Unicode letter or a
+// Unicode decimal digit.
+func isLetterOrDigit(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -1,6 +1,7 @@
 package lexer_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/mhoertnagl/donkey/lexer"
@@ -37,12 +38,12 @@ func TestNext(t *testing.T) {
 
 	tokens := []token.Token{
 		// let five = 5;
-		{Typ: token.LET, Literal: "let", Line: 2, Col: 6},
-		{Typ: token.ID, Literal: "five", Line: 2, Col: 10},
+		{Typ: token.LET, Literal: "let", Line: 2, Col: 5},
+		{Typ: token.ID, Literal: "five", Line: 2, Col: 9},
 		// TODO: Test line and column numbers.
-		{Typ: token.ASSIGN, Literal: "="},
-		{Typ: token.INT, Literal: "5"},
-		{Typ: token.SCOLON, Literal: ";"},
+		{Typ: token.ASSIGN, Literal: "=", Line: 2, Col: 14},
+		{Typ: token.INT, Literal: "5", Line: 2, Col: 16},
+		{Typ: token.SCOLON, Literal: ";", Line: 2, Col: 17},
 		// let ten = 0x10;
 		{Typ: token.LET, Literal: "let"},
 		{Typ: token.ID, Literal: "ten"},
@@ -122,9 +123,14 @@ func TestSingleTokens(t *testing.T) {
 	test(t, "+", token.Token{Typ: token.PLUS, Literal: "+"})
 	test(t, "-", token.Token{Typ: token.MINUS, Literal: "-"})
 	test(t, "*", token.Token{Typ: token.TIMES, Literal: "*"})
+	test(t, "**", token.Token{Typ: token.POW, Literal: "**"})
 	test(t, "/", token.Token{Typ: token.DIV, Literal: "/"})
+	test(t, "%", token.Token{Typ: token.MOD, Literal: "%"})
 
 	test(t, "~", token.Token{Typ: token.INV, Literal: "~"})
+	test(t, "~&", token.Token{Typ: token.NAND, Literal: "~&"})
+	test(t, "~|", token.Token{Typ: token.NOR, Literal: "~|"})
+	test(t, "~^", token.Token{Typ: token.XNOR, Literal: "~^"})
 
 	test(t, "&", token.Token{Typ: token.AND, Literal: "&"})
 	test(t, "&&", token.Token{Typ: token.CONJ, Literal: "&&"})
@@ -152,17 +158,157 @@ func TestSingleTokens(t *testing.T) {
 	test(t, ")", token.Token{Typ: token.RPAR, Literal: ")"})
 	test(t, "{", token.Token{Typ: token.LBRA, Literal: "{"})
 	test(t, "}", token.Token{Typ: token.RBRA, Literal: "}"})
+	test(t, "[", token.Token{Typ: token.LBRK, Literal: "["})
+	test(t, "]", token.Token{Typ: token.RBRK, Literal: "]"})
 	test(t, ",", token.Token{Typ: token.COMMA, Literal: ","})
 	test(t, ";", token.Token{Typ: token.SCOLON, Literal: ";"})
+	test(t, ":", token.Token{Typ: token.COLON, Literal: ":"})
+	test(t, ".", token.Token{Typ: token.DOT, Literal: "."})
+	test(t, "...", token.Token{Typ: token.ELLIPSIS, Literal: "..."})
+	test(t, "=>", token.Token{Typ: token.DARROW, Literal: "=>"})
+	test(t, "->", token.Token{Typ: token.ARROW, Literal: "->"})
+	test(t, "_", token.Token{Typ: token.BLANK, Literal: "_"})
+
+	test(t, "+=", token.Token{Typ: token.PLUSEQ, Literal: "+="})
+	test(t, "-=", token.Token{Typ: token.MINUSEQ, Literal: "-="})
+	test(t, "*=", token.Token{Typ: token.TIMESEQ, Literal: "*="})
+	test(t, "/=", token.Token{Typ: token.DIVEQ, Literal: "/="})
+	test(t, "%=", token.Token{Typ: token.MODEQ, Literal: "%="})
+	test(t, "**=", token.Token{Typ: token.POWEQ, Literal: "**="})
+	test(t, "&=", token.Token{Typ: token.ANDEQ, Literal: "&="})
+	test(t, "|=", token.Token{Typ: token.OREQ, Literal: "|="})
+	test(t, "^=", token.Token{Typ: token.XOREQ, Literal: "^="})
+	test(t, "<<=", token.Token{Typ: token.SLLEQ, Literal: "<<="})
+	test(t, ">>=", token.Token{Typ: token.SRLEQ, Literal: ">>="})
+	test(t, ">>>=", token.Token{Typ: token.SRAEQ, Literal: ">>>="})
+	test(t, "<<>=", token.Token{Typ: token.ROLEQ, Literal: "<<>="})
+	test(t, "<>>=", token.Token{Typ: token.ROREQ, Literal: "<>>="})
+	test(t, "++", token.Token{Typ: token.INC, Literal: "++"})
+	test(t, "--", token.Token{Typ: token.DEC, Literal: "--"})
+
+	test(t, "while", token.Token{Typ: token.WHILE, Literal: "while"})
+	test(t, "for", token.Token{Typ: token.FOR, Literal: "for"})
+	test(t, "break", token.Token{Typ: token.BREAK, Literal: "break"})
+	test(t, "continue", token.Token{Typ: token.CONT, Literal: "continue"})
+	test(t, "struct", token.Token{Typ: token.STRUCT, Literal: "struct"})
+	test(t, "enum", token.Token{Typ: token.ENUM, Literal: "enum"})
+	test(t, "match", token.Token{Typ: token.MATCH, Literal: "match"})
 
 	test(t, "xxx", token.Token{Typ: token.ID, Literal: "xxx"})
 	test(t, "x1", token.Token{Typ: token.ID, Literal: "x1"})
 	test(t, "0123456789", token.Token{Typ: token.INT, Literal: "0123456789"})
 	test(t, "0x0123456789ABCDEF", token.Token{Typ: token.INT, Literal: "0x0123456789ABCDEF"})
 
+	test(t, "0b1010", token.Token{Typ: token.INT, Literal: "0b1010"})
+	test(t, "0o777", token.Token{Typ: token.INT, Literal: "0o777"})
+	test(t, "1_000", token.Token{Typ: token.INT, Literal: "1_000"})
+	test(t, "0xFF_FF", token.Token{Typ: token.INT, Literal: "0xFF_FF"})
+	test(t, "0x_1", token.Token{Typ: token.INT, Literal: "0x_1"})
+	test(t, "0b12", token.Token{Typ: token.ILLEGAL, Literal: "0b12"})
+	test(t, "1.5", token.Token{Typ: token.FLOAT, Literal: "1.5"})
+	test(t, "1.5e-3", token.Token{Typ: token.FLOAT, Literal: "1.5e-3"})
+	test(t, "2E+10", token.Token{Typ: token.FLOAT, Literal: "2E+10"})
+	test(t, "1e9", token.Token{Typ: token.FLOAT, Literal: "1e9"})
+	test(t, "1_000.000_1", token.Token{Typ: token.FLOAT, Literal: "1_000.000_1"})
+	test(t, "1.", token.Token{Typ: token.INT, Literal: "1"})
+	test(t, "1e", token.Token{Typ: token.INT, Literal: "1"})
+
+	test(t, `""`, token.Token{Typ: token.STR, Literal: `""`})
+	test(t, `"a b"`, token.Token{Typ: token.STR, Literal: `"a b"`})
+	test(t, `"a\"b"`, token.Token{Typ: token.STR, Literal: `"a\"b"`})
+	test(t, `"\x41\u{1F600}"`, token.Token{Typ: token.STR, Literal: `"\x41\u{1F600}"`})
+	test(t, `"\q"`, token.Token{Typ: token.ILLEGAL, Literal: `"\q"`})
+
 	test(t, "#", token.Token{Typ: token.ILLEGAL, Literal: "#"})
 }
 
+func TestDiagnostics(t *testing.T) {
+	testDiagnostics(t, "let a = 1;", []string{})
+	testDiagnostics(t, "let # = 1;", []string{"1:5: error[L0001]: Illegal character [#]."})
+	testDiagnostics(t, "a\n  $ b @", []string{
+		"2:3: error[L0001]: Illegal character [$].",
+		"2:7: error[L0001]: Illegal character [@].",
+	})
+	testDiagnostics(t, "a /* b", []string{"1:3: error[L0002]: Unterminated comment."})
+	testDiagnostics(t, `"abc`, []string{"1:1: error[L0006]: Unterminated string."})
+	testDiagnostics(t, "\"abc\n\"", []string{"1:1: error[L0006]: Unterminated string.", "2:1: error[L0006]: Unterminated string."})
+	testDiagnostics(t, `"a\qb\x4"`, []string{
+		"1:3: error[L0007]: Invalid escape sequence [\\q].",
+		"1:6: error[L0007]: Invalid escape sequence [\\x4].",
+	})
+	testDiagnostics(t, `"\u{110000}\u{D800}\u41\u{}"`, []string{
+		"1:2: error[L0007]: Invalid escape sequence [\\u{110000}].",
+		"1:12: error[L0007]: Invalid escape sequence [\\u{D800}].",
+		"1:20: error[L0007]: Invalid escape sequence [\\u].",
+		"1:24: error[L0007]: Invalid escape sequence [\\u{}].",
+	})
+	testDiagnostics(t, "a € b", []string{"1:3: error[L0001]: Illegal character [€]."})
+	testDiagnostics(t, "a \xff\xfe b", []string{
+		"1:3: error[L0008]: Invalid UTF-8 encoding [0xff].",
+		"1:4: error[L0008]: Invalid UTF-8 encoding [0xfe].",
+	})
+	testDiagnostics(t, "// \xff\na", []string{"1:4: error[L0008]: Invalid UTF-8 encoding [0xff]."})
+	testDiagnostics(t, "0x;", []string{"1:1: error[L0003]: Missing digits in hexadecimal literal [0x]."})
+	testDiagnostics(t, "0b_;", []string{"1:1: error[L0003]: Missing digits in binary literal [0b_]."})
+	testDiagnostics(t, "a = 0o78;", []string{"1:5: error[L0004]: Invalid digit [8] in octal literal [0o78]."})
+	testDiagnostics(t, "0xFG;", []string{"1:1: error[L0004]: Invalid digit [G] in hexadecimal literal [0xFG]."})
+	testDiagnostics(t, "1__0;", []string{"1:1: error[L0005]: Misplaced separator in [1__0]."})
+	testDiagnostics(t, "0x1_;", []string{"1:1: error[L0005]: Misplaced separator in [0x1_]."})
+	testDiagnostics(t, "1_.5;", []string{"1:1: error[L0005]: Misplaced separator in [1_.5]."})
+	testDiagnostics(t, "1.5_e3;", []string{"1:1: error[L0005]: Misplaced separator in [1.5_e3]."})
+}
+
+func TestUnicode(t *testing.T) {
+	testBlock(t, "let größe = 1; // ½ comment\nλ2 + 一つ;", []token.Token{
+		{Typ: token.LET, Literal: "let", Line: 1, Col: 1},
+		{Typ: token.ID, Literal: "größe", Line: 1, Col: 5},
+		{Typ: token.ASSIGN, Literal: "=", Line: 1, Col: 11},
+		{Typ: token.INT, Literal: "1", Line: 1, Col: 13},
+		{Typ: token.SCOLON, Literal: ";", Line: 1, Col: 14},
+		{Typ: token.ID, Literal: "λ2", Line: 2, Col: 1},
+		{Typ: token.PLUS, Literal: "+", Line: 2, Col: 4},
+		{Typ: token.ID, Literal: "一つ", Line: 2, Col: 6},
+		{Typ: token.SCOLON, Literal: ";", Line: 2, Col: 8},
+		{Typ: token.EOF, Literal: "", Line: 2, Col: 9},
+	})
+	testBlock(t, `"😀" x`, []token.Token{
+		{Typ: token.STR, Literal: `"😀"`, Line: 1, Col: 1},
+		{Typ: token.ID, Literal: "x", Line: 1, Col: 5},
+	})
+	testBlock(t, "١", []token.Token{
+		{Typ: token.ILLEGAL, Literal: "١", Line: 1, Col: 1},
+	})
+}
+
+func TestUTF16Columns(t *testing.T) {
+	l := lexer.NewLexerForFile(token.NewFile("", `"😀" é x`, token.UTF16))
+	for _, e := range []int{1, 6, 8, 9} {
+		if a := l.Next(); a.Col != e {
+			t.Errorf("Expected [%s] at column [%d] but got [%d].", a.Literal, e, a.Col)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	l := lexer.NewLexer("let größe =\n  \"a\\nb\" >>> 0x1F;")
+	for _, e := range []string{
+		"let 0-3 1:1-1:4",
+		"größe 4-11 1:5-1:10",
+		"= 12-13 1:11-1:12",
+		"\"a\\nb\" 16-22 2:3-2:9",
+		">>> 23-26 2:10-2:13",
+		"0x1F 27-31 2:14-2:18",
+		"; 31-32 2:18-2:19",
+		" 32-32 2:19-2:19",
+	} {
+		a := l.Next()
+		s := fmt.Sprintf("%s %d-%d %d:%d-%d:%d", a.Literal, a.Offset, a.EndOffset, a.Line, a.Col, a.EndLine, a.EndCol)
+		if s != e {
+			t.Errorf("Expected [%s] but got [%s].", e, s)
+		}
+	}
+}
+
 const msgErrUnexpectedType = "%d: Unexpected token type [%s]. Expecting [%s]."
 const msgErrUnexpectedLiteral = "%d: Unexpected token literal [%s]. Expecting [%s]."
 const msgErrLineMismatch = "%d: Line mismatch [%d]. Expecting [%d]."
@@ -188,6 +334,22 @@ func testBlock(t *testing.T, input string, tokens []token.Token) {
 	}
 }
 
+func testDiagnostics(t *testing.T, input string, expected []string) {
+	t.Helper()
+	l := lexer.NewLexer(input)
+	for l.Next().Typ != token.EOF {
+	}
+	actual := l.Diagnostics().Items()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] diagnostics but got [%d]: %v.", len(expected), len(actual), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("%d: Expected [%s] but got [%s].", i, e, a)
+		}
+	}
+}
+
 func test(t *testing.T, input string, token token.Token) {
 	t.Helper()
 	l := lexer.NewLexer(input)
@@ -0,0 +1,154 @@
+// Package loader loads the modules of a program. The main module consists
+// of the source files given to the compiler. Every other module is a source
+// file of the project. Its import path is the path of the file relative to
+// the root directory of the project without the extension:
+//
+//	import "math/bits";  =>  <root>/math/bits.dk
+package loader
+
+import (
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/lexer"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/token"
+	"github.com/mhoertnagl/donkey/utils/fs"
+)
+
+// Ext is the extension of source files.
+const Ext = ".dk"
+
+// Loader parses the source files of the modules of a program.
+type Loader struct {
+	root  string
+	fset  *token.FileSet
+	diags *diag.List
+	// Source code of the parsed files by file name.
+	sources map[string]string
+	// Loaded modules by import path, the import paths of the modules that
+	// are being loaded and the loaded modules in the order of their
+	// completion.
+	modules map[string]*parser.Program
+	loading []string
+	order   []*parser.Program
+}
+
+func NewLoader(root string, fset *token.FileSet) *Loader {
+	return &Loader{
+		root:    root,
+		fset:    fset,
+		diags:   diag.NewList(),
+		sources: make(map[string]string),
+		modules: make(map[string]*parser.Program),
+	}
+}
+
+func (l *Loader) HasErrors() bool {
+	return l.diags.HasErrors()
+}
+
+func (l *Loader) Errors() []*diag.Diagnostic {
+	return l.diags.Items()
+}
+
+// Sources returns the source code of the parsed files by file name.
+func (l *Loader) Sources() map[string]string {
+	return l.sources
+}
+
+// Load parses the files of the main module and the modules they import.
+// It returns the modules such that every module follows the modules it
+// imports. The main module is the last one. An error is returned if a file
+// of the main module cannot be read.
+func (l *Loader) Load(files []string) ([]*parser.Program, error) {
+	main := parser.NewProgram()
+	for _, file := range files {
+		input, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		prog := l.parse(file, string(input))
+		main.Statements = append(main.Statements, prog.Statements...)
+	}
+	l.imports(main)
+	return append(l.order, main), nil
+}
+
+func (l *Loader) parse(file, input string) *parser.Program {
+	l.sources[file] = input
+	p := parser.NewParser(lexer.NewLexerForFile(l.fset.AddFile(file, input)))
+	prog := p.Parse()
+	for _, d := range p.Errors() {
+		l.diags.Add(d)
+	}
+	return prog
+}
+
+// imports loads the modules the module imports.
+func (l *Loader) imports(prog *parser.Program) {
+	for _, s := range prog.Statements {
+		if n, ok := s.(*parser.ImportStatement); ok {
+			l.load(n)
+		}
+	}
+}
+
+// load loads the imported module and the modules it imports unless it has
+// been loaded already.
+func (l *Loader) load(n *parser.ImportStatement) {
+	path := n.Path.Value
+	if _, ok := l.modules[path]; ok {
+		return
+	}
+	for i, p := range l.loading {
+		if p == path {
+			cycle := append(append([]string{}, l.loading[i:]...), path)
+			l.diags.NodeErrorf("M0002", n.Path, "Import cycle [%s].", strings.Join(cycle, " -> "))
+			return
+		}
+	}
+	if !isPath(path) {
+		l.diags.NodeErrorf("M0003", n.Path, "Invalid import path [%s].", path)
+		return
+	}
+	file, ok := fs.FindFile(l.root, path, Ext)
+	if !ok {
+		l.diags.NodeErrorf("M0001", n.Path, "Cannot find module [%s].", path)
+		return
+	}
+	input, err := os.ReadFile(file)
+	if err != nil {
+		l.diags.NodeErrorf("M0001", n.Path, "Cannot read module [%s]: %s.", path, err)
+		return
+	}
+	prog := l.parse(file, string(input))
+	prog.Path = path
+	l.loading = append(l.loading, path)
+	l.imports(prog)
+	l.loading = l.loading[:len(l.loading)-1]
+	l.modules[path] = prog
+	l.order = append(l.order, prog)
+}
+
+// isPath returns true iff the elements of the slash-separated import path
+// are identifiers. The last element is the name of the module.
+func isPath(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if !isIdentifier(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifier(s string) bool {
+	for i, c := range s {
+		if !unicode.IsLetter(c) && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return s != "" && token.LookupId(s) == token.ID
+}
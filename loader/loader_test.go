@@ -0,0 +1,103 @@
+package loader_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mhoertnagl/donkey/loader"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+func TestLoad(t *testing.T) {
+	testOrder(t, map[string]string{
+		"main.dk":      `import "math/bits"; import "util";`,
+		"math/bits.dk": `import "util"; pub fn count(x) { return util.id(x); }`,
+		"util.dk":      `pub fn id(x) { return x; }`,
+	}, "util", "math/bits", "")
+	testOrder(t, map[string]string{
+		"main.dk": `import "a"; import "b";`,
+		"a.dk":    `import "c";`,
+		"b.dk":    `import "c"; import "a";`,
+		"c.dk":    ``,
+	}, "c", "a", "b", "")
+	testOrder(t, map[string]string{"main.dk": `fn main() { return 0; }`}, "")
+}
+
+func TestLoadErrors(t *testing.T) {
+	testErrors(t, map[string]string{
+		"main.dk": `import "a";`,
+		"a.dk":    `import "b";`,
+		"b.dk":    `import "c";`,
+		"c.dk":    `import "a";`,
+	}, "c.dk:1:8: error[M0002]: Import cycle [a -> b -> c -> a].")
+	testErrors(t, map[string]string{
+		"main.dk": `import "a";`,
+		"a.dk":    `import "a";`,
+	}, "a.dk:1:8: error[M0002]: Import cycle [a -> a].")
+	testErrors(t, map[string]string{"main.dk": `import "math/bits";`}, "main.dk:1:8: error[M0001]: Cannot find module [math/bits].")
+	testErrors(t, map[string]string{"main.dk": `import "../a";`}, "main.dk:1:8: error[M0003]: Invalid import path [../a].")
+	testErrors(t, map[string]string{"main.dk": `import "a/fn";`}, "main.dk:1:8: error[M0003]: Invalid import path [a/fn].")
+	testErrors(t, map[string]string{"main.dk": `import "";`}, "main.dk:1:8: error[M0003]: Invalid import path [].")
+	testErrors(t, map[string]string{
+		"main.dk": `import "a";`,
+		"a.dk":    `let = 1;`,
+	}, "a.dk:1:5: error[P0001]: Expecting [ID] but got [=].")
+}
+
+// load writes the files to a new project directory and loads the program
+// with the main module main.dk. It returns the loader, the modules and the
+// project directory.
+func load(t *testing.T, files map[string]string) (*loader.Loader, []*parser.Program, string) {
+	t.Helper()
+	root := t.TempDir()
+	for name, src := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := loader.NewLoader(root, token.NewFileSet())
+	mods, err := l.Load([]string{filepath.Join(root, "main.dk")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l, mods, root
+}
+
+// testOrder checks the import paths of the loaded modules.
+func testOrder(t *testing.T, files map[string]string, expected ...string) {
+	t.Helper()
+	l, mods, _ := load(t, files)
+	if l.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", l.Errors())
+	}
+	paths := []string{}
+	for _, mod := range mods {
+		paths = append(paths, mod.Path)
+	}
+	if actual := strings.Join(paths, ", "); actual != strings.Join(expected, ", ") {
+		t.Errorf("Expected [%s] but got [%s].", strings.Join(expected, ", "), actual)
+	}
+}
+
+// testErrors checks the errors. File names are relative to the project
+// directory.
+func testErrors(t *testing.T, files map[string]string, expected ...string) {
+	t.Helper()
+	l, _, root := load(t, files)
+	actual := l.Errors()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := strings.TrimPrefix(actual[i].String(), root+string(filepath.Separator)); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
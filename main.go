@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 
 	"github.com/mhoertnagl/donkey/console"
@@ -22,7 +23,10 @@ func main() {
 
 	if len(flag.Args()) == 0 {
 		repl.Start(os.Stdin, os.Stdout, cargs)
+	} else if flag.Arg(0) == "build" {
+		os.Exit(build(flag.Args()[1:]))
 	} else {
-
+		fmt.Fprintf(os.Stderr, "donkey: unknown command [%s]\n", flag.Arg(0))
+		os.Exit(2)
 	}
 }
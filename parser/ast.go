@@ -3,14 +3,21 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"strconv"
 	"strings"
 
+	"github.com/mhoertnagl/donkey/lexer"
 	"github.com/mhoertnagl/donkey/token"
 )
 
 type Node interface {
 	Literal() string
 	String() string
+	// Pos returns the position of the first character of the node.
+	Pos() token.Position
+	// End returns the position of the character following the node.
+	End() token.Position
 }
 
 type Statement interface {
@@ -21,9 +28,48 @@ type Statement interface {
 type Expression interface {
 	Node
 	expression()
+	parens() *Parens
 }
 
+// Parens holds the outermost pair of parentheses around an expression. The
+// range of a parenthesized expression includes its parentheses.
+type Parens struct {
+	Open  token.Token
+	Close token.Token
+}
+
+func (p *Parens) parens() *Parens { return p }
+
+// start returns the position of the opening parenthesis or pos if the
+// expression is not parenthesized.
+func (p *Parens) start(pos token.Position) token.Position {
+	if p.Open.Typ == token.LPAR {
+		return p.Open.Start()
+	}
+	return pos
+}
+
+// end returns the position following the closing parenthesis or pos if the
+// expression is not parenthesized.
+func (p *Parens) end(pos token.Position) token.Position {
+	if p.Close.Typ == token.RPAR {
+		return p.Close.End()
+	}
+	return pos
+}
+
+// TypeExpr is the type of a type annotation. It is either the name of a
+// type, a type of an imported module such as geo.Point, an array type or a
+// function type.
+type TypeExpr interface {
+	Node
+	typeExpr()
+}
+
+// Program is a module of a program. Path is the import path of the module.
+// It is empty for the main module.
 type Program struct {
+	Path       string
 	Statements []Statement
 }
 
@@ -38,6 +84,20 @@ func (p *Program) Literal() string {
 	return ""
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
+func (p *Program) End() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].End()
+	}
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var buf bytes.Buffer
 	for _, s := range p.Statements {
@@ -47,9 +107,33 @@ func (p *Program) String() string {
 	return buf.String()
 }
 
+// ImportStatement imports the module with the path. The public
+// definitions of the module are members of the module name, which is the
+// last element of the path.
+type ImportStatement struct {
+	Token token.Token
+	Path  *String
+}
+
+func NewImportStmt(token token.Token) *ImportStatement {
+	return &ImportStatement{Token: token}
+}
+
+// Name returns the name of the imported module.
+func (s *ImportStatement) Name() string {
+	return s.Path.Value[strings.LastIndex(s.Path.Value, "/")+1:]
+}
+
+func (s *ImportStatement) statement()          {}
+func (s *ImportStatement) Literal() string     { return s.Token.Literal }
+func (s *ImportStatement) Pos() token.Position { return s.Token.Start() }
+func (s *ImportStatement) End() token.Position { return s.Path.End() }
+func (s *ImportStatement) String() string      { return "import " + s.Path.String() + ";" }
+
 type LetStatement struct {
 	Token token.Token
 	Name  *Identifier
+	Type  TypeExpr // Optional type annotation.
 	Value Expression
 }
 
@@ -57,12 +141,14 @@ func NewLetStmt(token token.Token) *LetStatement {
 	return &LetStatement{Token: token}
 }
 
-func (s *LetStatement) statement()      {}
-func (s *LetStatement) Literal() string { return s.Token.Literal }
+func (s *LetStatement) statement()          {}
+func (s *LetStatement) Literal() string     { return s.Token.Literal }
+func (s *LetStatement) Pos() token.Position { return s.Token.Start() }
+func (s *LetStatement) End() token.Position { return s.Value.End() }
 func (s *LetStatement) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("let ")
-	buf.WriteString(s.Name.String())
+	buf.WriteString(typed(s.Name, s.Type))
 	buf.WriteString(" = ")
 	// TODO: Remove when expression parsing is in place.
 	if s.Value != nil {
@@ -72,37 +158,259 @@ func (s *LetStatement) String() string {
 	return buf.String()
 }
 
+// AssignStatement assigns the value to the target. The operator of a
+// compound assignment such as a += 1 is the binary operator it applies to
+// the target and the value. It is empty for simple assignments. The value
+// of an increment a++ or a decrement a-- is the literal 1.
+type AssignStatement struct {
+	Token    token.Token
+	Operator token.TokenType
+	Target   Expression
+	Value    Expression
+}
+
+func NewAssignStmt(tok token.Token) *AssignStatement {
+	op, _ := token.CompoundOp(tok.Typ)
+	return &AssignStatement{Token: tok, Operator: op}
+}
+
+func (s *AssignStatement) statement()          {}
+func (s *AssignStatement) Literal() string     { return s.Token.Literal }
+func (s *AssignStatement) Pos() token.Position { return s.Target.Pos() }
+func (s *AssignStatement) End() token.Position { return s.Value.End() }
+func (s *AssignStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(s.Target.String())
+	if s.Token.Typ == token.INC || s.Token.Typ == token.DEC {
+		buf.WriteString(s.Token.Literal + ";")
+		return buf.String()
+	}
+	buf.WriteString(" " + s.Token.Literal + " ")
+	buf.WriteString(s.Value.String())
+	buf.WriteString(";")
+	return buf.String()
+}
+
+// FunDefStatement is a named function definition. ParamTypes holds the
+// optional type annotations of the parameters. Parameters without an
+// annotation have a nil type. Public functions are visible in the modules
+// that import the module of the function.
+//
+// An extern function is declared without a body and defined outside of the
+// program. Variadic extern functions accept additional arguments after
+// their parameters. Exported functions keep their name as the symbol.
 type FunDefStatement struct {
-	Token  token.Token
-	Name   *Identifier
-	Params []*Identifier
-	Body   *BlockStatement
+	Token      token.Token
+	Prefix     token.Token // First of the optional pub, extern and export.
+	Pub        bool
+	Extern     bool
+	Export     bool
+	Name       *Identifier
+	Params     []*Identifier
+	ParamTypes []TypeExpr
+	Variadic   bool
+	Rpar       token.Token
+	Result     TypeExpr        // Optional result type annotation.
+	Body       *BlockStatement // Nil for extern functions.
+}
+
+// prefixPos returns the position of the prefix of a definition or of the
+// token of the definition if it has no prefix.
+func prefixPos(prefix, tok token.Token) token.Position {
+	if prefix.Typ != "" {
+		return prefix.Start()
+	}
+	return tok.Start()
 }
 
 func NewFunDefStmt(token token.Token) *FunDefStatement {
 	return &FunDefStatement{Token: token}
 }
 
-func (e *FunDefStatement) statement()      {}
-func (e *FunDefStatement) Literal() string { return e.Token.Literal }
-func (e *FunDefStatement) String() string {
-	params := []string{}
-	for _, id := range e.Params {
-		params = append(params, id.String())
+func (e *FunDefStatement) statement()          {}
+func (e *FunDefStatement) Literal() string     { return e.Token.Literal }
+func (e *FunDefStatement) Pos() token.Position { return prefixPos(e.Prefix, e.Token) }
+func (e *FunDefStatement) End() token.Position {
+	switch {
+	case e.Body != nil:
+		return e.Body.End()
+	case e.Result != nil:
+		return e.Result.End()
 	}
-
+	return e.Rpar.End()
+}
+func (e *FunDefStatement) String() string {
 	var buf bytes.Buffer
+	buf.WriteString(pub(e.Pub))
+	buf.WriteString(linkage(e))
 	buf.WriteString("fn")
 	buf.WriteString(" ")
 	buf.WriteString(e.Name.String())
 	buf.WriteString("(")
+	params := typedList(e.Params, e.ParamTypes)
+	if e.Variadic {
+		params = append(params, "...")
+	}
 	buf.WriteString(strings.Join(params, ", "))
 	buf.WriteString(")")
+	if e.Result != nil {
+		buf.WriteString(" -> ")
+		buf.WriteString(e.Result.String())
+	}
+	if e.Body == nil {
+		buf.WriteString(";")
+		return buf.String()
+	}
 	buf.WriteString(" ")
 	buf.WriteString(e.Body.String())
 	return buf.String()
 }
 
+// StructDefStatement is a struct definition. FieldTypes holds the optional
+// type annotations of the fields.
+type StructDefStatement struct {
+	Token      token.Token
+	Prefix     token.Token // Optional pub.
+	Pub        bool
+	Name       *Identifier
+	Fields     []*Identifier
+	FieldTypes []TypeExpr
+	Rbrace     token.Token
+}
+
+func NewStructDefStmt(token token.Token) *StructDefStatement {
+	return &StructDefStatement{Token: token, Fields: []*Identifier{}, FieldTypes: []TypeExpr{}}
+}
+
+func (s *StructDefStatement) statement()          {}
+func (s *StructDefStatement) Literal() string     { return s.Token.Literal }
+func (s *StructDefStatement) Pos() token.Position { return prefixPos(s.Prefix, s.Token) }
+func (s *StructDefStatement) End() token.Position {
+	if s.Rbrace.Typ == token.RBRA {
+		return s.Rbrace.End()
+	}
+	return s.Name.End()
+}
+func (s *StructDefStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(pub(s.Pub))
+	buf.WriteString("struct")
+	buf.WriteString(" ")
+	buf.WriteString(s.Name.String())
+	buf.WriteString(" { ")
+	buf.WriteString(strings.Join(typedList(s.Fields, s.FieldTypes), ", "))
+	buf.WriteString(" }")
+	return buf.String()
+}
+
+type EnumDefStatement struct {
+	Token    token.Token
+	Prefix   token.Token // Optional pub.
+	Pub      bool
+	Name     *Identifier
+	Variants []*Variant
+	Rbrace   token.Token
+}
+
+func NewEnumDefStmt(token token.Token) *EnumDefStatement {
+	return &EnumDefStatement{Token: token, Variants: []*Variant{}}
+}
+
+func (s *EnumDefStatement) statement()          {}
+func (s *EnumDefStatement) Literal() string     { return s.Token.Literal }
+func (s *EnumDefStatement) Pos() token.Position { return prefixPos(s.Prefix, s.Token) }
+func (s *EnumDefStatement) End() token.Position {
+	if s.Rbrace.Typ == token.RBRA {
+		return s.Rbrace.End()
+	}
+	return s.Name.End()
+}
+func (s *EnumDefStatement) String() string {
+	variants := []string{}
+	for _, v := range s.Variants {
+		variants = append(variants, v.String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(pub(s.Pub))
+	buf.WriteString("enum")
+	buf.WriteString(" ")
+	buf.WriteString(s.Name.String())
+	buf.WriteString(" { ")
+	buf.WriteString(strings.Join(variants, ", "))
+	buf.WriteString(" }")
+	return buf.String()
+}
+
+// Variant is an alternative of an enum with an optional list of fields.
+// FieldTypes holds the optional type annotations of the fields.
+type Variant struct {
+	Name       *Identifier
+	Fields     []*Identifier
+	FieldTypes []TypeExpr
+}
+
+func (v *Variant) String() string {
+	if len(v.Fields) == 0 {
+		return v.Name.String()
+	}
+	return v.Name.String() + "(" + strings.Join(typedList(v.Fields, v.FieldTypes), ", ") + ")"
+}
+
+// pub returns the pub keyword of public definitions.
+func pub(isPub bool) string {
+	if isPub {
+		return "pub "
+	}
+	return ""
+}
+
+// linkage returns the extern or export keyword of the function.
+func linkage(n *FunDefStatement) string {
+	switch {
+	case n.Extern:
+		return "extern "
+	case n.Export:
+		return "export "
+	}
+	return ""
+}
+
+// fieldList returns the names in parentheses or an empty string if there
+// are no names.
+func fieldList(ids []*Identifier) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, id := range ids {
+		names = append(names, id.String())
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// typed returns the name followed by its type annotation if it has one.
+func typed(id *Identifier, typ TypeExpr) string {
+	if typ == nil {
+		return id.String()
+	}
+	return id.String() + ": " + typ.String()
+}
+
+// typedList returns the names with their type annotations. The types may
+// be nil or shorter than the names if no name is annotated.
+func typedList(ids []*Identifier, types []TypeExpr) []string {
+	res := []string{}
+	for i, id := range ids {
+		var typ TypeExpr
+		if i < len(types) {
+			typ = types[i]
+		}
+		res = append(res, typed(id, typ))
+	}
+	return res
+}
+
 type ReturnStatement struct {
 	Token token.Token
 	Value Expression
@@ -112,8 +420,10 @@ func NewReturnStmt(token token.Token) *ReturnStatement {
 	return &ReturnStatement{Token: token}
 }
 
-func (s *ReturnStatement) statement()      {}
-func (s *ReturnStatement) Literal() string { return s.Token.Literal }
+func (s *ReturnStatement) statement()          {}
+func (s *ReturnStatement) Literal() string     { return s.Token.Literal }
+func (s *ReturnStatement) Pos() token.Position { return s.Token.Start() }
+func (s *ReturnStatement) End() token.Position { return s.Value.End() }
 func (s *ReturnStatement) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("return ")
@@ -136,8 +446,15 @@ func NewIfStmt(token token.Token) *IfStatement {
 	return &IfStatement{Token: token}
 }
 
-func (s *IfStatement) statement()      {}
-func (s *IfStatement) Literal() string { return s.Token.Literal }
+func (s *IfStatement) statement()          {}
+func (s *IfStatement) Literal() string     { return s.Token.Literal }
+func (s *IfStatement) Pos() token.Position { return s.Token.Start() }
+func (s *IfStatement) End() token.Position {
+	if s.Alternative != nil {
+		return s.Alternative.End()
+	}
+	return s.Consequence.End()
+}
 func (s *IfStatement) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("if")
@@ -154,17 +471,120 @@ func (s *IfStatement) String() string {
 	return buf.String()
 }
 
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func NewWhileStmt(token token.Token) *WhileStatement {
+	return &WhileStatement{Token: token}
+}
+
+func (s *WhileStatement) statement()          {}
+func (s *WhileStatement) Literal() string     { return s.Token.Literal }
+func (s *WhileStatement) Pos() token.Position { return s.Token.Start() }
+func (s *WhileStatement) End() token.Position { return s.Body.End() }
+func (s *WhileStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("while")
+	buf.WriteString(" ")
+	buf.WriteString(s.Condition.String())
+	buf.WriteString(" ")
+	buf.WriteString(s.Body.String())
+	return buf.String()
+}
+
+// ForStatement is a C-style for loop. Init, Condition and Post are
+// optional. A missing condition loops forever.
+type ForStatement struct {
+	Token     token.Token
+	Init      Statement
+	Condition Expression
+	Post      Statement
+	Body      *BlockStatement
+}
+
+func NewForStmt(token token.Token) *ForStatement {
+	return &ForStatement{Token: token}
+}
+
+func (s *ForStatement) statement()          {}
+func (s *ForStatement) Literal() string     { return s.Token.Literal }
+func (s *ForStatement) Pos() token.Position { return s.Token.Start() }
+func (s *ForStatement) End() token.Position { return s.Body.End() }
+func (s *ForStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("for")
+	buf.WriteString(" ")
+	// Init and Post are statements and print their own semicolon.
+	if s.Init != nil {
+		buf.WriteString(s.Init.String())
+	} else {
+		buf.WriteString(";")
+	}
+	buf.WriteString(" ")
+	if s.Condition != nil {
+		buf.WriteString(s.Condition.String())
+	}
+	buf.WriteString(";")
+	if s.Post != nil {
+		buf.WriteString(" ")
+		post := s.Post.String()
+		buf.WriteString(post[:len(post)-1])
+	}
+	buf.WriteString(" ")
+	buf.WriteString(s.Body.String())
+	return buf.String()
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func NewBreakStmt(token token.Token) *BreakStatement {
+	return &BreakStatement{Token: token}
+}
+
+func (s *BreakStatement) statement()          {}
+func (s *BreakStatement) Literal() string     { return s.Token.Literal }
+func (s *BreakStatement) Pos() token.Position { return s.Token.Start() }
+func (s *BreakStatement) End() token.Position { return s.Token.End() }
+func (s *BreakStatement) String() string      { return "break;" }
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func NewContinueStmt(token token.Token) *ContinueStatement {
+	return &ContinueStatement{Token: token}
+}
+
+func (s *ContinueStatement) statement()          {}
+func (s *ContinueStatement) Literal() string     { return s.Token.Literal }
+func (s *ContinueStatement) Pos() token.Position { return s.Token.Start() }
+func (s *ContinueStatement) End() token.Position { return s.Token.End() }
+func (s *ContinueStatement) String() string      { return "continue;" }
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	Rbrace     token.Token
 }
 
 func NewBlockStmt(token token.Token) *BlockStatement {
 	return &BlockStatement{Token: token, Statements: []Statement{}}
 }
 
-func (s *BlockStatement) statement()      {}
-func (s *BlockStatement) Literal() string { return s.Token.Literal }
+func (s *BlockStatement) statement()          {}
+func (s *BlockStatement) Literal() string     { return s.Token.Literal }
+func (s *BlockStatement) Pos() token.Position { return s.Token.Start() }
+func (s *BlockStatement) End() token.Position {
+	if s.Rbrace.Typ == token.RBRA {
+		return s.Rbrace.End()
+	}
+	return s.Token.End()
+}
 func (s *BlockStatement) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("{")
@@ -186,8 +606,10 @@ func NewExprStmt(token token.Token) *ExpressionStatement {
 	return &ExpressionStatement{Token: token}
 }
 
-func (s *ExpressionStatement) statement()      {}
-func (s *ExpressionStatement) Literal() string { return s.Token.Literal }
+func (s *ExpressionStatement) statement()          {}
+func (s *ExpressionStatement) Literal() string     { return s.Token.Literal }
+func (s *ExpressionStatement) Pos() token.Position { return s.Value.Pos() }
+func (s *ExpressionStatement) End() token.Position { return s.Value.End() }
 func (s *ExpressionStatement) String() string {
 	var buf bytes.Buffer
 	// TODO: Remove when expression parsing is in place.
@@ -198,57 +620,205 @@ func (s *ExpressionStatement) String() string {
 	return buf.String()
 }
 
+// BadStatement is a placeholder for a statement that contains syntax
+// errors.
+type BadStatement struct {
+	Token token.Token
+	Last  token.Token // The last token that has been skipped.
+}
+
+func NewBadStmt(token token.Token) *BadStatement {
+	return &BadStatement{Token: token}
+}
+
+func (s *BadStatement) statement()          {}
+func (s *BadStatement) Literal() string     { return s.Token.Literal }
+func (s *BadStatement) Pos() token.Position { return s.Token.Start() }
+func (s *BadStatement) End() token.Position {
+	if s.Last.EndOffset > s.Token.EndOffset {
+		return s.Last.End()
+	}
+	return s.Token.End()
+}
+func (s *BadStatement) String() string { return "<bad statement>;" }
+
+// BadExpression is a placeholder for an expression that contains syntax
+// errors.
+type BadExpression struct {
+	Token token.Token
+	Parens
+}
+
+func NewBadExpr(token token.Token) *BadExpression {
+	return &BadExpression{Token: token}
+}
+
+func (e *BadExpression) expression()         {}
+func (e *BadExpression) Literal() string     { return e.Token.Literal }
+func (e *BadExpression) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *BadExpression) End() token.Position { return e.end(e.Token.End()) }
+func (e *BadExpression) String() string      { return "<bad expression>" }
+
 type Identifier struct {
 	Token token.Token
 	Value string
+	Parens
 }
 
 func NewIdentifier(token token.Token) *Identifier {
 	return &Identifier{Token: token, Value: token.Literal}
 }
 
-func (e *Identifier) expression()     {}
-func (e *Identifier) Literal() string { return e.Token.Literal }
-func (e *Identifier) String() string  { return e.Value }
+func (e *Identifier) expression()         {}
+func (e *Identifier) Literal() string     { return e.Token.Literal }
+func (e *Identifier) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *Identifier) End() token.Position { return e.end(e.Token.End()) }
+func (e *Identifier) String() string      { return e.Value }
+
+// Identifiers are also the names of types in type annotations.
+func (e *Identifier) typeExpr() {}
+
+// ArrayType is the type [Elem] of arrays.
+type ArrayType struct {
+	Token  token.Token
+	Elem   TypeExpr
+	Rbrack token.Token
+}
+
+func (t *ArrayType) typeExpr()           {}
+func (t *ArrayType) Literal() string     { return t.Token.Literal }
+func (t *ArrayType) Pos() token.Position { return t.Token.Start() }
+func (t *ArrayType) End() token.Position { return t.Rbrack.End() }
+func (t *ArrayType) String() string      { return "[" + t.Elem.String() + "]" }
+
+// FuncType is the type fn(Params) -> Result of functions.
+type FuncType struct {
+	Token  token.Token
+	Params []TypeExpr
+	Result TypeExpr
+	Rpar   token.Token
+}
+
+func (t *FuncType) typeExpr()           {}
+func (t *FuncType) Literal() string     { return t.Token.Literal }
+func (t *FuncType) Pos() token.Position { return t.Token.Start() }
+func (t *FuncType) End() token.Position {
+	if t.Result != nil {
+		return t.Result.End()
+	}
+	return t.Rpar.End()
+}
+func (t *FuncType) String() string {
+	params := []string{}
+	for _, p := range t.Params {
+		params = append(params, p.String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("fn(")
+	buf.WriteString(strings.Join(params, ", "))
+	buf.WriteString(")")
+	if t.Result != nil {
+		buf.WriteString(" -> ")
+		buf.WriteString(t.Result.String())
+	}
+	return buf.String()
+}
 
+// Integer is an integer literal. Its value is exact. Whether it fits into
+// the range of its type is up to the type checker.
 type Integer struct {
 	Token token.Token
-	Value int64
+	Value *big.Int
+	Parens
 }
 
 func NewIntLiteral(token token.Token) *Integer {
 	return &Integer{Token: token}
 }
 
-func (e *Integer) expression()     {}
-func (e *Integer) Literal() string { return e.Token.Literal }
-func (e *Integer) String() string  { return fmt.Sprintf("%d", e.Value) }
+func (e *Integer) expression()         {}
+func (e *Integer) Literal() string     { return e.Token.Literal }
+func (e *Integer) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *Integer) End() token.Position { return e.end(e.Token.End()) }
+func (e *Integer) String() string      { return e.Value.String() }
+
+// Float is a floating-point literal. Its value is the literal rounded to
+// the nearest double-precision number.
+type Float struct {
+	Token token.Token
+	Value float64
+	Parens
+}
+
+func NewFloatLiteral(token token.Token) *Float {
+	return &Float{Token: token}
+}
+
+func (e *Float) expression()         {}
+func (e *Float) Literal() string     { return e.Token.Literal }
+func (e *Float) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *Float) End() token.Position { return e.end(e.Token.End()) }
+
+// String returns the shortest representation of the value that is still
+// a floating-point literal.
+func (e *Float) String() string {
+	s := strconv.FormatFloat(e.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
 
 type Boolean struct {
 	Token token.Token
 	Value bool
+	Parens
 }
 
 func NewBoolLiteral(_token token.Token) *Boolean {
 	return &Boolean{Token: _token, Value: _token.Typ == token.TRUE}
 }
 
-func (e *Boolean) expression()     {}
-func (e *Boolean) Literal() string { return e.Token.Literal }
-func (e *Boolean) String() string  { return fmt.Sprintf("%t", e.Value) }
+func (e *Boolean) expression()         {}
+func (e *Boolean) Literal() string     { return e.Token.Literal }
+func (e *Boolean) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *Boolean) End() token.Position { return e.end(e.Token.End()) }
+func (e *Boolean) String() string      { return fmt.Sprintf("%t", e.Value) }
+
+type String struct {
+	Token token.Token
+	Value string
+	Parens
+}
+
+// NewStringLiteral creates a string literal with the value of the token.
+// The token must not contain invalid escape sequences.
+func NewStringLiteral(token token.Token) *String {
+	return &String{Token: token, Value: lexer.Unquote(token.Literal)}
+}
+
+func (e *String) expression()         {}
+func (e *String) Literal() string     { return e.Token.Literal }
+func (e *String) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *String) End() token.Position { return e.end(e.Token.End()) }
+func (e *String) String() string      { return e.Token.Literal }
 
 type PrefixExpression struct {
 	Token    token.Token
 	Operator token.TokenType
 	Value    Expression
+	Parens
 }
 
 func NewPrefixExpr(token token.Token) *PrefixExpression {
 	return &PrefixExpression{Token: token, Operator: token.Typ}
 }
 
-func (e *PrefixExpression) expression()     {}
-func (e *PrefixExpression) Literal() string { return e.Token.Literal }
+func (e *PrefixExpression) expression()         {}
+func (e *PrefixExpression) Literal() string     { return e.Token.Literal }
+func (e *PrefixExpression) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *PrefixExpression) End() token.Position { return e.end(e.Value.End()) }
 func (e *PrefixExpression) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("(")
@@ -263,14 +833,17 @@ type BinaryExpression struct {
 	Left     Expression
 	Operator token.TokenType
 	Right    Expression
+	Parens
 }
 
 func NewBinaryExpr(token token.Token) *BinaryExpression {
 	return &BinaryExpression{Token: token, Operator: token.Typ}
 }
 
-func (e *BinaryExpression) expression()     {}
-func (e *BinaryExpression) Literal() string { return e.Token.Literal }
+func (e *BinaryExpression) expression()         {}
+func (e *BinaryExpression) Literal() string     { return e.Token.Literal }
+func (e *BinaryExpression) Pos() token.Position { return e.start(e.Left.Pos()) }
+func (e *BinaryExpression) End() token.Position { return e.end(e.Right.End()) }
 func (e *BinaryExpression) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("(")
@@ -283,43 +856,273 @@ func (e *BinaryExpression) String() string {
 	return buf.String()
 }
 
-// type FunctionLiteral struct {
-// 	Token  token.Token
-// 	Params []*Identifier
-// 	Body   *BlockStatement
-// }
-
-// func (e *FunctionLiteral) expression()     {}
-// func (e *FunctionLiteral) Literal() string { return e.Token.Literal }
-// func (e *FunctionLiteral) String() string {
-// 	params := []string{}
-// 	for _, id := range e.Params {
-// 		params = append(params, id.String())
-// 	}
-
-// 	var buf bytes.Buffer
-// 	buf.WriteString("fun")
-// 	buf.WriteString(" ")
-// 	buf.WriteString("(")
-// 	buf.WriteString(strings.Join(params, ", "))
-// 	buf.WriteString(")")
-// 	buf.WriteString(" ")
-// 	buf.WriteString(e.Body.String())
-// 	return buf.String()
-// }
+// FunctionLiteral is an anonymous function. ParamTypes and Result are
+// the optional type annotations like in function definitions.
+type FunctionLiteral struct {
+	Token      token.Token
+	Params     []*Identifier
+	ParamTypes []TypeExpr
+	Result     TypeExpr
+	Body       *BlockStatement
+	Parens
+}
+
+func NewFunLiteral(token token.Token) *FunctionLiteral {
+	return &FunctionLiteral{Token: token}
+}
+
+func (e *FunctionLiteral) expression()         {}
+func (e *FunctionLiteral) Literal() string     { return e.Token.Literal }
+func (e *FunctionLiteral) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *FunctionLiteral) End() token.Position { return e.end(e.Body.End()) }
+func (e *FunctionLiteral) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("fn")
+	buf.WriteString("(")
+	buf.WriteString(strings.Join(typedList(e.Params, e.ParamTypes), ", "))
+	buf.WriteString(")")
+	if e.Result != nil {
+		buf.WriteString(" -> ")
+		buf.WriteString(e.Result.String())
+	}
+	buf.WriteString(" ")
+	buf.WriteString(e.Body.String())
+	return buf.String()
+}
+
+type ArrayLiteral struct {
+	Token    token.Token
+	Elements []Expression
+	Rbrack   token.Token
+	Parens
+}
+
+func NewArrayLiteral(token token.Token) *ArrayLiteral {
+	return &ArrayLiteral{Token: token}
+}
+
+func (e *ArrayLiteral) expression()         {}
+func (e *ArrayLiteral) Literal() string     { return e.Token.Literal }
+func (e *ArrayLiteral) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *ArrayLiteral) End() token.Position {
+	if e.Close.Typ == token.RPAR {
+		return e.Close.End()
+	}
+	if e.Rbrack.Typ == token.RBRK {
+		return e.Rbrack.End()
+	}
+	return e.Token.End()
+}
+func (e *ArrayLiteral) String() string {
+	elems := []string{}
+	for _, elem := range e.Elements {
+		elems = append(elems, elem.String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	buf.WriteString(strings.Join(elems, ", "))
+	buf.WriteString("]")
+	return buf.String()
+}
+
+type IndexExpression struct {
+	Token  token.Token
+	Left   Expression
+	Index  Expression
+	Rbrack token.Token
+	Parens
+}
+
+func NewIndexExpr(token token.Token) *IndexExpression {
+	return &IndexExpression{Token: token}
+}
+
+func (e *IndexExpression) expression()         {}
+func (e *IndexExpression) Literal() string     { return e.Token.Literal }
+func (e *IndexExpression) Pos() token.Position { return e.start(e.Left.Pos()) }
+func (e *IndexExpression) End() token.Position {
+	if e.Close.Typ == token.RPAR {
+		return e.Close.End()
+	}
+	if e.Rbrack.Typ == token.RBRK {
+		return e.Rbrack.End()
+	}
+	return e.Index.End()
+}
+func (e *IndexExpression) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(e.Left.String())
+	buf.WriteString("[")
+	buf.WriteString(e.Index.String())
+	buf.WriteString("]")
+	return buf.String()
+}
+
+type StructLiteral struct {
+	Token  token.Token
+	Module *Identifier // Optional module of a struct of an imported module.
+	Name   *Identifier
+	Fields []*Identifier
+	Values []Expression
+	Rbrace token.Token
+	Parens
+}
+
+func NewStructLiteral(token token.Token) *StructLiteral {
+	return &StructLiteral{Token: token, Fields: []*Identifier{}, Values: []Expression{}}
+}
+
+func (e *StructLiteral) expression()         {}
+func (e *StructLiteral) Literal() string     { return e.Token.Literal }
+func (e *StructLiteral) Pos() token.Position { return e.start(qualifiedPos(e.Module, e.Name)) }
+func (e *StructLiteral) End() token.Position {
+	if e.Close.Typ == token.RPAR {
+		return e.Close.End()
+	}
+	if e.Rbrace.Typ == token.RBRA {
+		return e.Rbrace.End()
+	}
+	return e.Token.End()
+}
+func (e *StructLiteral) String() string {
+	fields := []string{}
+	for i, id := range e.Fields {
+		fields = append(fields, id.String()+": "+e.Values[i].String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(qualified(e.Module, e.Name))
+	buf.WriteString(" { ")
+	buf.WriteString(strings.Join(fields, ", "))
+	buf.WriteString(" }")
+	return buf.String()
+}
+
+type SelectorExpression struct {
+	Token token.Token
+	Left  Expression
+	Field *Identifier
+	Parens
+}
+
+func NewSelectorExpr(token token.Token) *SelectorExpression {
+	return &SelectorExpression{Token: token}
+}
+
+func (e *SelectorExpression) expression()         {}
+func (e *SelectorExpression) typeExpr()           {}
+func (e *SelectorExpression) Literal() string     { return e.Token.Literal }
+func (e *SelectorExpression) Pos() token.Position { return e.start(e.Left.Pos()) }
+func (e *SelectorExpression) End() token.Position { return e.end(e.Field.End()) }
+func (e *SelectorExpression) String() string {
+	return e.Left.String() + "." + e.Field.String()
+}
+
+type MatchExpression struct {
+	Token  token.Token
+	Value  Expression
+	Arms   []*MatchArm
+	Rbrace token.Token
+	Parens
+}
+
+func NewMatchExpr(token token.Token) *MatchExpression {
+	return &MatchExpression{Token: token, Arms: []*MatchArm{}}
+}
+
+func (e *MatchExpression) expression()         {}
+func (e *MatchExpression) Literal() string     { return e.Token.Literal }
+func (e *MatchExpression) Pos() token.Position { return e.start(e.Token.Start()) }
+func (e *MatchExpression) End() token.Position {
+	if e.Close.Typ == token.RPAR {
+		return e.Close.End()
+	}
+	if e.Rbrace.Typ == token.RBRA {
+		return e.Rbrace.End()
+	}
+	return e.Value.End()
+}
+func (e *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range e.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("match")
+	buf.WriteString(" ")
+	buf.WriteString(e.Value.String())
+	buf.WriteString(" { ")
+	buf.WriteString(strings.Join(arms, ", "))
+	buf.WriteString(" }")
+	return buf.String()
+}
+
+// MatchArm is an alternative of a match expression. The pattern is either
+// the name of a variant that binds the fields of the variant or the blank
+// identifier [_] that matches any value. Variants of an imported module
+// are qualified by the module.
+type MatchArm struct {
+	Module   *Identifier // Optional module of the variant.
+	Pattern  *Identifier
+	Bindings []*Identifier
+	Body     Expression
+}
+
+// IsWildcard returns true iff the arm matches any value.
+func (a *MatchArm) IsWildcard() bool {
+	return a.Pattern.Token.Typ == token.BLANK
+}
+
+func (a *MatchArm) Literal() string     { return a.Pattern.Literal() }
+func (a *MatchArm) Pos() token.Position { return qualifiedPos(a.Module, a.Pattern) }
+func (a *MatchArm) End() token.Position { return a.Body.End() }
+func (a *MatchArm) String() string {
+	return qualified(a.Module, a.Pattern) + fieldList(a.Bindings) + " => " + a.Body.String()
+}
+
+// qualified returns the name qualified by the optional module.
+func qualified(mod, name *Identifier) string {
+	if mod == nil {
+		return name.String()
+	}
+	return mod.String() + "." + name.String()
+}
+
+// qualifiedPos returns the position of the name qualified by the optional
+// module.
+func qualifiedPos(mod, name *Identifier) token.Position {
+	if mod == nil {
+		return name.Pos()
+	}
+	return mod.Pos()
+}
 
 type CallExpression struct {
 	Token    token.Token
 	Function Expression
 	Args     []Expression
+	Rparen   token.Token
+	Parens
 }
 
 func NewCallExpr(token token.Token) *CallExpression {
 	return &CallExpression{Token: token}
 }
 
-func (e *CallExpression) expression()     {}
-func (e *CallExpression) Literal() string { return e.Token.Literal }
+func (e *CallExpression) expression()         {}
+func (e *CallExpression) Literal() string     { return e.Token.Literal }
+func (e *CallExpression) Pos() token.Position { return e.start(e.Function.Pos()) }
+func (e *CallExpression) End() token.Position {
+	if e.Close.Typ == token.RPAR {
+		return e.Close.End()
+	}
+	if e.Rparen.Typ == token.RPAR {
+		return e.Rparen.End()
+	}
+	return e.Function.End()
+}
 func (e *CallExpression) String() string {
 	args := []string{}
 	for _, arg := range e.Args {
@@ -21,24 +21,75 @@ func printParseTree(indent int, buf *bytes.Buffer, node Node) string {
 			buf.WriteString("\n")
 		}
 	case *BlockStatement:
-		len := len(n.Statements) - 1
-		buf.WriteString("BLOCK\n")
-		for index := 0; index < len; index++ {
-			printIntermediate(indent, buf, n.Statements[index])
-		}
-		printFinal(indent, buf, n.Statements[len])
+		buf.WriteString("BLOCK")
+		printChildren(indent, buf, n.Statements)
+	case *ImportStatement:
+		buf.WriteString(fmt.Sprintf("IMPORT %s", n.Path))
 	case *LetStatement:
-		buf.WriteString(fmt.Sprintf("LET %s\n", n.Name))
+		buf.WriteString(fmt.Sprintf("LET %s\n", typed(n.Name, n.Type)))
+		printFinal(indent, buf, n.Value)
+	case *AssignStatement:
+		if n.Operator != "" {
+			buf.WriteString(fmt.Sprintf("ASSIGN(%s)\n", n.Token.Literal))
+		} else {
+			buf.WriteString("ASSIGN\n")
+		}
+		printIntermediate(indent, buf, n.Target)
 		printFinal(indent, buf, n.Value)
 	case *ReturnStatement:
 		buf.WriteString("RETURN\n")
 		printFinal(indent, buf, n.Value)
 	case *FunDefStatement:
-		buf.WriteString(fmt.Sprintf("FUN%s\n", n.Params))
+		params := typedList(n.Params, n.ParamTypes)
+		if n.Variadic {
+			params = append(params, "...")
+		}
+		buf.WriteString(fmt.Sprintf("%sFUN%s%s", strings.ToUpper(pub(n.Pub)+linkage(n)), params, result(n.Result)))
+		if n.Body != nil {
+			buf.WriteString("\n")
+			printFinal(indent, buf, n.Body)
+		}
+	case *StructDefStatement:
+		buf.WriteString(fmt.Sprintf("%sSTRUCT %s%s", strings.ToUpper(pub(n.Pub)), n.Name, typedList(n.Fields, n.FieldTypes)))
+	case *EnumDefStatement:
+		buf.WriteString(fmt.Sprintf("%sENUM %s%s", strings.ToUpper(pub(n.Pub)), n.Name, n.Variants))
+	case *IfStatement:
+		buf.WriteString("IF\n")
+		printIntermediate(indent, buf, n.Condition)
+		if n.Alternative == nil {
+			printFinal(indent, buf, n.Consequence)
+		} else {
+			printIntermediate(indent, buf, n.Consequence)
+			printFinal(indent, buf, n.Alternative)
+		}
+	case *WhileStatement:
+		buf.WriteString("WHILE\n")
+		printIntermediate(indent, buf, n.Condition)
+		printFinal(indent, buf, n.Body)
+	case *ForStatement:
+		buf.WriteString("FOR")
+		children := []Node{}
+		if n.Init != nil {
+			children = append(children, n.Init)
+		}
+		if n.Condition != nil {
+			children = append(children, n.Condition)
+		}
+		if n.Post != nil {
+			children = append(children, n.Post)
+		}
+		printChildren(indent, buf, append(children, n.Body))
+	case *BreakStatement:
+		buf.WriteString("BREAK")
+	case *ContinueStatement:
+		buf.WriteString("CONTINUE")
+	case *ExpressionStatement:
+		printParseTree(indent, buf, n.Value)
+	case *BadStatement:
+		buf.WriteString("BAD")
+	case *FunctionLiteral:
+		buf.WriteString(fmt.Sprintf("FUN%s%s\n", typedList(n.Params, n.ParamTypes), result(n.Result)))
 		printFinal(indent, buf, n.Body)
-	// case *FunctionLiteral:
-	//   buf.WriteString(fmt.Sprintf("FUN%s\n", n.Params))
-	//   printFinal(indent, buf, n.Body)
 	case *BinaryExpression:
 		buf.WriteString(fmt.Sprintf("INFIX(%s)\n", n.Operator))
 		printIntermediate(indent, buf, n.Left)
@@ -50,12 +101,67 @@ func printParseTree(indent int, buf *bytes.Buffer, node Node) string {
 		buf.WriteString(n.String())
 	case *Integer:
 		buf.WriteString(n.String())
+	case *Float:
+		buf.WriteString(n.String())
 	case *Boolean:
 		buf.WriteString(n.String())
+	case *String:
+		buf.WriteString(n.String())
+	case *CallExpression:
+		buf.WriteString("CALL")
+		printChildren(indent, buf, append([]Expression{n.Function}, n.Args...))
+	case *ArrayLiteral:
+		buf.WriteString("ARRAY")
+		printChildren(indent, buf, n.Elements)
+	case *IndexExpression:
+		buf.WriteString("INDEX\n")
+		printIntermediate(indent, buf, n.Left)
+		printFinal(indent, buf, n.Index)
+	case *StructLiteral:
+		buf.WriteString(fmt.Sprintf("NEW %s%s", qualified(n.Module, n.Name), n.Fields))
+		printChildren(indent, buf, n.Values)
+	case *SelectorExpression:
+		buf.WriteString(fmt.Sprintf("FIELD(%s)\n", n.Field))
+		printFinal(indent, buf, n.Left)
+	case *MatchExpression:
+		buf.WriteString("MATCH")
+		children := []Node{n.Value}
+		for _, arm := range n.Arms {
+			children = append(children, arm)
+		}
+		printChildren(indent, buf, children)
+	case *MatchArm:
+		buf.WriteString(fmt.Sprintf("CASE %s%s\n", qualified(n.Module, n.Pattern), fieldList(n.Bindings)))
+		printFinal(indent, buf, n.Body)
+	case *BadExpression:
+		buf.WriteString("BAD")
 	}
 	return buf.String()
 }
 
+// result returns the result type annotation of a function or an empty
+// string if it has none.
+func result(typ TypeExpr) string {
+	if typ == nil {
+		return ""
+	}
+	return " -> " + typ.String()
+}
+
+// printChildren prints the nodes on separate lines below the current line.
+func printChildren[T Node](indent int, buf *bytes.Buffer, ns []T) {
+	for i, n := range ns {
+		buf.WriteString("\n")
+		buf.WriteString(strings.Repeat(" ", indent*3))
+		if i < len(ns)-1 {
+			buf.WriteString(" ├ ")
+		} else {
+			buf.WriteString(" └ ")
+		}
+		printParseTree(indent+1, buf, n)
+	}
+}
+
 func printIntermediate(indent int, buf *bytes.Buffer, n Node) {
 	buf.WriteString(strings.Repeat(" ", indent*3))
 	buf.WriteString(" ├ ")
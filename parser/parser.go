@@ -1,24 +1,19 @@
 package parser
 
 import (
-	"fmt"
+	"errors"
+	"math/big"
 	"strconv"
+	"strings"
 
+	"github.com/mhoertnagl/donkey/diag"
 	"github.com/mhoertnagl/donkey/lexer"
 	"github.com/mhoertnagl/donkey/token"
 )
 
-// TODO: for loop
-// TODO: general iteration conditions
-// TODO: switch case?
-// TODO: strings
-// TODO: arrays
 // TODO: pointers?
-// TODO: structs
-// TODO: list support
 // TODO: tuples?
 // TODO: dictionaries?
-// TODO: type inference
 
 const (
 	_       int = iota
@@ -27,24 +22,34 @@ const (
 	AND         // &&
 	EQUALS      // ==, !=
 	COMPARE     // >, <, <=, >=
-	BOR         // |
-	XOR         // ^
-	BAND        // &
+	BOR         // |, ~|
+	XOR         // ^, ~^
+	BAND        // &, ~&
 	SHIFT       // <<, >>, >>>, <<>, <>>
 	SUM         // +, -
-	PRODUCT     // *, /
+	PRODUCT     // *, /, %
 	PREFIX      // -, !, ~
-	CALL        // foo()
+	POWER       // **
+	CALL        // foo(), a[i], p.x
 )
 
+// maxErrors is the number of errors after which the parser gives up.
+const maxErrors = 10
+
 type prefixParslet func() Expression
 type infixParslet func(Expression) Expression
 
 type Parser struct {
 	lexer          *lexer.Lexer
+	prvToken       token.Token // The last consumed token.
 	curToken       token.Token
 	nxtToken       token.Token
-	errors         []string
+	diags          *diag.List
+	numDiags       int  // Number of diagnostics reported before parsing.
+	panicking      bool // An error has been reported for the current statement.
+	stopped        bool // Too many errors have been reported.
+	loops          int  // Number of enclosing loops.
+	noStructLits   bool // Struct literals are not allowed in the current expression.
 	precedences    map[token.TokenType]int
 	prefixParslets map[token.TokenType]prefixParslet
 	infixParslets  map[token.TokenType]infixParslet
@@ -53,7 +58,8 @@ type Parser struct {
 func NewParser(lexer *lexer.Lexer) *Parser {
 	p := &Parser{
 		lexer:          lexer,
-		errors:         []string{},
+		diags:          lexer.Diagnostics(),
+		numDiags:       lexer.Diagnostics().Len(),
 		precedences:    make(map[token.TokenType]int),
 		prefixParslets: make(map[token.TokenType]prefixParslet),
 		infixParslets:  make(map[token.TokenType]infixParslet),
@@ -68,8 +74,11 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.registerPrecedence(token.GT, COMPARE)
 	p.registerPrecedence(token.GE, COMPARE)
 	p.registerPrecedence(token.OR, BOR)
+	p.registerPrecedence(token.NOR, BOR)
 	p.registerPrecedence(token.XOR, XOR)
+	p.registerPrecedence(token.XNOR, XOR)
 	p.registerPrecedence(token.AND, BAND)
+	p.registerPrecedence(token.NAND, BAND)
 	p.registerPrecedence(token.SLL, SHIFT)
 	p.registerPrecedence(token.SRA, SHIFT)
 	p.registerPrecedence(token.SRL, SHIFT)
@@ -79,17 +88,25 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.registerPrecedence(token.MINUS, SUM)
 	p.registerPrecedence(token.TIMES, PRODUCT)
 	p.registerPrecedence(token.DIV, PRODUCT)
+	p.registerPrecedence(token.MOD, PRODUCT)
+	p.registerPrecedence(token.POW, POWER)
 	p.registerPrecedence(token.LPAR, CALL)
+	p.registerPrecedence(token.LBRK, CALL)
+	p.registerPrecedence(token.DOT, CALL)
 
 	p.registerPrefix(token.ID, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseInteger)
+	p.registerPrefix(token.FLOAT, p.parseFloat)
+	p.registerPrefix(token.STR, p.parseString)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.MINUS, p.parsePrefix)
 	p.registerPrefix(token.INV, p.parsePrefix)
 	p.registerPrefix(token.NOT, p.parsePrefix)
 	p.registerPrefix(token.LPAR, p.parseExpressionGroup)
-	// p.registerPrefix(token.FUN, p.parseFunctionLiteral)
+	p.registerPrefix(token.LBRK, p.parseArrayLiteral)
+	p.registerPrefix(token.FUN, p.parseFunctionLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatch)
 
 	p.registerInfix(token.DISJ, p.parseBinary)
 	p.registerInfix(token.CONJ, p.parseBinary)
@@ -100,8 +117,11 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.registerInfix(token.GT, p.parseBinary)
 	p.registerInfix(token.GE, p.parseBinary)
 	p.registerInfix(token.OR, p.parseBinary)
+	p.registerInfix(token.NOR, p.parseBinary)
 	p.registerInfix(token.XOR, p.parseBinary)
+	p.registerInfix(token.XNOR, p.parseBinary)
 	p.registerInfix(token.AND, p.parseBinary)
+	p.registerInfix(token.NAND, p.parseBinary)
 	p.registerInfix(token.SLL, p.parseBinary)
 	p.registerInfix(token.SRA, p.parseBinary)
 	p.registerInfix(token.SRL, p.parseBinary)
@@ -111,7 +131,11 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseBinary)
 	p.registerInfix(token.TIMES, p.parseBinary)
 	p.registerInfix(token.DIV, p.parseBinary)
+	p.registerInfix(token.MOD, p.parseBinary)
+	p.registerInfix(token.POW, p.parsePower)
 	p.registerInfix(token.LPAR, p.parseFunCall)
+	p.registerInfix(token.LBRK, p.parseIndex)
+	p.registerInfix(token.DOT, p.parseSelector)
 
 	// Sets the parsers current and next tokens.
 	p.next()
@@ -143,6 +167,7 @@ func (p *Parser) curTokenPrecedence() int {
 }
 
 func (p *Parser) next() {
+	p.prvToken = p.curToken
 	p.curToken = p.nxtToken
 	p.nxtToken = p.lexer.Next()
 }
@@ -173,88 +198,422 @@ func (p *Parser) consume(tok token.TokenType) {
 		p.next()
 		return
 	}
-	p.error("Expecting [%s] but got [%v].", tok, p.curToken)
+	p.error("P0001", "Expecting [%s] but got [%s].", tok, describe(p.curToken))
+}
+
+// consumeTerminator consumes the semicolon that terminates a statement.
+// Statements that end with a block such as function definitions or if
+// statements may omit the semicolon.
+func (p *Parser) consumeTerminator(stmt Statement) {
+	if p.curTokenIs(token.SCOLON) || !endsWithBlock(stmt) {
+		p.consume(token.SCOLON)
+	}
+}
+
+// synchronize skips tokens until the parser reaches a point where it can
+// resume parsing after an error. This is either after the next semicolon,
+// before a closing brace or before the next statement keyword.
+func (p *Parser) synchronize() {
+	for p.curTokenIsNot(token.EOF) {
+		switch p.curToken.Typ {
+		case token.SCOLON:
+			p.next()
+			return
+		case token.RBRA, token.LET, token.FUN, token.STRUCT, token.ENUM,
+			token.RETURN, token.IF, token.WHILE, token.FOR, token.BREAK,
+			token.CONT, token.IMPORT, token.PUB, token.EXTERN, token.EXPORT:
+			return
+		}
+		p.next()
+	}
+}
+
+// ensureProgress skips the current token if parsing a statement did not
+// consume any tokens. Otherwise an unexpected token would be reported over
+// and over again.
+func (p *Parser) ensureProgress(start token.Token) {
+	if p.curToken == start && p.curTokenIsNot(token.EOF) {
+		p.next()
+	}
 }
 
+func endsWithBlock(stmt Statement) bool {
+	switch n := stmt.(type) {
+	case *FunDefStatement:
+		return n.Body != nil
+	case *StructDefStatement, *EnumDefStatement,
+		*IfStatement, *BlockStatement, *WhileStatement, *ForStatement:
+		return true
+	}
+	return false
+}
+
+// HasNoErrors returns true iff neither the lexer nor the parser reported
+// an error.
 func (p *Parser) HasNoErrors() bool {
-	return len(p.errors) == 0
+	return !p.diags.HasErrors()
 }
 
+// HasErrors returns true iff the lexer or the parser reported an error.
 func (p *Parser) HasErrors() bool {
-	return len(p.errors) > 0
+	return p.diags.HasErrors()
+}
+
+// Errors returns the diagnostics reported by the lexer and the parser.
+func (p *Parser) Errors() []*diag.Diagnostic {
+	return p.diags.Items()
 }
 
-func (p *Parser) Errors() []string {
-	return p.errors
+// Diagnostics returns the list the parser reports into.
+func (p *Parser) Diagnostics() *diag.List {
+	return p.diags
 }
 
-func (p *Parser) error(format string, a ...any) {
-	p.errors = append(p.errors, fmt.Sprintf(format, a...))
+// error reports an error at the current token. Only the first error of a
+// statement is reported. Subsequent errors are most likely caused by the
+// first one. Illegal tokens have been reported by the lexer already.
+func (p *Parser) error(code string, format string, a ...any) {
+	if p.panicking || p.stopped {
+		return
+	}
+	p.panicking = true
+	if p.curTokenIs(token.ILLEGAL) {
+		return
+	}
+	if p.diags.Len()-p.numDiags >= maxErrors {
+		p.diags.TokenErrorf("P0004", p.curToken, "Too many errors.")
+		p.stopped = true
+		return
+	}
+	p.diags.TokenErrorf(code, p.curToken, format, a...)
+}
+
+// describe returns a short description of the token for error messages.
+func describe(tok token.Token) string {
+	if tok.Typ == token.EOF {
+		return "end of file"
+	}
+	return tok.Literal
 }
 
 func (p *Parser) Parse() *Program {
 	prog := NewProgram()
-	for p.curTokenIsNot(token.EOF) {
-		stmt := p.parseStatement()
-		prog.Statements = append(prog.Statements, stmt)
-		p.consume(token.SCOLON)
+	for p.curTokenIsNot(token.EOF) && !p.stopped {
+		prog.Statements = append(prog.Statements, p.statement())
 	}
 	return prog
 }
 
+// statement parses a statement and its terminator. If the statement
+// contains a syntax error, the parser skips ahead to a point where it can
+// resume and returns a BadStatement instead.
+func (p *Parser) statement() Statement {
+	start := p.curToken
+	stmt := p.parseStatement()
+	if p.panicking {
+		stmt = NewBadStmt(start)
+	} else {
+		p.consumeTerminator(stmt)
+	}
+	if p.panicking {
+		p.synchronize()
+		p.panicking = false
+	}
+	p.ensureProgress(start)
+	if bad, ok := stmt.(*BadStatement); ok {
+		bad.Last = p.prvToken
+	}
+	return stmt
+}
+
 func (p *Parser) parseStatement() Statement {
 	switch p.curToken.Typ {
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.PUB:
+		return p.parsePubStatement()
+	case token.EXTERN:
+		return p.parseExternStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
 	case token.LET:
 		return p.parseLetStatement()
 	case token.FUN:
+		// Function literals may start an expression statement.
+		if p.nxtTokenIs(token.LPAR) {
+			return p.parseExpressionStatement()
+		}
 		return p.parseFunDefStatement()
+	case token.STRUCT:
+		return p.parseStructDefStatement()
+	case token.ENUM:
+		return p.parseEnumDefStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
 	case token.IF:
 		return p.parseIfStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONT:
+		return p.parseContinueStatement()
 	case token.LBRA:
 		return p.parseBlockStatement()
 	}
 	return p.parseExpressionStatement()
 }
 
-// let <Identifier> = <Expression>
+// import <String>
+func (p *Parser) parseImportStatement() *ImportStatement {
+	stmt := NewImportStmt(p.curToken)
+	p.consume(token.IMPORT)
+	if p.curTokenIs(token.STR) {
+		stmt.Path = NewStringLiteral(p.curToken)
+	}
+	p.consume(token.STR)
+	return stmt
+}
+
+// pub <FunDefStatement>
+// pub <ExternStatement>
+// pub <ExportStatement>
+// pub <StructDefStatement>
+// pub <EnumDefStatement>
+func (p *Parser) parsePubStatement() Statement {
+	start := p.curToken
+	p.consume(token.PUB)
+	switch p.curToken.Typ {
+	case token.FUN:
+		stmt := p.parseFunDefStatement()
+		stmt.Prefix, stmt.Pub = start, true
+		return stmt
+	case token.EXTERN:
+		stmt := p.parseExternStatement()
+		stmt.Prefix, stmt.Pub = start, true
+		return stmt
+	case token.EXPORT:
+		stmt := p.parseExportStatement()
+		stmt.Prefix, stmt.Pub = start, true
+		return stmt
+	case token.STRUCT:
+		stmt := p.parseStructDefStatement()
+		stmt.Prefix, stmt.Pub = start, true
+		return stmt
+	case token.ENUM:
+		stmt := p.parseEnumDefStatement()
+		stmt.Prefix, stmt.Pub = start, true
+		return stmt
+	}
+	p.error("P0008", "Expecting a definition after [pub] but got [%s].", describe(p.curToken))
+	return NewBadStmt(start)
+}
+
+// let <Identifier> <TypeAnnotation>? = <Expression>
 func (p *Parser) parseLetStatement() *LetStatement {
 	stmt := NewLetStmt(p.curToken)
 	p.consume(token.LET)
 	stmt.Name = p.identifier()
+	stmt.Type = p.parseTypeAnnotation()
 	p.consume(token.ASSIGN)
 	stmt.Value = p.parseExpression(LOWEST)
 	return stmt
 }
 
-// fn <Identifier> <FunctionParams> <BlockStatement>
-func (p *Parser) parseFunDefStatement() Statement {
+// fn <Identifier> <FunctionParams> <ResultType>? <BlockStatement>
+func (p *Parser) parseFunDefStatement() *FunDefStatement {
+	stmt := NewFunDefStmt(p.curToken)
+	p.consume(token.FUN)
+	stmt.Name = p.identifier()
+	stmt.Params, stmt.ParamTypes = p.parseFunctionParams()
+	stmt.Rpar = p.prvToken
+	stmt.Result = p.parseResultType()
+	stmt.Body = p.parseFunctionBody()
+	return stmt
+}
+
+// extern fn <Identifier> <ExternParams> <ResultType>?
+func (p *Parser) parseExternStatement() *FunDefStatement {
+	start := p.curToken
+	p.consume(token.EXTERN)
 	stmt := NewFunDefStmt(p.curToken)
+	stmt.Prefix, stmt.Extern = start, true
 	p.consume(token.FUN)
 	stmt.Name = p.identifier()
-	stmt.Params = p.parseFunctionParams()
-	stmt.Body = p.parseBlockStatement()
+	p.parseExternParams(stmt)
+	stmt.Result = p.parseResultType()
+	return stmt
+}
+
+// ( (<Identifier> <TypeAnnotation>?)* (, ...)? )
+// ( ... )
+func (p *Parser) parseExternParams(stmt *FunDefStatement) {
+	stmt.Params = []*Identifier{}
+	stmt.ParamTypes = []TypeExpr{}
+	p.consume(token.LPAR)
+	for p.curTokenIsNone(token.RPAR, token.EOF) {
+		if p.curTokenIs(token.ELLIPSIS) {
+			p.consume(token.ELLIPSIS)
+			stmt.Variadic = true
+			break
+		}
+		stmt.Params = append(stmt.Params, p.identifier())
+		stmt.ParamTypes = append(stmt.ParamTypes, p.parseTypeAnnotation())
+		if p.curTokenIsNot(token.COMMA) {
+			break
+		}
+		p.consume(token.COMMA)
+	}
+	stmt.Rpar = p.curToken
+	p.consume(token.RPAR)
+}
+
+// export <FunDefStatement>
+func (p *Parser) parseExportStatement() *FunDefStatement {
+	start := p.curToken
+	p.consume(token.EXPORT)
+	stmt := p.parseFunDefStatement()
+	stmt.Prefix, stmt.Export = start, true
 	return stmt
 }
 
-// ( <Identifier>* )
-func (p *Parser) parseFunctionParams() []*Identifier {
+// ( (<Identifier> <TypeAnnotation>?)* )
+func (p *Parser) parseFunctionParams() ([]*Identifier, []TypeExpr) {
 	params := []*Identifier{}
+	types := []TypeExpr{}
 	p.consume(token.LPAR)
 	if p.curTokenIs(token.RPAR) {
 		p.consume(token.RPAR)
-		return params
+		return params, types
 	}
-	param := p.identifier()
-	params = append(params, param)
+	params = append(params, p.identifier())
+	types = append(types, p.parseTypeAnnotation())
 	for p.curTokenIs(token.COMMA) {
 		p.consume(token.COMMA)
-		param := p.identifier()
-		params = append(params, param)
+		params = append(params, p.identifier())
+		types = append(types, p.parseTypeAnnotation())
 	}
 	p.consume(token.RPAR)
-	return params
+	return params, types
+}
+
+// : <Type>
+//
+// Returns nil if there is no type annotation.
+func (p *Parser) parseTypeAnnotation() TypeExpr {
+	if p.curTokenIsNot(token.COLON) {
+		return nil
+	}
+	p.consume(token.COLON)
+	return p.parseType()
+}
+
+// -> <Type>
+//
+// Returns nil if there is no result type.
+func (p *Parser) parseResultType() TypeExpr {
+	if p.curTokenIsNot(token.ARROW) {
+		return nil
+	}
+	p.consume(token.ARROW)
+	return p.parseType()
+}
+
+// <Identifier>
+// <Identifier> . <Identifier>
+// [ <Type> ]
+// fn ( <Type>* ) <ResultType>?
+func (p *Parser) parseType() TypeExpr {
+	switch p.curToken.Typ {
+	case token.LBRK:
+		typ := &ArrayType{Token: p.curToken}
+		p.consume(token.LBRK)
+		typ.Elem = p.parseType()
+		p.consume(token.RBRK)
+		typ.Rbrack = p.prvToken
+		return typ
+	case token.FUN:
+		typ := &FuncType{Token: p.curToken, Params: []TypeExpr{}}
+		p.consume(token.FUN)
+		p.consume(token.LPAR)
+		for p.curTokenIsNot(token.RPAR) && !p.panicking {
+			typ.Params = append(typ.Params, p.parseType())
+			if p.curTokenIsNot(token.RPAR) {
+				p.consume(token.COMMA)
+			}
+		}
+		p.consume(token.RPAR)
+		typ.Rpar = p.prvToken
+		typ.Result = p.parseResultType()
+		return typ
+	}
+	id := p.identifier()
+	if p.curTokenIsNot(token.DOT) {
+		return id
+	}
+	// A type of an imported module.
+	typ := NewSelectorExpr(p.curToken)
+	p.consume(token.DOT)
+	typ.Left = id
+	typ.Field = p.identifier()
+	return typ
+}
+
+// <BlockStatement>
+func (p *Parser) parseFunctionBody() *BlockStatement {
+	// Loops do not extend into function bodies.
+	loops := p.loops
+	p.loops = 0
+	body := p.parseBlockStatement()
+	p.loops = loops
+	return body
+}
+
+// struct <Identifier> { (<Identifier> <TypeAnnotation>?)* }
+func (p *Parser) parseStructDefStatement() *StructDefStatement {
+	stmt := NewStructDefStmt(p.curToken)
+	p.consume(token.STRUCT)
+	stmt.Name = p.identifier()
+	p.consume(token.LBRA)
+	for p.curTokenIsNot(token.RBRA) && !p.panicking {
+		stmt.Fields = append(stmt.Fields, p.identifier())
+		stmt.FieldTypes = append(stmt.FieldTypes, p.parseTypeAnnotation())
+		if p.curTokenIsNot(token.RBRA) {
+			p.consume(token.COMMA)
+		}
+	}
+	p.consume(token.RBRA)
+	stmt.Rbrace = p.prvToken
+	return stmt
+}
+
+// enum <Identifier> { <Variant>* }
+func (p *Parser) parseEnumDefStatement() *EnumDefStatement {
+	stmt := NewEnumDefStmt(p.curToken)
+	p.consume(token.ENUM)
+	stmt.Name = p.identifier()
+	p.consume(token.LBRA)
+	for p.curTokenIsNot(token.RBRA) && !p.panicking {
+		stmt.Variants = append(stmt.Variants, p.parseVariant())
+		if p.curTokenIsNot(token.RBRA) {
+			p.consume(token.COMMA)
+		}
+	}
+	p.consume(token.RBRA)
+	stmt.Rbrace = p.prvToken
+	return stmt
+}
+
+// <Identifier>
+// <Identifier> ( (<Identifier> <TypeAnnotation>?)* )
+func (p *Parser) parseVariant() *Variant {
+	v := &Variant{Name: p.identifier(), Fields: []*Identifier{}, FieldTypes: []TypeExpr{}}
+	if p.curTokenIs(token.LPAR) {
+		v.Fields, v.FieldTypes = p.parseFunctionParams()
+	}
+	return v
 }
 
 // TODO: return <nil>
@@ -270,7 +629,7 @@ func (p *Parser) parseReturnStatement() *ReturnStatement {
 func (p *Parser) parseIfStatement() *IfStatement {
 	stmt := NewIfStmt(p.curToken)
 	p.consume(token.IF)
-	stmt.Condition = p.parseExpression(LOWEST)
+	stmt.Condition = p.parseClauseExpression()
 	stmt.Consequence = p.parseBlockStatement()
 	stmt.Alternative = p.parseElseStatement()
 	return stmt
@@ -290,31 +649,166 @@ func (p *Parser) parseElseStatement() Statement {
 	return nil
 }
 
+// while <Expression> <BlockStatement>
+func (p *Parser) parseWhileStatement() *WhileStatement {
+	stmt := NewWhileStmt(p.curToken)
+	p.consume(token.WHILE)
+	stmt.Condition = p.parseClauseExpression()
+	stmt.Body = p.parseLoopBody()
+	return stmt
+}
+
+// for <SimpleStatement>? ; <Expression>? ; <SimpleStatement>? <BlockStatement>
+func (p *Parser) parseForStatement() *ForStatement {
+	stmt := NewForStmt(p.curToken)
+	p.consume(token.FOR)
+	defer p.setNoStructLits(p.setNoStructLits(true))
+	if p.curTokenIsNot(token.SCOLON) {
+		stmt.Init = p.parseSimpleStatement()
+	}
+	p.consume(token.SCOLON)
+	if p.curTokenIsNot(token.SCOLON) {
+		stmt.Condition = p.parseExpression(LOWEST)
+	}
+	p.consume(token.SCOLON)
+	if p.curTokenIsNot(token.LBRA) {
+		stmt.Post = p.parseSimpleStatement()
+	}
+	stmt.Body = p.parseLoopBody()
+	return stmt
+}
+
+// <LetStatement>
+// <ExpressionStatement>
+// <AssignStatement>
+func (p *Parser) parseSimpleStatement() Statement {
+	if p.curTokenIs(token.LET) {
+		return p.parseLetStatement()
+	}
+	return p.parseExpressionStatement()
+}
+
+func (p *Parser) parseLoopBody() *BlockStatement {
+	p.loops++
+	body := p.parseBlockStatement()
+	p.loops--
+	return body
+}
+
+// break
+func (p *Parser) parseBreakStatement() *BreakStatement {
+	stmt := NewBreakStmt(p.curToken)
+	if p.loops == 0 {
+		p.error("P0006", "Break outside of a loop.")
+	}
+	p.consume(token.BREAK)
+	return stmt
+}
+
+// continue
+func (p *Parser) parseContinueStatement() *ContinueStatement {
+	stmt := NewContinueStmt(p.curToken)
+	if p.loops == 0 {
+		p.error("P0006", "Continue outside of a loop.")
+	}
+	p.consume(token.CONT)
+	return stmt
+}
+
 // { <Statement>* }
 func (p *Parser) parseBlockStatement() *BlockStatement {
+	defer p.setNoStructLits(p.setNoStructLits(false))
 	block := NewBlockStmt(p.curToken)
 	p.consume(token.LBRA)
-	for p.curTokenIsNone(token.RBRA, token.EOF) {
-		stmt := p.parseStatement()
-		block.Statements = append(block.Statements, stmt)
-		p.consume(token.SCOLON)
+	for p.curTokenIsNone(token.RBRA, token.EOF) && !p.stopped {
+		block.Statements = append(block.Statements, p.statement())
 	}
 	p.consume(token.RBRA)
+	block.Rbrace = p.prvToken
 	return block
 }
 
 // <Expression>
-func (p *Parser) parseExpressionStatement() *ExpressionStatement {
+// <Expression> = <Expression>
+// <Expression> <CompoundOperator> <Expression>
+// <Expression> ++
+// <Expression> --
+func (p *Parser) parseExpressionStatement() Statement {
 	stmt := NewExprStmt(p.curToken)
 	stmt.Value = p.parseExpression(LOWEST)
+	if p.curTokenIsAssignment() {
+		return p.parseAssignStatement(stmt.Value)
+	}
 	return stmt
 }
 
+// <Identifier> = <Expression>
+// <IndexExpression> = <Expression>
+// <SelectorExpression> = <Expression>
+//
+// Compound assignments such as a += 1 have the same targets. Increments
+// a++ and decrements a-- are compound assignments of the literal 1.
+func (p *Parser) parseAssignStatement(target Expression) *AssignStatement {
+	stmt := NewAssignStmt(p.curToken)
+	stmt.Target = target
+	if !isAssignable(target) {
+		p.error("P0005", "Cannot assign to [%s].", target)
+	}
+	if p.curTokenIs(token.INC) || p.curTokenIs(token.DEC) {
+		one := p.curToken
+		one.Typ = token.INT
+		one.Literal = "1"
+		stmt.Value = &Integer{Token: one, Value: big.NewInt(1)}
+		p.next()
+		return stmt
+	}
+	p.next() // Consume assignment operator.
+	stmt.Value = p.parseExpression(LOWEST)
+	return stmt
+}
+
+// curTokenIsAssignment returns true iff the current token is = or a
+// compound assignment operator.
+func (p *Parser) curTokenIsAssignment() bool {
+	_, ok := token.CompoundOp(p.curToken.Typ)
+	return ok || p.curTokenIs(token.ASSIGN)
+}
+
+// isAssignable returns true iff the expression may be the target of an
+// assignment. Fields can only be assigned if the struct is stored in a
+// variable or an array.
+func isAssignable(e Expression) bool {
+	switch e := e.(type) {
+	case *Identifier, *IndexExpression:
+		return true
+	case *SelectorExpression:
+		return isAssignable(e.Left)
+	}
+	return false
+}
+
+// parseClauseExpression parses the condition of an if or while statement
+// or the value of a match expression.
+// Struct literals are only allowed in parentheses, because their opening
+// brace cannot be told apart from the body of the statement.
+func (p *Parser) parseClauseExpression() Expression {
+	defer p.setNoStructLits(p.setNoStructLits(true))
+	return p.parseExpression(LOWEST)
+}
+
+// setNoStructLits disallows or allows struct literals and returns the
+// previous setting.
+func (p *Parser) setNoStructLits(no bool) bool {
+	prev := p.noStructLits
+	p.noStructLits = no
+	return prev
+}
+
 func (p *Parser) parseExpression(pre int) Expression {
 	prefix := p.prefixParslets[p.curToken.Typ]
 	if prefix == nil {
-		p.error("No prefix parslet found for token [%s].", p.curToken.Literal)
-		return nil
+		p.error("P0002", "Expecting an expression but got [%s].", describe(p.curToken))
+		return NewBadExpr(p.curToken)
 	}
 	left := prefix()
 	for p.curTokenIsNone(token.SCOLON, token.EOF) && pre < p.curTokenPrecedence() {
@@ -328,7 +822,11 @@ func (p *Parser) parseExpression(pre int) Expression {
 }
 
 func (p *Parser) parseIdentifier() Expression {
-	return p.identifier()
+	id := p.identifier()
+	if p.curTokenIs(token.LBRA) && !p.noStructLits {
+		return p.parseStructLiteral(id)
+	}
+	return id
 }
 
 func (p *Parser) identifier() *Identifier {
@@ -339,15 +837,36 @@ func (p *Parser) identifier() *Identifier {
 
 func (p *Parser) parseInteger() Expression {
 	expr := NewIntLiteral(p.curToken)
-	num, err := strconv.ParseInt(p.curToken.Literal, 10, 64)
-	if err != nil {
-		p.error("Invaild number [%s].", p.curToken.Literal)
+	num, ok := parseUint(p.curToken.Literal)
+	if !ok {
+		p.error("P0003", "Invalid number [%s].", p.curToken.Literal)
+	} else if num.BitLen() > maxIntBits {
+		p.error("P0007", "Number [%s] is out of range.", p.curToken.Literal)
 	}
 	expr.Value = num
 	p.next() // Consume integer.
 	return expr
 }
 
+func (p *Parser) parseFloat() Expression {
+	expr := NewFloatLiteral(p.curToken)
+	num, err := strconv.ParseFloat(strings.ReplaceAll(p.curToken.Literal, "_", ""), 64)
+	if errors.Is(err, strconv.ErrRange) {
+		p.error("P0007", "Number [%s] is out of range.", p.curToken.Literal)
+	} else if err != nil {
+		p.error("P0003", "Invalid number [%s].", p.curToken.Literal)
+	}
+	expr.Value = num
+	p.next() // Consume float.
+	return expr
+}
+
+func (p *Parser) parseString() Expression {
+	expr := NewStringLiteral(p.curToken)
+	p.next() // Consume string.
+	return expr
+}
+
 func (p *Parser) parseBoolean() Expression {
 	expr := NewBoolLiteral(p.curToken)
 	p.next() // Consume boolean.
@@ -370,32 +889,153 @@ func (p *Parser) parseBinary(left Expression) Expression {
 	return expr
 }
 
+// parsePower parses the right associative ** operator. It binds tighter
+// than the prefix operators so -2 ** 2 is -(2 ** 2). The exponent is
+// parsed with the precedence of the prefix operators. It may thus be
+// negated and contain further ** operators.
+func (p *Parser) parsePower(left Expression) Expression {
+	expr := NewBinaryExpr(p.curToken)
+	expr.Left = left
+	p.next() // Consume operator.
+	expr.Right = p.parseExpression(PREFIX)
+	return expr
+}
+
 // ( <Expression> )
 func (p *Parser) parseExpressionGroup() Expression {
+	defer p.setNoStructLits(p.setNoStructLits(false))
+	open := p.curToken
 	p.consume(token.LPAR)
 	expr := p.parseExpression(LOWEST)
+	// Enclosing parentheses replace the ones of a nested group.
+	if p.curTokenIs(token.RPAR) {
+		parens := expr.parens()
+		parens.Open, parens.Close = open, p.curToken
+	}
 	p.consume(token.RPAR)
 	return expr
 }
 
-// // fun <FunctionParams> <BlockStatement>
-// func (p *Parser) parseFunctionLiteral() Expression {
-// 	expr := &FunctionLiteral{Token: p.curToken}
-// 	p.consume(token.FUN)
-// 	expr.Params = p.parseFunctionParams()
-// 	expr.Body = p.parseBlockStatement()
-// 	return expr
-// }
+// fn <FunctionParams> <ResultType>? <BlockStatement>
+func (p *Parser) parseFunctionLiteral() Expression {
+	// A named function definition cannot be used as an expression.
+	if !p.nxtTokenIs(token.LPAR) {
+		p.error("P0002", "Expecting an expression but got [%s].", describe(p.curToken))
+		return NewBadExpr(p.curToken)
+	}
+	expr := NewFunLiteral(p.curToken)
+	p.consume(token.FUN)
+	expr.Params, expr.ParamTypes = p.parseFunctionParams()
+	expr.Result = p.parseResultType()
+	expr.Body = p.parseFunctionBody()
+	return expr
+}
 
 // <Expression> ( <Expression>* )
 func (p *Parser) parseFunCall(left Expression) Expression {
 	expr := NewCallExpr(p.curToken)
 	expr.Function = left
 	expr.Args = p.parseExprSeq(token.LPAR, token.COMMA, token.RPAR)
+	expr.Rparen = p.prvToken
+	return expr
+}
+
+// [ <Expression>* ]
+func (p *Parser) parseArrayLiteral() Expression {
+	expr := NewArrayLiteral(p.curToken)
+	expr.Elements = p.parseExprSeq(token.LBRK, token.COMMA, token.RBRK)
+	expr.Rbrack = p.prvToken
+	return expr
+}
+
+// <Expression> [ <Expression> ]
+func (p *Parser) parseIndex(left Expression) Expression {
+	defer p.setNoStructLits(p.setNoStructLits(false))
+	expr := NewIndexExpr(p.curToken)
+	expr.Left = left
+	p.consume(token.LBRK)
+	expr.Index = p.parseExpression(LOWEST)
+	p.consume(token.RBRK)
+	expr.Rbrack = p.prvToken
+	return expr
+}
+
+// <Identifier> { (<Identifier> : <Expression>)* }
+func (p *Parser) parseStructLiteral(name *Identifier) *StructLiteral {
+	defer p.setNoStructLits(p.setNoStructLits(false))
+	expr := NewStructLiteral(p.curToken)
+	expr.Name = name
+	p.consume(token.LBRA)
+	for p.curTokenIsNot(token.RBRA) && !p.panicking {
+		expr.Fields = append(expr.Fields, p.identifier())
+		p.consume(token.COLON)
+		expr.Values = append(expr.Values, p.parseExpression(LOWEST))
+		if p.curTokenIsNot(token.RBRA) {
+			p.consume(token.COMMA)
+		}
+	}
+	p.consume(token.RBRA)
+	expr.Rbrace = p.prvToken
+	return expr
+}
+
+// match <Expression> { (<Pattern> => <Expression>)* }
+func (p *Parser) parseMatch() Expression {
+	expr := NewMatchExpr(p.curToken)
+	p.consume(token.MATCH)
+	expr.Value = p.parseClauseExpression()
+	defer p.setNoStructLits(p.setNoStructLits(false))
+	p.consume(token.LBRA)
+	for p.curTokenIsNot(token.RBRA) && !p.panicking {
+		expr.Arms = append(expr.Arms, p.parseMatchArm())
+		if p.curTokenIsNot(token.RBRA) {
+			p.consume(token.COMMA)
+		}
+	}
+	p.consume(token.RBRA)
+	expr.Rbrace = p.prvToken
+	return expr
+}
+
+// _ => <Expression>
+// <Variant> => <Expression>
+// <Identifier> . <Variant> => <Expression>
+func (p *Parser) parseMatchArm() *MatchArm {
+	arm := &MatchArm{Bindings: []*Identifier{}}
+	if p.curTokenIs(token.BLANK) {
+		arm.Pattern = NewIdentifier(p.curToken)
+		p.consume(token.BLANK)
+	} else {
+		if p.nxtTokenIs(token.DOT) {
+			arm.Module = p.identifier()
+			p.consume(token.DOT)
+		}
+		v := p.parseVariant()
+		arm.Pattern, arm.Bindings = v.Name, v.Fields
+	}
+	p.consume(token.DARROW)
+	arm.Body = p.parseExpression(LOWEST)
+	return arm
+}
+
+// <Expression> . <Identifier>
+// <Identifier> . <Identifier> { (<Identifier> : <Expression>)* }
+func (p *Parser) parseSelector(left Expression) Expression {
+	expr := NewSelectorExpr(p.curToken)
+	expr.Left = left
+	p.consume(token.DOT)
+	expr.Field = p.identifier()
+	// A struct literal of a struct of an imported module.
+	if mod, ok := left.(*Identifier); ok && p.curTokenIs(token.LBRA) && !p.noStructLits {
+		lit := p.parseStructLiteral(expr.Field)
+		lit.Module = mod
+		return lit
+	}
 	return expr
 }
 
 func (p *Parser) parseExprSeq(start, delim, end token.TokenType) []Expression {
+	defer p.setNoStructLits(p.setNoStructLits(false))
 	exprs := []Expression{}
 	p.consume(start)
 	if p.curTokenIs(end) {
@@ -412,3 +1052,31 @@ func (p *Parser) parseExprSeq(start, delim, end token.TokenType) []Expression {
 	p.consume(end)
 	return exprs
 }
+
+// maxIntBits is the size of the largest integer type. Literals that do not
+// fit into it cannot have any integer type.
+const maxIntBits = 128
+
+// parseUint parses an integer literal with an optional base prefix [0x],
+// [0b] or [0o] and underscores as digit separators. The result is zero if
+// the literal is invalid.
+func parseUint(literal string) (*big.Int, bool) {
+	base := 10
+	switch {
+	case strings.HasPrefix(literal, "0x"):
+		base = 16
+	case strings.HasPrefix(literal, "0b"):
+		base = 2
+	case strings.HasPrefix(literal, "0o"):
+		base = 8
+	}
+	digits := literal
+	if base != 10 {
+		digits = literal[2:]
+	}
+	num, ok := new(big.Int).SetString(strings.ReplaceAll(digits, "_", ""), base)
+	if !ok || num.Sign() < 0 {
+		return new(big.Int), false
+	}
+	return num, true
+}
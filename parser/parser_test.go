@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/mhoertnagl/donkey/lexer"
@@ -15,6 +17,73 @@ func TestLetStatements(t *testing.T) {
 	test(t, "let a = 0;", "let a = 0;", 1)
 }
 
+func TestAssignStatements(t *testing.T) {
+	test(t, "a = 0;", "a = 0;", 1)
+	test(t, "a = b + 1;", "a = (b + 1);", 1)
+	test(t, "{ let a = 0; a = a * 2; }", "{ let a = 0;a = (a * 2); }", 1)
+	testError(t, "1 = 2;", "1:3: error[P0005]: Cannot assign to [1].")
+	testError(t, "a + b = 2;", "1:7: error[P0005]: Cannot assign to [(a + b)].")
+	testErrors(t, "a = ;", 1)
+}
+
+func TestCompoundAssignStatements(t *testing.T) {
+	test(t, "a += 1;", "a += 1;", 1)
+	test(t, "a -= b * 2;", "a -= (b * 2);", 1)
+	test(t, "a[i] *= 2;", "a[i] *= 2;", 1)
+	test(t, "p.x /= 2;", "p.x /= 2;", 1)
+	test(t, "a &= 1; a |= 2; a ^= 3;", "a &= 1;a |= 2;a ^= 3;", 3)
+	test(t, "a <<= 1; a >>= 2; a >>>= 3;", "a <<= 1;a >>= 2;a >>>= 3;", 3)
+	test(t, "a <<>= 1; a <>>= 2;", "a <<>= 1;a <>>= 2;", 2)
+	test(t, "a %= 3; a **= 2;", "a %= 3;a **= 2;", 2)
+	test(t, "a++; a[i]--;", "a++;a[i]--;", 2)
+	test(t, "for ; ; i++ { }", "for ; ; i++ {  }", 1)
+	test(t, "for ; ; i += 1 { }", "for ; ; i += 1 {  }", 1)
+	testError(t, "1 += 2;", "1:3: error[P0005]: Cannot assign to [1].")
+	testError(t, "f()++;", "1:4: error[P0005]: Cannot assign to [f()].")
+}
+
+func TestIntegerLiterals(t *testing.T) {
+	test(t, "42;", "42;", 1)
+	test(t, "1_000_000;", "1000000;", 1)
+	test(t, "0xFF;", "255;", 1)
+	test(t, "0xdead_beef;", "3735928559;", 1)
+	test(t, "0b1010;", "10;", 1)
+	test(t, "0b_1111_0000;", "240;", 1)
+	test(t, "0o17;", "15;", 1)
+	test(t, "9223372036854775807;", "9223372036854775807;", 1)
+	test(t, "9223372036854775808;", "9223372036854775808;", 1)
+	test(t, "0xFFFFFFFFFFFFFFFF;", "18446744073709551615;", 1)
+	test(t, "18446744073709551616;", "18446744073709551616;", 1)
+	test(t, "0xFFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF;", "340282366920938463463374607431768211455;", 1)
+	testError(t, "340282366920938463463374607431768211456;", "1:1: error[P0007]: Number [340282366920938463463374607431768211456] is out of range.")
+	testError(t, "let a = 0x1_0000_0000_0000_0000_0000_0000_0000_0000;", "1:9: error[P0007]: Number [0x1_0000_0000_0000_0000_0000_0000_0000_0000] is out of range.")
+	testError(t, "0b102;", "1:1: error[L0004]: Invalid digit [2] in binary literal [0b102].")
+}
+
+func TestFloatLiterals(t *testing.T) {
+	test(t, "1.5;", "1.5;", 1)
+	test(t, "1.0;", "1.0;", 1)
+	test(t, "1_000.25;", "1000.25;", 1)
+	test(t, "1.5e-3;", "0.0015;", 1)
+	test(t, "2E3;", "2000.0;", 1)
+	test(t, "1e100;", "1e+100;", 1)
+	test(t, "a.b + 1.5;", "(a.b + 1.5);", 1)
+	test(t, "-0.5 * x;", "((-0.5) * x);", 1)
+	testError(t, "1e400;", "1:1: error[P0007]: Number [1e400] is out of range.")
+}
+
+func TestStringLiterals(t *testing.T) {
+	test(t, `"";`, `"";`, 1)
+	test(t, `let s = "a \"b\"\n";`, `let s = "a \"b\"\n";`, 1)
+	test(t, `print("x");`, `print("x");`, 1)
+	testValue(t, `"a\tb";`, "a\tb")
+	testValue(t, `"\\\"\r\0";`, "\\\"\r\x00")
+	testValue(t, `"\x41\x7a";`, "Az")
+	testValue(t, `"\u{48}\u{e9}\u{1F600}";`, "Hé😀")
+	testError(t, `let s = "abc;`, "1:9: error[L0006]: Unterminated string.")
+	testError(t, `"\q";`, "1:2: error[L0007]: Invalid escape sequence [\\q].")
+}
+
 func TestReturnStatements(t *testing.T) {
 	test(t, "return 42;", "return 42;", 1)
 	test(t, "return a;", "return a;", 1)
@@ -28,7 +97,7 @@ func TestExpressionStatements(t *testing.T) {
 	test(t, "!true;", "(!true);", 1)
 	test(t, "~0;", "(~0);", 1)
 	test(t, "~~0;", "(~(~0));", 1)
-	test(t, "--99;", "(-(-99));", 1)
+	test(t, "- -99;", "(-(-99));", 1)
 	test(t, "!~-a;", "(!(~(-a)));", 1)
 
 	test(t, "false || false;", "(false || false);", 1)
@@ -42,6 +111,9 @@ func TestExpressionStatements(t *testing.T) {
 	test(t, "5 | 5;", "(5 | 5);", 1)
 	test(t, "5 ^ 5;", "(5 ^ 5);", 1)
 	test(t, "5 & 5;", "(5 & 5);", 1)
+	test(t, "5 ~| 5;", "(5 ~| 5);", 1)
+	test(t, "5 ~^ 5;", "(5 ~^ 5);", 1)
+	test(t, "5 ~& 5;", "(5 ~& 5);", 1)
 	test(t, "5 << 5;", "(5 << 5);", 1)
 	test(t, "5 >> 5;", "(5 >> 5);", 1)
 	test(t, "5 >>> 5;", "(5 >>> 5);", 1)
@@ -51,6 +123,8 @@ func TestExpressionStatements(t *testing.T) {
 	test(t, "5 - 5;", "(5 - 5);", 1)
 	test(t, "5 * 5;", "(5 * 5);", 1)
 	test(t, "5 / 5;", "(5 / 5);", 1)
+	test(t, "5 % 5;", "(5 % 5);", 1)
+	test(t, "5 ** 5;", "(5 ** 5);", 1)
 
 	test(t, "1 - -2;", "(1 - (-2));", 1)
 	test(t, "-1 - 2;", "((-1) - 2);", 1)
@@ -63,6 +137,18 @@ func TestOperatorPrecedence(t *testing.T) {
 	test(t, "a + b * c;", "(a + (b * c));", 1)
 	test(t, "a + (b + c) + d;", "((a + (b + c)) + d);", 1)
 	test(t, "(a + b) * c;", "((a + b) * c);", 1)
+	test(t, "a + b % c;", "(a + (b % c));", 1)
+	test(t, "a % b * c;", "((a % b) * c);", 1)
+	test(t, "a ** b ** c;", "(a ** (b ** c));", 1)
+	test(t, "a * b ** c;", "(a * (b ** c));", 1)
+	test(t, "a ** b * c;", "((a ** b) * c);", 1)
+	test(t, "-a ** b;", "(-(a ** b));", 1)
+	test(t, "a ** -b;", "(a ** (-b));", 1)
+	test(t, "a ** b[i];", "(a ** b[i]);", 1)
+	test(t, "a | b ~| c;", "((a | b) ~| c);", 1)
+	test(t, "a ~| b ^ c;", "(a ~| (b ^ c));", 1)
+	test(t, "a ~^ b & c;", "(a ~^ (b & c));", 1)
+	test(t, "a ~& b << c;", "(a ~& (b << c));", 1)
 }
 
 func TestExpressionGroupStatements(t *testing.T) {
@@ -75,7 +161,7 @@ func TestBlockStatements(t *testing.T) {
 	test(t, "{ 1; }", "{ 1; }", 1)
 	test(t, "{ -1; -2; }", "{ (-1);(-2); }", 1)
 	test(t, "{ 0; 1; 2; }", "{ 0;1;2; }", 1)
-	test(t, "{ -0; --1; !false; }", "{ (-0);(-(-1));(!false); }", 1)
+	test(t, "{ -0; - -1; !false; }", "{ (-0);(-(-1));(!false); }", 1)
 }
 
 func TestIfStatements(t *testing.T) {
@@ -87,6 +173,28 @@ func TestIfStatements(t *testing.T) {
 	test(t, "if a { return b; } else if b { return c; } else { return d; }", "if a { return b; } else if b { return c; } else { return d; }", 1)
 }
 
+func TestWhileStatements(t *testing.T) {
+	test(t, "while a < 10 { a = a + 1; }", "while (a < 10) { a = (a + 1); }", 1)
+	test(t, "while true { break; }", "while true { break; }", 1)
+	test(t, "while true { continue; } return 1;", "while true { continue; }return 1;", 2)
+	test(t, "while a { while b { break; } continue; }", "while a { while b { break; }continue; }", 1)
+}
+
+func TestForStatements(t *testing.T) {
+	test(t, "for let i = 0; i < 10; i = i + 1 { }", "for let i = 0; (i < 10); i = (i + 1) {  }", 1)
+	test(t, "for i = 0; i < 10; i = i + 1 { }", "for i = 0; (i < 10); i = (i + 1) {  }", 1)
+	test(t, "for ; i < 10; { }", "for ; (i < 10); {  }", 1)
+	test(t, "for ;; { break; }", "for ; ; { break; }", 1)
+	testErrors(t, "for let i = 0; i < 10; i = i + 1 { continue; }", 0)
+	testErrors(t, "for let i = 0 { }", 1)
+}
+
+func TestBreakContinueOutsideLoop(t *testing.T) {
+	testError(t, "break;", "1:1: error[P0006]: Break outside of a loop.")
+	testError(t, "if a { continue; }", "1:8: error[P0006]: Continue outside of a loop.")
+	testError(t, "while a { fn f() { break; } }", "1:20: error[P0006]: Break outside of a loop.")
+}
+
 // :>, <:, =>, -> >>=, =<<, |>, <|, ~>, <~, +>, <+, ::, :, #, ?:, (| |), {| |}, |{  }|, <>, ><, <|>, <+>, <->, <=>, ?, ++, --, @,
 
 func TestFunDefn(t *testing.T) {
@@ -95,20 +203,182 @@ func TestFunDefn(t *testing.T) {
 	test(t, "fn baz(a, b) {}", "fn baz(a, b) {  }", 1)
 }
 
-// func TestFunLiterals(t *testing.T) {
-// 	test(t, "fn () {};", "fun () {  };", 1)
-// 	test(t, "fn (a) {};", "fun (a) {  };", 1)
-// 	test(t, "fn (a, b) {};", "fun (a, b) {  };", 1)
-// }
+func TestFunLiterals(t *testing.T) {
+	test(t, "fn () {};", "fn() {  };", 1)
+	test(t, "fn (a) {};", "fn(a) {  };", 1)
+	test(t, "fn (a, b) {};", "fn(a, b) {  };", 1)
+	test(t, "let f = fn(a) { return fn(b) { return a + b; }; };", "let f = fn(a) { return fn(b) { return (a + b); }; };", 1)
+	test(t, "while true { fn() { break; }; }", "while true { fn() { <bad statement>; }; }", 1)
+}
+
+func TestArrays(t *testing.T) {
+	test(t, "[];", "[];", 1)
+	test(t, "[1, 2 + 3, a];", "[1, (2 + 3), a];", 1)
+	test(t, "a[1];", "a[1];", 1)
+	test(t, "a[i + 1][j];", "a[(i + 1)][j];", 1)
+	test(t, "-a[0];", "(-a[0]);", 1)
+	test(t, "f(a)[0];", "f(a)[0];", 1)
+	test(t, "[[1], [2, 3]][1][0];", "[[1], [2, 3]][1][0];", 1)
+	test(t, "a[0] = 1;", "a[0] = 1;", 1)
+	test(t, "a[0][1] = b[2];", "a[0][1] = b[2];", 1)
+	testError(t, "a[0;", "1:4: error[P0001]: Expecting []] but got [;].")
+	testError(t, "[1] = 1;", "1:5: error[P0005]: Cannot assign to [[1]].")
+}
+
+func TestStructs(t *testing.T) {
+	test(t, "struct Point { x, y }", "struct Point { x, y }", 1)
+	test(t, "struct Point { x, y, } let a = 1;", "struct Point { x, y }let a = 1;", 2)
+	test(t, "struct Empty {}", "struct Empty {  }", 1)
+	test(t, "Point { x: 1, y: a + 2 };", "Point { x: 1, y: (a + 2) };", 1)
+	test(t, "Empty {};", "Empty {  };", 1)
+	test(t, "p.x;", "p.x;", 1)
+	test(t, "-p.x * q.y;", "((-p.x) * q.y);", 1)
+	test(t, "a[0].p.x;", "a[0].p.x;", 1)
+	test(t, "f(p).x;", "f(p).x;", 1)
+	test(t, "Point { x: 1, y: 2 }.x;", "Point { x: 1, y: 2 }.x;", 1)
+	test(t, "p.x = q.y;", "p.x = q.y;", 1)
+	test(t, "a[0].p.x = 1;", "a[0].p.x = 1;", 1)
+	test(t, "if p { } while q.b { }", "if p {  }while q.b {  }", 2)
+	test(t, "if (Point { x: 1 }).x == 1 { }", "if (Point { x: 1 }.x == 1) {  }", 1)
+	test(t, "for let p = q; p.x < 1; p.x = f(Point { x: 1 }) { }", "for let p = q; (p.x < 1); p.x = f(Point { x: 1 }) {  }", 1)
+	testError(t, "f().x = 1;", "1:7: error[P0005]: Cannot assign to [f().x].")
+	testError(t, "Point { x 1 };", "1:11: error[P0001]: Expecting [:] but got [1].")
+	testError(t, "struct Point { x y }", "1:18: error[P0001]: Expecting [,] but got [y].")
+}
+
+func TestEnums(t *testing.T) {
+	test(t, "enum Shape { Circle(r), Rect(w, h), Empty }", "enum Shape { Circle(r), Rect(w, h), Empty }", 1)
+	test(t, "enum E { A, } let a = 1;", "enum E { A }let a = 1;", 2)
+	test(t, "Circle(1);", "Circle(1);", 1)
+	test(t, "match s { Circle(r) => r * r, Rect(w, h) => w * h, _ => 0 };", "match s { Circle(r) => (r * r), Rect(w, h) => (w * h), _ => 0 };", 1)
+	test(t, "match s { Empty => 0, };", "match s { Empty => 0 };", 1)
+	test(t, "return 1 + match s { A => P { x: 1 } }.x;", "return (1 + match s { A => P { x: 1 } }.x);", 1)
+	test(t, "match match s { A => B } { B => 1 };", "match match s { A => B } { B => 1 };", 1)
+	testError(t, "match s { A -> 1 };", "1:13: error[P0001]: Expecting [=>] but got [->].")
+	testError(t, "match s { A(1) => 1 };", "1:13: error[P0001]: Expecting [ID] but got [1].")
+	testError(t, "enum E { A B }", "1:12: error[P0001]: Expecting [,] but got [B].")
+}
+
+func TestImports(t *testing.T) {
+	test(t, `import "math/bits"; bits.count(1);`, `import "math/bits";bits.count(1);`, 2)
+	test(t, `pub fn f() { } pub struct P { x } pub enum E { A }`, `pub fn f() {  }pub struct P { x }pub enum E { A }`, 3)
+	test(t, "fn f(p: geo.Point) -> [geo.Shape] { }", "fn f(p: geo.Point) -> [geo.Shape] {  }", 1)
+	testError(t, "import bits;", "1:8: error[P0001]: Expecting [STRING] but got [bits].")
+	testError(t, "pub let a = 1;", "1:5: error[P0008]: Expecting a definition after [pub] but got [let].")
+	testError(t, "let p: geo. = 1;", "1:13: error[P0001]: Expecting [ID] but got [=].")
+	test(t, "geo.P { x: 1 };", "geo.P { x: 1 };", 1)
+	test(t, "match e { geo.A(x) => x, geo.B => 0, _ => 1 };", "match e { geo.A(x) => x, geo.B => 0, _ => 1 };", 1)
+	test(t, "if geo.a { }", "if geo.a {  }", 1)
+	testSpan(t, "geo.P { x: 1 };", "1:1-1:15")
+	testSpan(t, `import "math/bits";`, "1:1-1:19")
+	testSpan(t, "pub fn f() { }", "1:1-1:15")
+	testSpan(t, "pub struct P { x }", "1:1-1:19")
+	testSpan(t, "pub enum E { A }", "1:1-1:17")
+}
+
+func TestExterns(t *testing.T) {
+	test(t, "extern fn putchar(c: i32) -> i32;", "extern fn putchar(c: i32) -> i32;", 1)
+	test(t, "extern fn printf(format: string, ...) -> i32; extern fn f(...);", "extern fn printf(format: string, ...) -> i32;extern fn f(...);", 2)
+	test(t, "pub extern fn abort(); pub export fn g(x: i32) -> i32 { return x; }", "pub extern fn abort();pub export fn g(x: i32) -> i32 { return x; }", 2)
+	test(t, "export fn f() { }", "export fn f() {  }", 1)
+	testError(t, "extern fn f(c: i32)", "1:20: error[P0001]: Expecting [;] but got [end of file].")
+	testError(t, "extern fn f(..., c: i32);", "1:16: error[P0001]: Expecting [)] but got [,].")
+	testError(t, "extern fn f(c: i32) { }", "1:21: error[P0001]: Expecting [;] but got [{].")
+	testError(t, "export struct P { x }", "1:8: error[P0001]: Expecting [FUN] but got [struct].")
+	testSpan(t, "extern fn putchar(c: i32) -> i32;", "1:1-1:33")
+	testSpan(t, "extern fn f();", "1:1-1:14")
+	testSpan(t, "export fn f() { }", "1:1-1:18")
+	testSpan(t, "pub extern fn f();", "1:1-1:18")
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	test(t, "let a: u8 = 1;", "let a: u8 = 1;", 1)
+	test(t, "let a: [[i32]] = [];", "let a: [[i32]] = [];", 1)
+	test(t, "fn f(a: u8, b) -> u16 { return a; }", "fn f(a: u8, b) -> u16 { return a; }", 1)
+	test(t, "fn f(g: fn(u8, i8) -> bool) { }", "fn f(g: fn(u8, i8) -> bool) {  }", 1)
+	test(t, "fn(a: i32) -> i32 { return a; };", "fn(a: i32) -> i32 { return a; };", 1)
+	test(t, "struct P { x: u8, y }", "struct P { x: u8, y }", 1)
+	test(t, "enum E { A(x: u8, y: P), B }", "enum E { A(x: u8, y: P), B }", 1)
+	test(t, "u8(1) + i32(a);", "(u8(1) + i32(a));", 1)
+	testError(t, "let a: = 1;", "1:8: error[P0001]: Expecting [ID] but got [=].")
+	testError(t, "fn f() -> { }", "1:11: error[P0001]: Expecting [ID] but got [{].")
+}
 
 func TestFunCall(t *testing.T) {
 	test(t, "foo();", "foo();", 1)
 	test(t, "foo(a);", "foo(a);", 1)
 	test(t, "foo(a, b);", "foo(a, b);", 1)
 
-	// test(t, "fn () { return 0; }();", "fun () { return 0; }();", 1)
-	// test(t, "fn (a) { return a + 1; }(1);", "fun (a) { return (a + 1); }(1);", 1)
-	// test(t, "fn (a, b) { return a + b; }(1, 2);", "fun (a, b) { return (a + b); }(1, 2);", 1)
+	test(t, "foo(a)(b);", "foo(a)(b);", 1)
+	test(t, "fn () { return 0; }();", "fn() { return 0; }();", 1)
+	test(t, "fn (a) { return a + 1; }(1);", "fn(a) { return (a + 1); }(1);", 1)
+	test(t, "fn (a, b) { return a + b; }(1, 2);", "fn(a, b) { return (a + b); }(1, 2);", 1)
+}
+
+func TestOptionalTerminators(t *testing.T) {
+	test(t, "fn foo() {} fn bar() {}", "fn foo() {  }fn bar() {  }", 2)
+	test(t, "if a { return b; } return c;", "if a { return b; }return c;", 2)
+	test(t, "{ } { };", "{  }{  }", 2)
+	testErrors(t, "fn foo() {} fn bar() {}", 0)
+	testErrors(t, "if a { return b; } else { return c; } return d;", 0)
+	testErrors(t, "let a = 1 let b = 2;", 1)
+}
+
+func TestErrorPositions(t *testing.T) {
+	testError(t, "let a = 1 let b = 2;", "1:11: error[P0001]: Expecting [;] but got [let].")
+	testError(t, "return 1 +;", "1:11: error[P0002]: Expecting an expression but got [;].")
+	testError(t, "fn foo(", "1:8: error[P0001]: Expecting [ID] but got [end of file].")
+}
+
+func TestErrorRecovery(t *testing.T) {
+	// A missing semicolon is reported once and the statement is kept.
+	testErrors(t, "let a = 1 let b = 2; let c = 3;", 1)
+	test(t, "let a = 1 let b = 2;", "let a = 1;let b = 2;", 2)
+	// An unknown prefix token is reported once per statement.
+	testErrors(t, "let a = ) + ) * ); let b = );", 2)
+	test(t, "let a = ); let b = 1;", "<bad statement>;let b = 1;", 2)
+	// Recovery stops at a closing brace.
+	test(t, "fn f() { let a = ; return a; } f();", "fn f() { <bad statement>;return a; }f();", 2)
+	test(t, "if a { 1 + } return 2;", "if a { <bad statement>; }return 2;", 2)
+	// Recovery stops at a statement keyword.
+	test(t, "1 + + fn f() { }", "<bad statement>;fn f() {  }", 2)
+	// Stray closing braces do not stall the parser.
+	testErrors(t, "} } let a = 1;", 2)
+	// Illegal characters are reported by the lexer only.
+	testErrors(t, "let a = 1 # 2;", 1)
+}
+
+func TestTooManyErrors(t *testing.T) {
+	input := strings.Repeat("let = 1;", 20)
+	lexer := lexer.NewLexer(input)
+	parser := parser.NewParser(lexer)
+	parser.Parse()
+	errs := parser.Errors()
+	if len(errs) != 11 {
+		t.Fatalf("Expected [11] errors but got [%d].", len(errs))
+	}
+	if errs[10].Code != "P0004" {
+		t.Errorf("Expected last error to be [P0004] but got [%s].", errs[10].Code)
+	}
+}
+
+func TestNodeSpans(t *testing.T) {
+	testSpan(t, "let a = 1 + 2;", "1:1-1:14")
+	testSpan(t, "a = f(1,\n  2);", "1:1-2:5")
+	testSpan(t, "f(x)", "1:1-1:5")
+	testSpan(t, "-a * b", "1:1-1:7")
+	testSpan(t, "{\n  a;\n}", "1:1-3:2")
+	testSpan(t, "if a { b; } else { c; }", "1:1-1:24")
+	testSpan(t, "fn f(a) {\n  return a;\n}", "1:1-3:2")
+	testSpan(t, "struct P {\n  x\n}", "1:1-3:2")
+	testSpan(t, "P { x: 1 }.x", "1:1-1:13")
+	testSpan(t, "let a = ); let b = 1;", "1:1-1:11")
+	testSpan(t, "return (x[0]);", "1:1-1:14")
+	testSpan(t, "let a = (1 + 2);", "1:1-1:16")
+	testSpan(t, "-(3)", "1:1-1:5")
+	testSpan(t, "(a + b) * c", "1:1-1:12")
+	testSpan(t, "a * ((b))", "1:1-1:10")
+	testSpan(t, "(f)(1)", "1:1-1:7")
 }
 
 // TODO: Test error cases.
@@ -127,3 +397,54 @@ func test(t *testing.T, input string, expected string, n int) {
 		t.Errorf("Expected [%s] but got [%s].", expected, actual)
 	}
 }
+
+// testValue checks the value of the string literal in the expression
+// statement.
+func testValue(t *testing.T, input string, expected string) {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	prog := p.Parse()
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", p.Errors())
+	}
+	str := prog.Statements[0].(*parser.ExpressionStatement).Value.(*parser.String)
+	if str.Value != expected {
+		t.Errorf("Expected [%q] but got [%q].", expected, str.Value)
+	}
+}
+
+func testErrors(t *testing.T, input string, n int) {
+	t.Helper()
+	lexer := lexer.NewLexer(input)
+	parser := parser.NewParser(lexer)
+	parser.Parse()
+	m := len(parser.Errors())
+	if m != n {
+		t.Errorf("Expected [%d] errors but got [%d]: %v.", n, m, parser.Errors())
+	}
+}
+
+func testError(t *testing.T, input string, expected string) {
+	t.Helper()
+	lexer := lexer.NewLexer(input)
+	parser := parser.NewParser(lexer)
+	parser.Parse()
+	if parser.HasNoErrors() {
+		t.Fatalf("Expected error [%s].", expected)
+	}
+	if actual := parser.Errors()[0].String(); actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
+
+// testSpan checks the source range covered by the first statement.
+func testSpan(t *testing.T, input string, expected string) {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	n := p.Parse().Statements[0]
+	start, end := n.Pos(), n.End()
+	actual := fmt.Sprintf("%d:%d-%d:%d", start.Line, start.Col, end.Line, end.Col)
+	if actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
@@ -1,109 +1,254 @@
 package parser_test
 
-// func TestPrintParseTreeIdentifier(t *testing.T) {
-// 	n := &Identifier{Value: "x"}
-// 	expected := `x`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeInteger(t *testing.T) {
-// 	n := &Integer{Value: 42}
-// 	expected := `42`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeBoolean(t *testing.T) {
-// 	n := &Boolean{Value: true}
-// 	expected := `true`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreePrefix(t *testing.T) {
-// 	val := &Integer{Value: 42}
-// 	n := &PrefixExpression{Operator: "-", Value: val}
-// 	expected := `PREFIX(-)
-//  └ 42`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeInfix(t *testing.T) {
-// 	left := &Integer{Value: 42}
-// 	right := &Integer{Value: 43}
-// 	n := &BinaryExpression{Operator: "+", Left: left, Right: right}
-// 	expected := `INFIX(+)
-//  ├ 42
-//  └ 43`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeFunctionLiteral(t *testing.T) {
-//   params := []*Identifier{}
-//   params = append(params, &Identifier{Value: "a"})
-//   params = append(params, &Identifier{Value: "b"})
-
-//   stmts := []Statement{}
-//   val := &Integer{Value: 42}
-//   stmt := &ReturnStatement{Value: val}
-//   stmts = append(stmts, stmt)
-//   block := &BlockStatement{Statements: stmts}
-//   n := &FunctionLiteral{Params: params, Body: block}
-//   expected := `FUN[a b]
-//  └ BLOCK
-//     └ RETURN
-//        └ 42`
-//   testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeReturn(t *testing.T) {
-// 	val := &Integer{Value: 42}
-// 	n := &ReturnStatement{Value: val}
-// 	expected := `RETURN
-//  └ 42`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeLet(t *testing.T) {
-// 	name := &Identifier{Value: "x"}
-// 	val := &Integer{Value: 42}
-// 	n := &LetStatement{Name: name, Value: val}
-// 	expected := `LET x
-//  └ 42`
-// 	testParseTree(t, n, expected)
-// }
-
-// func TestPrintParseTreeBlock(t *testing.T) {
-// 	stmts := []Statement{}
-
-// 	name1 := &Identifier{Value: "x"}
-// 	val1 := &Integer{Value: 42}
-// 	stmt1 := &LetStatement{Name: name1, Value: val1}
-// 	stmts = append(stmts, stmt1)
-
-// 	name2 := &Identifier{Value: "y"}
-// 	val2 := &Integer{Value: 43}
-// 	stmt2 := &LetStatement{Name: name2, Value: val2}
-// 	stmts = append(stmts, stmt2)
-
-// 	val3 := &BinaryExpression{Operator: "+", Left: name1, Right: name2}
-// 	stmt3 := &ReturnStatement{Value: val3}
-// 	stmts = append(stmts, stmt3)
-
-// 	n := &BlockStatement{Statements: stmts}
-// 	expected := `BLOCK
-//  ├ LET x
-//     └ 42
-//  ├ LET y
-//     └ 43
-//  └ RETURN
-//     └ INFIX(+)
-//        ├ x
-//        └ y`
-// 	testParseTree(t, n, expected)
-// }
-
-// func testParseTree(t *testing.T, n Node, expected string) {
-// 	actual := PrintParseTree(n)
-// 	if actual != expected {
-// 		t.Errorf("Expected [\n%s\n] but got [\n%s\n].", expected, actual)
-// 	}
-// }
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mhoertnagl/donkey/lexer"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+func TestPrintParseTreeIdentifier(t *testing.T) {
+	n := &parser.Identifier{Value: "x"}
+	expected := `x`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeInteger(t *testing.T) {
+	n := &parser.Integer{Value: big.NewInt(42)}
+	expected := `42`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeFloat(t *testing.T) {
+	n := &parser.Float{Value: 2.5}
+	expected := `2.5`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeBoolean(t *testing.T) {
+	n := &parser.Boolean{Value: true}
+	expected := `true`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeString(t *testing.T) {
+	n := parser.NewStringLiteral(token.Token{Typ: token.STR, Literal: `"a\tb"`})
+	expected := `"a\tb"`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreePrefix(t *testing.T) {
+	val := &parser.Integer{Value: big.NewInt(42)}
+	n := &parser.PrefixExpression{Operator: "-", Value: val}
+	expected := `PREFIX(-)
+ └ 42`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeInfix(t *testing.T) {
+	left := &parser.Integer{Value: big.NewInt(42)}
+	right := &parser.Integer{Value: big.NewInt(43)}
+	n := &parser.BinaryExpression{Operator: "+", Left: left, Right: right}
+	expected := `INFIX(+)
+ ├ 42
+ └ 43`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeFunctionLiteral(t *testing.T) {
+	params := []*parser.Identifier{{Value: "a"}, {Value: "b"}}
+	val := &parser.Integer{Value: big.NewInt(42)}
+	stmts := []parser.Statement{&parser.ReturnStatement{Value: val}}
+	block := &parser.BlockStatement{Statements: stmts}
+	n := &parser.FunctionLiteral{Params: params, Body: block}
+	expected := `FUN[a b]
+ └ BLOCK
+    └ RETURN
+       └ 42`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeArray(t *testing.T) {
+	testParseTreeOf(t, "[1, a[2]];", `ARRAY
+ ├ 1
+ └ INDEX
+    ├ a
+    └ 2
+`)
+}
+
+func TestPrintParseTreeStruct(t *testing.T) {
+	testParseTreeOf(t, "struct Point { x, y } p.x = Point { x: 1, y: q.y };", `STRUCT Point[x y]
+ASSIGN
+ ├ FIELD(x)
+    └ p
+ └ NEW Point[x y]
+    ├ 1
+    └ FIELD(y)
+       └ q
+`)
+}
+
+func TestPrintParseTreeImport(t *testing.T) {
+	testParseTreeOf(t, `import "geo"; pub struct P { x } pub fn f() { return geo.g(); }`, `IMPORT "geo"
+PUB STRUCT P[x]
+PUB FUN[]
+ └ BLOCK
+    └ RETURN
+       └ CALL
+          └ FIELD(g)
+             └ geo
+`)
+}
+
+func TestPrintParseTreeExtern(t *testing.T) {
+	testParseTreeOf(t, `pub extern fn printf(format: string, ...) -> i32; export fn f() { }`, `PUB EXTERN FUN[format: string ...] -> i32
+EXPORT FUN[]
+ └ BLOCK
+`)
+}
+
+func TestPrintParseTreeEnum(t *testing.T) {
+	testParseTreeOf(t, "enum Shape { Circle(r), Rect(w, h), Empty } match s { Circle(r) => r, _ => 0 };", `ENUM Shape[Circle(r) Rect(w, h) Empty]
+MATCH
+ ├ s
+ ├ CASE Circle(r)
+    └ r
+ └ CASE _
+    └ 0
+`)
+}
+
+func TestPrintParseTreeReturn(t *testing.T) {
+	val := &parser.Integer{Value: big.NewInt(42)}
+	n := &parser.ReturnStatement{Value: val}
+	expected := `RETURN
+ └ 42`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeLet(t *testing.T) {
+	name := &parser.Identifier{Value: "x"}
+	val := &parser.Integer{Value: big.NewInt(42)}
+	n := &parser.LetStatement{Name: name, Value: val}
+	expected := `LET x
+ └ 42`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeBlock(t *testing.T) {
+	stmts := []parser.Statement{}
+
+	name1 := &parser.Identifier{Value: "x"}
+	val1 := &parser.Integer{Value: big.NewInt(42)}
+	stmt1 := &parser.LetStatement{Name: name1, Value: val1}
+	stmts = append(stmts, stmt1)
+
+	name2 := &parser.Identifier{Value: "y"}
+	val2 := &parser.Integer{Value: big.NewInt(43)}
+	stmt2 := &parser.LetStatement{Name: name2, Value: val2}
+	stmts = append(stmts, stmt2)
+
+	val3 := &parser.BinaryExpression{Operator: "+", Left: name1, Right: name2}
+	stmt3 := &parser.ReturnStatement{Value: val3}
+	stmts = append(stmts, stmt3)
+
+	n := &parser.BlockStatement{Statements: stmts}
+	expected := `BLOCK
+ ├ LET x
+    └ 42
+ ├ LET y
+    └ 43
+ └ RETURN
+    └ INFIX(+)
+       ├ x
+       └ y`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeEmptyBlock(t *testing.T) {
+	n := &parser.BlockStatement{}
+	expected := `BLOCK`
+	testParseTree(t, n, expected)
+}
+
+func TestPrintParseTreeIf(t *testing.T) {
+	testParseTreeOf(t, "if a { return f(1, b); } else { }", `IF
+ ├ a
+ ├ BLOCK
+    └ RETURN
+       └ CALL
+          ├ f
+          ├ 1
+          └ b
+ └ BLOCK
+`)
+}
+
+func TestPrintParseTreeLoops(t *testing.T) {
+	testParseTreeOf(t, "while a { break; } for let i = 0; i < 2; i = i + 1 { continue; }", `WHILE
+ ├ a
+ └ BLOCK
+    └ BREAK
+FOR
+ ├ LET i
+    └ 0
+ ├ INFIX(<)
+    ├ i
+    └ 2
+ ├ ASSIGN
+    ├ i
+    └ INFIX(+)
+       ├ i
+       └ 1
+ └ BLOCK
+    └ CONTINUE
+`)
+}
+
+func TestPrintParseTreeCompoundAssign(t *testing.T) {
+	testParseTreeOf(t, "a[0] <<= 2;", `ASSIGN(<<=)
+ ├ INDEX
+    ├ a
+    └ 0
+ └ 2
+`)
+}
+
+func TestPrintParseTreeIncrement(t *testing.T) {
+	testParseTreeOf(t, "p.x++;", `ASSIGN(++)
+ ├ FIELD(x)
+    └ p
+ └ 1
+`)
+}
+
+func TestPrintParseTreeTypeAnnotations(t *testing.T) {
+	testParseTreeOf(t, "let a: u8 = 1; fn f(a: u8, b) -> [i8] { }", `LET a: u8
+ └ 1
+FUN[a: u8 b] -> [i8]
+ └ BLOCK
+`)
+}
+
+func TestPrintParseTreeBad(t *testing.T) {
+	testParseTreeOf(t, "let a = ; return 1 + ;", `BAD
+BAD
+`)
+}
+
+func testParseTree(t *testing.T, n parser.Node, expected string) {
+	t.Helper()
+	actual := parser.PrintParseTree(n)
+	if actual != expected {
+		t.Errorf("Expected [\n%s\n] but got [\n%s\n].", expected, actual)
+	}
+}
+
+func testParseTreeOf(t *testing.T, input string, expected string) {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	testParseTree(t, p.Parse(), expected)
+}
@@ -0,0 +1,112 @@
+package parser
+
+// Inspect traverses the syntax tree in depth-first order. It calls f for
+// every node. If f returns false, the children of the node are skipped.
+// Missing optional nodes such as the else branch of an if statement are
+// not visited.
+func Inspect(n Node, f func(Node) bool) {
+	if n == nil || !f(n) {
+		return
+	}
+	switch n := n.(type) {
+	case *Program:
+		inspectAll(n.Statements, f)
+	case *ImportStatement:
+		Inspect(n.Path, f)
+	case *LetStatement:
+		Inspect(n.Name, f)
+		Inspect(n.Type, f)
+		Inspect(n.Value, f)
+	case *AssignStatement:
+		Inspect(n.Target, f)
+		Inspect(n.Value, f)
+	case *FunDefStatement:
+		Inspect(n.Name, f)
+		inspectAll(n.Params, f)
+		inspectAll(n.ParamTypes, f)
+		Inspect(n.Result, f)
+		if n.Body != nil {
+			Inspect(n.Body, f)
+		}
+	case *StructDefStatement:
+		Inspect(n.Name, f)
+		inspectAll(n.Fields, f)
+		inspectAll(n.FieldTypes, f)
+	case *EnumDefStatement:
+		Inspect(n.Name, f)
+		for _, v := range n.Variants {
+			Inspect(v.Name, f)
+			inspectAll(v.Fields, f)
+			inspectAll(v.FieldTypes, f)
+		}
+	case *ReturnStatement:
+		Inspect(n.Value, f)
+	case *IfStatement:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+		Inspect(n.Alternative, f)
+	case *WhileStatement:
+		Inspect(n.Condition, f)
+		Inspect(n.Body, f)
+	case *ForStatement:
+		Inspect(n.Init, f)
+		Inspect(n.Condition, f)
+		Inspect(n.Post, f)
+		Inspect(n.Body, f)
+	case *BlockStatement:
+		inspectAll(n.Statements, f)
+	case *ExpressionStatement:
+		Inspect(n.Value, f)
+	case *PrefixExpression:
+		Inspect(n.Value, f)
+	case *BinaryExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+	case *FunctionLiteral:
+		inspectAll(n.Params, f)
+		inspectAll(n.ParamTypes, f)
+		Inspect(n.Result, f)
+		Inspect(n.Body, f)
+	case *ArrayLiteral:
+		inspectAll(n.Elements, f)
+	case *IndexExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Index, f)
+	case *StructLiteral:
+		if n.Module != nil {
+			Inspect(n.Module, f)
+		}
+		Inspect(n.Name, f)
+		for i, field := range n.Fields {
+			Inspect(field, f)
+			Inspect(n.Values[i], f)
+		}
+	case *SelectorExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Field, f)
+	case *MatchExpression:
+		Inspect(n.Value, f)
+		inspectAll(n.Arms, f)
+	case *MatchArm:
+		if n.Module != nil {
+			Inspect(n.Module, f)
+		}
+		Inspect(n.Pattern, f)
+		inspectAll(n.Bindings, f)
+		Inspect(n.Body, f)
+	case *CallExpression:
+		Inspect(n.Function, f)
+		inspectAll(n.Args, f)
+	case *ArrayType:
+		Inspect(n.Elem, f)
+	case *FuncType:
+		inspectAll(n.Params, f)
+		Inspect(n.Result, f)
+	}
+}
+
+func inspectAll[T Node](ns []T, f func(Node) bool) {
+	for _, n := range ns {
+		Inspect(n, f)
+	}
+}
@@ -1,4 +1,3 @@
-// TODO: Should be rpel read-parse-evaluate-loop
 package repl
 
 import (
@@ -8,14 +7,20 @@ import (
 
 	// "github.com/mhoertnagl/donkey/aegis"
 	"github.com/mhoertnagl/donkey/console"
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/eval"
 	"github.com/mhoertnagl/donkey/lexer"
 	"github.com/mhoertnagl/donkey/parser"
 	"github.com/mhoertnagl/donkey/token"
 )
 
+// Start runs the read-parse-evaluate loop. Definitions persist between
+// lines. The console arguments may restrict the loop to lexing or parsing.
 func Start(in io.Reader, out io.Writer, cargs console.Args) {
 	// aegis.FsetTextColor(out, aegis.Color(245, 245, 255))
 	s := bufio.NewScanner(in)
+	evaluator := eval.NewEvaluator()
+	evaluator.SetOutput(out)
 	for {
 		fmt.Fprintf(out, ">> ")
 		if ok := s.Scan(); !ok {
@@ -39,15 +44,19 @@ func Start(in io.Reader, out io.Writer, cargs console.Args) {
 
 		parser := parser.NewParser(lexer)
 		ast := parser.Parse()
+		if parser.HasErrors() {
+			renderer := diag.NewRenderer()
+			renderer.AddSource("", input)
+			renderer.RenderAll(out, parser.Errors())
+			continue
+		}
 		if cargs.ParseOnly {
-			if parser.HasErrors() {
-				for _, err := range parser.Errors() {
-					fmt.Fprintf(out, "%s\n", err)
-				}
-			} else {
-				fmt.Fprintf(out, "%s\n", ast)
-			}
+			fmt.Fprintf(out, "%s\n", ast)
 			continue
 		}
+
+		if res := evaluator.Eval(ast); res != nil {
+			fmt.Fprintf(out, "%s\n", res.Inspect())
+		}
 	}
 }
@@ -0,0 +1,714 @@
+package resolve
+
+import (
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/parser"
+)
+
+type ObjectKind int
+
+const (
+	Var ObjectKind = iota
+	Param
+	Func
+	Builtin
+	Struct
+	Enum
+	Variant
+	Type
+	Module
+)
+
+var kindNames = [...]string{
+	Var:     "variable",
+	Param:   "parameter",
+	Func:    "function",
+	Builtin: "builtin function",
+	Struct:  "struct",
+	Enum:    "enum",
+	Variant: "variant",
+	Type:    "type",
+	Module:  "module",
+}
+
+func (k ObjectKind) String() string {
+	return kindNames[k]
+}
+
+// Object is a named entity of the program.
+type Object struct {
+	Kind ObjectKind
+	Name string
+	// Decl is the name of the object in its declaration.
+	Decl *parser.Identifier
+	// Fun is the definition of the function if the object is a function.
+	Fun *parser.FunDefStatement
+	// Struct is the definition of the struct if the object is a struct.
+	Struct *parser.StructDefStatement
+	// Enum is the definition of the enum if the object is an enum or a
+	// variant. Variant is the definition of the variant.
+	Enum    *parser.EnumDefStatement
+	Variant *parser.Variant
+	// Import is the import statement of the module if the object is an
+	// imported module. Members holds the top-level objects of the module.
+	Import  *parser.ImportStatement
+	Members map[string]*Object
+	// Captured is true iff the variable or parameter is used by a function
+	// literal other than the one it is declared in.
+	Captured bool
+}
+
+// IsFunc returns true iff the object is a function or a builtin function.
+func (o *Object) IsFunc() bool {
+	return o.Kind == Func || o.Kind == Builtin
+}
+
+// IsPub returns true iff the object is a public function, struct or enum
+// or a variant of a public enum.
+func (o *Object) IsPub() bool {
+	switch o.Kind {
+	case Func:
+		return o.Fun.Pub
+	case Struct:
+		return o.Struct.Pub
+	case Enum, Variant:
+		return o.Enum.Pub
+	}
+	return false
+}
+
+// Universe holds the builtin functions and the predeclared types. They are
+// visible everywhere but may be shadowed by declarations of the program.
+var Universe = map[string]*Object{
+	"print":   {Kind: Builtin, Name: "print"},
+	"println": {Kind: Builtin, Name: "println"},
+	"len":     {Kind: Builtin, Name: "len"},
+	"int":     {Kind: Type, Name: "int"},
+	"bool":    {Kind: Type, Name: "bool"},
+	"string":  {Kind: Type, Name: "string"},
+	"i8":      {Kind: Type, Name: "i8"},
+	"i16":     {Kind: Type, Name: "i16"},
+	"i32":     {Kind: Type, Name: "i32"},
+	"i64":     {Kind: Type, Name: "i64"},
+	"i128":    {Kind: Type, Name: "i128"},
+	"u8":      {Kind: Type, Name: "u8"},
+	"u16":     {Kind: Type, Name: "u16"},
+	"u32":     {Kind: Type, Name: "u32"},
+	"u64":     {Kind: Type, Name: "u64"},
+	"u128":    {Kind: Type, Name: "u128"},
+	"f32":     {Kind: Type, Name: "f32"},
+	"f64":     {Kind: Type, Name: "f64"},
+}
+
+// Info holds the results of name resolution.
+type Info struct {
+	// Defs maps every declared name to its object.
+	Defs map[*parser.Identifier]*Object
+	// Uses maps every identifier that refers to an object to the object.
+	// Undefined identifiers are not part of the map.
+	Uses map[*parser.Identifier]*Object
+	// Captures maps every function literal to the variables and parameters
+	// of enclosing functions it uses in the order of their first use. This
+	// includes the variables used by nested function literals.
+	Captures map[*parser.FunctionLiteral][]*Object
+}
+
+func newInfo() *Info {
+	return &Info{
+		Defs:     make(map[*parser.Identifier]*Object),
+		Uses:     make(map[*parser.Identifier]*Object),
+		Captures: make(map[*parser.FunctionLiteral][]*Object),
+	}
+}
+
+// Qualified returns the name of the member of an imported module the
+// selector refers to such as abs in math.abs. It returns nil if the
+// selector selects the field of a value.
+func (info *Info) Qualified(n *parser.SelectorExpression) *parser.Identifier {
+	if id, ok := n.Left.(*parser.Identifier); ok {
+		if obj := info.Uses[id]; obj != nil && obj.Kind == Module {
+			return n.Field
+		}
+	}
+	return nil
+}
+
+// Name returns the identifier the expression or type consists of. The
+// members of imported modules are names as well. It returns nil for other
+// expressions and types.
+func (info *Info) Name(n parser.Node) *parser.Identifier {
+	switch n := n.(type) {
+	case *parser.Identifier:
+		return n
+	case *parser.SelectorExpression:
+		return info.Qualified(n)
+	}
+	return nil
+}
+
+// ObjectOf returns the object the identifier declares or refers to.
+func (info *Info) ObjectOf(id *parser.Identifier) *Object {
+	if obj, ok := info.Defs[id]; ok {
+		return obj
+	}
+	return info.Uses[id]
+}
+
+type scope map[string]*Object
+
+// Resolver binds every identifier of a program to its declaration.
+//
+// Functions, structs, enums and the variants of enums are visible in the
+// whole block they are defined in, even before their definition. Variables are visible from their let statement
+// to the end of the enclosing block. Function bodies only see the global
+// scope and their parameters. Function literals additionally see the
+// variables of the enclosing blocks and capture the ones they use.
+//
+// Every module has a global scope of its own. Imported modules are
+// declared in the global scope by their name. The public definitions of an
+// imported module are its members.
+type Resolver struct {
+	info   *Info
+	diags  *diag.List
+	scopes []scope
+	// Global scopes of the resolved modules by import path.
+	modules map[string]scope
+	// Enclosing function literals of the current statement and the number
+	// of function literals that enclose the declaration of each object.
+	lits   []*parser.FunctionLiteral
+	levels map[*Object]int
+}
+
+func NewResolver(diags *diag.List) *Resolver {
+	return &Resolver{
+		info:    newInfo(),
+		diags:   diags,
+		scopes:  []scope{},
+		levels:  make(map[*Object]int),
+		modules: make(map[string]scope),
+	}
+}
+
+func (r *Resolver) HasErrors() bool {
+	return r.diags.HasErrors()
+}
+
+func (r *Resolver) Errors() []*diag.Diagnostic {
+	return r.diags.Items()
+}
+
+func (r *Resolver) Resolve(prog *parser.Program) *Info {
+	return r.ResolveModules([]*parser.Program{prog})
+}
+
+// ResolveModules resolves the modules of a program. Every module has to
+// follow the modules it imports.
+func (r *Resolver) ResolveModules(mods []*parser.Program) *Info {
+	for _, mod := range mods {
+		r.pushScope()
+		for _, s := range mod.Statements {
+			if n, ok := s.(*parser.ImportStatement); ok {
+				r.declareModule(n)
+			}
+		}
+		r.stmts(mod.Statements)
+		r.modules[mod.Path] = r.scopes[0]
+		r.popScope()
+	}
+	return r.info
+}
+
+func (r *Resolver) pushScope() {
+	r.scopes = append(r.scopes, scope{})
+}
+
+func (r *Resolver) popScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) declare(kind ObjectKind, id *parser.Identifier) *Object {
+	obj := &Object{Kind: kind, Name: id.Value, Decl: id}
+	r.scopes[len(r.scopes)-1][id.Value] = obj
+	r.info.Defs[id] = obj
+	r.levels[obj] = len(r.lits)
+	return obj
+}
+
+// lookup returns the object with the name and the index of the scope that
+// declares it. The index of builtin functions is -1.
+func (r *Resolver) lookup(name string) (*Object, int) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if obj, ok := r.scopes[i][name]; ok {
+			return obj, i
+		}
+	}
+	return Universe[name], -1
+}
+
+// stmts declares the functions, structs and enums of a block before the
+// statements are resolved.
+func (r *Resolver) stmts(ns []parser.Statement) {
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.FunDefStatement:
+			r.declareFunction(n)
+		case *parser.StructDefStatement:
+			r.declareStruct(n)
+		case *parser.EnumDefStatement:
+			r.declareEnum(n)
+		}
+	}
+	for _, s := range ns {
+		r.stmt(s)
+	}
+}
+
+// declareModule declares the name of the imported module in the global
+// scope.
+func (r *Resolver) declareModule(n *parser.ImportStatement) {
+	members, ok := r.modules[n.Path.Value]
+	if !ok {
+		r.diags.NodeErrorf("R0010", n.Path, "Unknown module [%s].", n.Path.Value)
+		return
+	}
+	name := n.Name()
+	if prev, ok := r.scopes[0][name]; ok {
+		d := r.diags.NodeErrorf("R0002", n.Path, "Module [%s] is already imported.", name)
+		d.AddNote(declLocation(prev), "Previous import of [%s].", name)
+		return
+	}
+	obj := &Object{Kind: Module, Name: name, Import: n, Members: members}
+	r.scopes[0][name] = obj
+	r.levels[obj] = 0
+}
+
+func (r *Resolver) declareFunction(n *parser.FunDefStatement) {
+	if prev := r.defined(n.Name); prev != nil {
+		d := r.diags.TokenErrorf("R0002", n.Name.Token, "Function [%s] is already defined.", n.Name.Value)
+		d.AddNote(declLocation(prev), "Previous definition of [%s].", n.Name.Value)
+		// Keep the first definition. The duplicate is still resolved but
+		// cannot be referred to.
+		r.info.Defs[n.Name] = &Object{Kind: Func, Name: n.Name.Value, Decl: n.Name, Fun: n}
+		return
+	}
+	obj := r.declare(Func, n.Name)
+	obj.Fun = n
+}
+
+func (r *Resolver) declareStruct(n *parser.StructDefStatement) {
+	if prev := r.defined(n.Name); prev != nil {
+		d := r.diags.TokenErrorf("R0002", n.Name.Token, "Struct [%s] is already defined.", n.Name.Value)
+		d.AddNote(declLocation(prev), "Previous definition of [%s].", n.Name.Value)
+		r.info.Defs[n.Name] = &Object{Kind: Struct, Name: n.Name.Value, Decl: n.Name, Struct: n}
+		return
+	}
+	obj := r.declare(Struct, n.Name)
+	obj.Struct = n
+}
+
+// declareEnum declares the enum and its variants.
+func (r *Resolver) declareEnum(n *parser.EnumDefStatement) {
+	if prev := r.defined(n.Name); prev != nil {
+		d := r.diags.TokenErrorf("R0002", n.Name.Token, "Enum [%s] is already defined.", n.Name.Value)
+		d.AddNote(declLocation(prev), "Previous definition of [%s].", n.Name.Value)
+		r.info.Defs[n.Name] = &Object{Kind: Enum, Name: n.Name.Value, Decl: n.Name, Enum: n}
+	} else {
+		obj := r.declare(Enum, n.Name)
+		obj.Enum = n
+	}
+	for _, v := range n.Variants {
+		if prev := r.defined(v.Name); prev != nil {
+			d := r.diags.TokenErrorf("R0002", v.Name.Token, "Variant [%s] is already defined.", v.Name.Value)
+			d.AddNote(declLocation(prev), "Previous definition of [%s].", v.Name.Value)
+			r.info.Defs[v.Name] = &Object{Kind: Variant, Name: v.Name.Value, Decl: v.Name, Enum: n, Variant: v}
+			continue
+		}
+		obj := r.declare(Variant, v.Name)
+		obj.Enum, obj.Variant = n, v
+	}
+}
+
+// defined returns the function, struct, enum or variant with the name of
+// the identifier that has been declared in the current block or nil if
+// there is none.
+func (r *Resolver) defined(id *parser.Identifier) *Object {
+	prev, ok := r.scopes[len(r.scopes)-1][id.Value]
+	if ok && prev.Kind != Var && prev.Kind != Param {
+		return prev
+	}
+	return nil
+}
+
+func (r *Resolver) stmt(n parser.Statement) {
+	switch n := n.(type) {
+	case *parser.ImportStatement:
+		if len(r.scopes) > 1 {
+			r.diags.TokenErrorf("R0013", n.Token, "Imports are only allowed at the top level.")
+		}
+	case *parser.LetStatement:
+		r.typ(n.Type)
+		r.expr(n.Value)
+		r.declare(Var, n.Name)
+	case *parser.AssignStatement:
+		r.assignStmt(n)
+	case *parser.FunDefStatement:
+		r.pub(n.Pub, n.Name)
+		r.linkage(n)
+		r.funDefStmt(n)
+	case *parser.StructDefStatement:
+		r.pub(n.Pub, n.Name)
+		r.structDefStmt(n)
+	case *parser.EnumDefStatement:
+		r.pub(n.Pub, n.Name)
+		r.enumDefStmt(n)
+	case *parser.BlockStatement:
+		r.blockStmt(n)
+	case *parser.IfStatement:
+		r.expr(n.Condition)
+		r.stmt(n.Consequence)
+		if n.Alternative != nil {
+			r.stmt(n.Alternative)
+		}
+	case *parser.WhileStatement:
+		r.expr(n.Condition)
+		r.blockStmt(n.Body)
+	case *parser.ForStatement:
+		r.forStmt(n)
+	case *parser.ReturnStatement:
+		r.expr(n.Value)
+	case *parser.ExpressionStatement:
+		r.expr(n.Value)
+	}
+}
+
+// pub reports public definitions that are not at the top level.
+func (r *Resolver) pub(isPub bool, name *parser.Identifier) {
+	if isPub && len(r.scopes) > 1 {
+		r.diags.TokenErrorf("R0013", name.Token, "Local definition [%s] cannot be public.", name.Value)
+	}
+}
+
+// linkage reports extern and exported functions that are not defined at
+// the top level.
+func (r *Resolver) linkage(n *parser.FunDefStatement) {
+	if len(r.scopes) == 1 {
+		return
+	}
+	switch {
+	case n.Extern:
+		r.diags.TokenErrorf("R0013", n.Name.Token, "Local function [%s] cannot be extern.", n.Name.Value)
+	case n.Export:
+		r.diags.TokenErrorf("R0013", n.Name.Token, "Local function [%s] cannot be exported.", n.Name.Value)
+	}
+}
+
+func (r *Resolver) assignStmt(n *parser.AssignStatement) {
+	r.expr(n.Value)
+	id, ok := n.Target.(*parser.Identifier)
+	if !ok {
+		r.expr(n.Target)
+		return
+	}
+	obj := r.use(id)
+	switch {
+	case obj == nil:
+	case obj.IsFunc():
+		r.diags.TokenErrorf("R0005", id.Token, "Cannot assign to function [%s].", id.Value)
+	case obj.Kind == Struct, obj.Kind == Enum, obj.Kind == Variant, obj.Kind == Type, obj.Kind == Module:
+		r.diags.TokenErrorf("R0005", id.Token, "Cannot assign to %s [%s].", obj.Kind, id.Value)
+	}
+}
+
+func (r *Resolver) funDefStmt(n *parser.FunDefStatement) {
+	// The types of the signature are resolved where the function is
+	// defined.
+	r.types(n.ParamTypes)
+	r.typ(n.Result)
+	// Function bodies only see the global scope.
+	scopes, lits := r.scopes, r.lits
+	r.scopes = []scope{r.scopes[0]}
+	r.lits = nil
+	r.pushScope()
+	for _, param := range n.Params {
+		if prev, ok := r.scopes[1][param.Value]; ok {
+			d := r.diags.TokenErrorf("R0003", param.Token, "Duplicate parameter [%s] in function [%s].", param.Value, n.Name.Value)
+			d.AddNote(locationOf(prev.Decl), "Previous declaration of [%s].", param.Value)
+		}
+		r.declare(Param, param)
+	}
+	// Extern functions have no body.
+	if n.Body != nil {
+		r.blockStmt(n.Body)
+	}
+	r.scopes, r.lits = scopes, lits
+}
+
+func (r *Resolver) structDefStmt(n *parser.StructDefStatement) {
+	r.fields(n.Fields, "struct", n.Name)
+	r.types(n.FieldTypes)
+}
+
+func (r *Resolver) enumDefStmt(n *parser.EnumDefStatement) {
+	for _, v := range n.Variants {
+		r.fields(v.Fields, "variant", v.Name)
+		r.types(v.FieldTypes)
+	}
+}
+
+// typ binds the names of the type annotation. Missing annotations are
+// ignored.
+func (r *Resolver) typ(n parser.TypeExpr) {
+	switch n := n.(type) {
+	case *parser.Identifier:
+		r.isType(n, r.use(n))
+	case *parser.SelectorExpression:
+		if obj := r.use(n.Left.(*parser.Identifier)); obj != nil {
+			r.isType(n.Field, r.member(obj, n.Field))
+		}
+	case *parser.ArrayType:
+		r.typ(n.Elem)
+	case *parser.FuncType:
+		r.types(n.Params)
+		r.typ(n.Result)
+	}
+}
+
+// isType reports an error if the object the name refers to is not a type.
+func (r *Resolver) isType(id *parser.Identifier, obj *Object) {
+	if obj != nil && obj.Kind != Type && obj.Kind != Struct && obj.Kind != Enum {
+		r.diags.TokenErrorf("R0009", id.Token, "[%s] is not a type.", id.Value)
+	}
+}
+
+func (r *Resolver) types(ns []parser.TypeExpr) {
+	for _, n := range ns {
+		r.typ(n)
+	}
+}
+
+// fields reports duplicate fields of a struct or variant.
+func (r *Resolver) fields(ids []*parser.Identifier, kind string, name *parser.Identifier) {
+	fields := map[string]*parser.Identifier{}
+	for _, field := range ids {
+		if prev, ok := fields[field.Value]; ok {
+			d := r.diags.TokenErrorf("R0006", field.Token, "Duplicate field [%s] in %s [%s].", field.Value, kind, name.Value)
+			d.AddNote(locationOf(prev), "Previous declaration of [%s].", field.Value)
+			continue
+		}
+		fields[field.Value] = field
+	}
+}
+
+func (r *Resolver) funLit(n *parser.FunctionLiteral) {
+	r.types(n.ParamTypes)
+	r.typ(n.Result)
+	r.lits = append(r.lits, n)
+	r.info.Captures[n] = []*Object{}
+	r.pushScope()
+	params := r.scopes[len(r.scopes)-1]
+	for _, param := range n.Params {
+		if prev, ok := params[param.Value]; ok {
+			d := r.diags.TokenErrorf("R0003", param.Token, "Duplicate parameter [%s] in anonymous function.", param.Value)
+			d.AddNote(locationOf(prev.Decl), "Previous declaration of [%s].", param.Value)
+		}
+		r.declare(Param, param)
+	}
+	r.blockStmt(n.Body)
+	r.popScope()
+	r.lits = r.lits[:len(r.lits)-1]
+}
+
+func (r *Resolver) blockStmt(n *parser.BlockStatement) {
+	r.pushScope()
+	r.stmts(n.Statements)
+	r.popScope()
+}
+
+func (r *Resolver) forStmt(n *parser.ForStatement) {
+	r.pushScope()
+	if n.Init != nil {
+		r.stmt(n.Init)
+	}
+	if n.Condition != nil {
+		r.expr(n.Condition)
+	}
+	if n.Post != nil {
+		r.stmt(n.Post)
+	}
+	r.blockStmt(n.Body)
+	r.popScope()
+}
+
+func (r *Resolver) expr(n parser.Expression) {
+	switch n := n.(type) {
+	case *parser.Identifier:
+		r.use(n)
+	case *parser.CallExpression:
+		r.callExpr(n)
+	case *parser.FunctionLiteral:
+		r.funLit(n)
+	case *parser.ArrayLiteral:
+		for _, elem := range n.Elements {
+			r.expr(elem)
+		}
+	case *parser.IndexExpression:
+		r.expr(n.Left)
+		r.expr(n.Index)
+	case *parser.StructLiteral:
+		r.structLit(n)
+	case *parser.SelectorExpression:
+		r.expr(n.Left)
+		if id, ok := n.Left.(*parser.Identifier); ok {
+			if obj := r.info.Uses[id]; obj != nil && obj.Kind == Module {
+				r.member(obj, n.Field)
+			}
+		}
+	case *parser.MatchExpression:
+		r.match(n)
+	case *parser.BinaryExpression:
+		r.expr(n.Left)
+		r.expr(n.Right)
+	case *parser.PrefixExpression:
+		r.expr(n.Value)
+	}
+}
+
+// use binds the identifier to the object it refers to. It returns nil if
+// the identifier is undefined.
+func (r *Resolver) use(id *parser.Identifier) *Object {
+	obj, i := r.lookup(id.Value)
+	if obj == nil {
+		r.diags.TokenErrorf("R0001", id.Token, "Undefined identifier [%s].", id.Value)
+		return nil
+	}
+	r.info.Uses[id] = obj
+	// Global variables, functions and structs are never captured.
+	if i > 0 && (obj.Kind == Var || obj.Kind == Param) {
+		r.capture(obj)
+	}
+	return obj
+}
+
+// qualified binds the name that is optionally qualified by a module.
+func (r *Resolver) qualified(mod, id *parser.Identifier) *Object {
+	if mod == nil {
+		return r.use(id)
+	}
+	if obj := r.use(mod); obj != nil {
+		return r.member(obj, id)
+	}
+	return nil
+}
+
+// member binds the identifier to the public member of the module with its
+// name. It returns nil if the module has no such member or if the member is
+// not public.
+func (r *Resolver) member(mod *Object, id *parser.Identifier) *Object {
+	if mod.Kind != Module {
+		r.diags.TokenErrorf("R0011", id.Token, "[%s] is not a module.", mod.Name)
+		return nil
+	}
+	obj, ok := mod.Members[id.Value]
+	if !ok {
+		r.diags.TokenErrorf("R0011", id.Token, "Module [%s] has no member [%s].", mod.Name, id.Value)
+		return nil
+	}
+	if !obj.IsPub() {
+		d := r.diags.TokenErrorf("R0012", id.Token, "[%s] is not public in module [%s].", id.Value, mod.Name)
+		d.AddNote(declLocation(obj), "[%s] is defined here.", id.Value)
+		return nil
+	}
+	r.info.Uses[id] = obj
+	return obj
+}
+
+// capture adds the object to the captures of all function literals between
+// its declaration and the current statement.
+func (r *Resolver) capture(obj *Object) {
+	for _, lit := range r.lits[r.levels[obj]:] {
+		obj.Captured = true
+		if !contains(r.info.Captures[lit], obj) {
+			r.info.Captures[lit] = append(r.info.Captures[lit], obj)
+		}
+	}
+}
+
+func contains(objs []*Object, obj *Object) bool {
+	for _, o := range objs {
+		if o == obj {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) structLit(n *parser.StructLiteral) {
+	if obj := r.qualified(n.Module, n.Name); obj != nil && obj.Kind != Struct {
+		r.diags.TokenErrorf("R0007", n.Name.Token, "[%s] is not a struct.", n.Name.Value)
+	}
+	for _, value := range n.Values {
+		r.expr(value)
+	}
+}
+
+// match resolves the arms of the match expression. The bindings of an arm
+// are only visible in the body of the arm.
+func (r *Resolver) match(n *parser.MatchExpression) {
+	r.expr(n.Value)
+	for _, arm := range n.Arms {
+		if !arm.IsWildcard() {
+			if obj := r.qualified(arm.Module, arm.Pattern); obj != nil && obj.Kind != Variant {
+				r.diags.TokenErrorf("R0008", arm.Pattern.Token, "[%s] is not a variant.", arm.Pattern.Value)
+			}
+		}
+		r.pushScope()
+		bindings := r.scopes[len(r.scopes)-1]
+		for _, id := range arm.Bindings {
+			if prev, ok := bindings[id.Value]; ok {
+				d := r.diags.TokenErrorf("R0003", id.Token, "Duplicate binding [%s] in pattern [%s].", id.Value, arm.Pattern.Value)
+				d.AddNote(locationOf(prev.Decl), "Previous declaration of [%s].", id.Value)
+			}
+			r.declare(Var, id)
+		}
+		r.expr(arm.Body)
+		r.popScope()
+	}
+}
+
+func (r *Resolver) callExpr(n *parser.CallExpression) {
+	r.expr(n.Function)
+	for _, arg := range n.Args {
+		r.expr(arg)
+	}
+	id := r.info.Name(n.Function)
+	if id == nil {
+		return
+	}
+	obj := r.info.Uses[id]
+	if obj == nil || obj.Kind != Func {
+		return
+	}
+	// Variadic functions accept additional arguments.
+	switch {
+	case obj.Fun.Variadic && len(n.Args) < len(obj.Fun.Params):
+		d := r.diags.TokenErrorf("R0004", n.Token, "Function [%s] expects at least [%d] arguments but got [%d].", id.Value, len(obj.Fun.Params), len(n.Args))
+		d.AddNote(locationOf(obj.Decl), "[%s] is defined here.", id.Value)
+	case !obj.Fun.Variadic && len(n.Args) != len(obj.Fun.Params):
+		d := r.diags.TokenErrorf("R0004", n.Token, "Function [%s] expects [%d] arguments but got [%d].", id.Value, len(obj.Fun.Params), len(n.Args))
+		d.AddNote(locationOf(obj.Decl), "[%s] is defined here.", id.Value)
+	}
+}
+
+// declLocation returns the location of the declaration of the object.
+func declLocation(obj *Object) *diag.Location {
+	if obj.Kind == Module {
+		loc := diag.NodeLocation(obj.Import.Path)
+		return &loc
+	}
+	return locationOf(obj.Decl)
+}
+
+func locationOf(id *parser.Identifier) *diag.Location {
+	loc := diag.TokenLocation(id.Token)
+	return &loc
+}
@@ -0,0 +1,277 @@
+package resolve_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/lexer"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+)
+
+func TestBindings(t *testing.T) {
+	testBinding(t, "let a = 1; a;", "variable a at 1:5")
+	testBinding(t, "fn f(a) { return a; }", "parameter a at 1:6")
+	testBinding(t, "f; fn f() { return 1; }", "function f at 1:7")
+	testBinding(t, "let a = 1; { let a = true; a; }", "variable a at 1:18")
+	testBinding(t, "let a = 1; { let a = a; }", "variable a at 1:5")
+	testBinding(t, "let a = 1; fn f(a) { return a; }", "parameter a at 1:17")
+	testBinding(t, "for let i = 0; i < 1; i = i + 1 { i; }", "variable i at 1:9")
+	testBinding(t, "{ g; fn g() { return 1; } }", "function g at 1:9")
+	testBinding(t, "print(1);", "builtin function print")
+	testBinding(t, "len([1]);", "builtin function len")
+	testBinding(t, "let a = [1]; a[0] = a[0];", "variable a at 1:5")
+	testBinding(t, "fn print(a) { return a; } print(1);", "function print at 1:4")
+	testBinding(t, "let f = fn(a) { return a; };", "parameter a at 1:12")
+	testBinding(t, "fn f(a) { return fn() { return a; }; }", "parameter a at 1:6")
+	testBinding(t, "fn f() { let a = 1; return fn(a) { return a; }; }", "parameter a at 1:31")
+	testBinding(t, "P { x: 1 }; struct P { x }", "struct P at 1:20")
+	testBinding(t, "let p = 1; p.x = p.y;", "variable p at 1:5")
+	testBinding(t, "A(1); enum E { A(x) }", "variant A at 1:16")
+	testBinding(t, "enum E { A(x) } let x = 1; match x { A(x) => x };", "variable x at 1:40")
+	testBinding(t, "enum E { A(x) } let e = 1; match e { _ => e };", "variable e at 1:21")
+}
+
+func TestCaptures(t *testing.T) {
+	testCaptures(t, "fn f(a) { return fn(b) { return b; }; }", "")
+	testCaptures(t, "fn f(a) { let b = 1; return fn(c) { return a + b + a; }; }", "a b")
+	testCaptures(t, "let a = 1; fn f() { return fn() { return a; }; }", "")
+	testCaptures(t, "fn f(a) { return fn() { return g(a); }; } fn g(x) { return x; }", "a")
+	testCaptures(t, "fn f(a) { return fn(b) { return fn() { return a + b; }; }; }", "a", "a b")
+	testCaptures(t, "fn f() { struct P { x } return fn() { return P { x: 1 }; }; }", "")
+	testCaptures(t, "enum E { A(x) } fn f(e) { return match e { A(y) => fn() { return y; } }; }", "y")
+}
+
+func TestResolveErrors(t *testing.T) {
+	testErrors(t, "a;", "1:1: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "let a = a;", "1:9: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "{ let a = 1; } a;", "1:16: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "let a = 1; fn f() { return a + b; }", "1:32: error[R0001]: Undefined identifier [b].")
+	testErrors(t, "fn f() { let a = 1; } fn g() { return a; }", "1:39: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "b = 1;", "1:1: error[R0001]: Undefined identifier [b].")
+	testErrors(t, "let a = [b]; a[c] = 1;", "1:10: error[R0001]: Undefined identifier [b].", "1:16: error[R0001]: Undefined identifier [c].")
+	testErrors(t, "x(y);", "1:1: error[R0001]: Undefined identifier [x].", "1:3: error[R0001]: Undefined identifier [y].")
+	testErrors(t, "fn f() { return 1; } fn f() { return 2; }", "1:25: error[R0002]: Function [f] is already defined.")
+	testErrors(t, "fn f(a, b, a) { return a; }", "1:12: error[R0003]: Duplicate parameter [a] in function [f].")
+	testErrors(t, "fn(a, a) { return a; };", "1:7: error[R0003]: Duplicate parameter [a] in anonymous function.")
+	testErrors(t, "fn f() { let a = 1; fn g() { return a; } }", "1:37: error[R0001]: Undefined identifier [a].")
+	testErrors(t, "fn f(a) { return a; } f();", "1:24: error[R0004]: Function [f] expects [1] arguments but got [0].")
+	testErrors(t, "fn f() { return 1; } f(1, 2);", "1:23: error[R0004]: Function [f] expects [0] arguments but got [2].")
+	testErrors(t, "fn f() { return 1; } f = 1;", "1:22: error[R0005]: Cannot assign to function [f].")
+	testErrors(t, "println = 1;", "1:1: error[R0005]: Cannot assign to function [println].")
+	testErrors(t, "struct P { x } struct P { y }", "1:23: error[R0002]: Struct [P] is already defined.")
+	testErrors(t, "fn P() { return 1; } struct P { x }", "1:29: error[R0002]: Struct [P] is already defined.")
+	testErrors(t, "struct P { x } P = 1;", "1:16: error[R0005]: Cannot assign to struct [P].")
+	testErrors(t, "struct P { x, y, x }", "1:18: error[R0006]: Duplicate field [x] in struct [P].")
+	testErrors(t, "Q { x: 1 };", "1:1: error[R0001]: Undefined identifier [Q].")
+	testErrors(t, "let a = 1; a { x: b };", "1:12: error[R0007]: [a] is not a struct.", "1:19: error[R0001]: Undefined identifier [b].")
+	testErrors(t, "enum E { A } enum E { B }", "1:19: error[R0002]: Enum [E] is already defined.")
+	testErrors(t, "enum E { A, B, A }", "1:16: error[R0002]: Variant [A] is already defined.")
+	testErrors(t, "enum E { A(x, x) }", "1:15: error[R0006]: Duplicate field [x] in variant [A].")
+	testErrors(t, "enum E { A } A = 1;", "1:14: error[R0005]: Cannot assign to variant [A].")
+	testErrors(t, "let a = 1; match a { a => 1, B => 2 };", "1:22: error[R0008]: [a] is not a variant.", "1:30: error[R0001]: Undefined identifier [B].")
+	testErrors(t, "enum E { A(x, y) } match 1 { A(z, z) => z };", "1:35: error[R0003]: Duplicate binding [z] in pattern [A].")
+	testErrors(t, "enum E { A(x) } match 1 { A(y) => 1 }; y;", "1:40: error[R0001]: Undefined identifier [y].")
+	testErrors(t, "let a = 1; let b: a = 2;", "1:19: error[R0009]: [a] is not a type.")
+	testErrors(t, "fn f(a: T) -> [U] { return a; }", "1:9: error[R0001]: Undefined identifier [T].", "1:16: error[R0001]: Undefined identifier [U].")
+	testErrors(t, "u8 = 1;", "1:1: error[R0005]: Cannot assign to type [u8].")
+	testErrors(t, "extern fn printf(format: string, ...) -> i32; printf();", "1:53: error[R0004]: Function [printf] expects at least [1] arguments but got [0].")
+	testErrors(t, "extern fn f(c: i32, c: i32);", "1:21: error[R0003]: Duplicate parameter [c] in function [f].")
+	testErrors(t, "fn f() { extern fn g(); export fn h() { } }", "1:20: error[R0013]: Local function [g] cannot be extern.", "1:35: error[R0013]: Local function [h] cannot be exported.")
+}
+
+func TestExterns(t *testing.T) {
+	testErrors(t, "extern fn putchar(c: i32) -> i32; putchar(65);")
+	testErrors(t, "extern fn printf(format: string, ...) -> i32; printf(\"\"); printf(\"%d %d\", 1, 2);")
+	testErrors(t, "export fn f(x: i32) -> i32 { return x; } f(1);")
+}
+
+func TestModules(t *testing.T) {
+	geo := module{"lib/geo", "pub struct P { x } pub enum E { A } pub fn f(a) { return a; } fn g() { return 1; }"}
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; let p: geo.P = geo.f(geo.A); fn f(q: [geo.E]) { return geo.f(q); }`}})
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo.g();`}}, "1:23: error[R0012]: [g] is not public in module [geo].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo.h();`}}, "1:23: error[R0011]: Module [geo] has no member [h].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo.f();`}}, "1:24: error[R0004]: Function [f] expects [1] arguments but got [0].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; let a: geo.f = 1;`}}, "1:30: error[R0009]: [f] is not a type.")
+	testModuleErrors(t, []module{geo, {"", `fn f() { return 1; } let a: f.P = 1;`}}, "1:31: error[R0011]: [f] is not a module.")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo = 1;`}}, "1:19: error[R0005]: Cannot assign to module [geo].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; let p = geo.P { x: 1 }; let a = match geo.A { geo.A => p.x, _ => 0 };`}})
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; match geo.A { A => 1 };`}}, "1:33: error[R0001]: Undefined identifier [A].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo.Q { x: 1 };`}}, "1:23: error[R0011]: Module [geo] has no member [Q].")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; geo.f { x: 1 };`}}, "1:23: error[R0007]: [f] is not a struct.")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; match geo.A { geo.P => 1 };`}}, "1:37: error[R0008]: [P] is not a variant.")
+	testModuleErrors(t, []module{geo, {"", `let a = 1; match 1 { a.A => 1 };`}}, "1:24: error[R0011]: [a] is not a module.")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; import "lib/geo";`}}, "1:26: error[R0002]: Module [geo] is already imported.")
+	testModuleErrors(t, []module{geo, {"", `import "lib/geo"; fn geo() { return 1; }`}}, "1:22: error[R0002]: Function [geo] is already defined.")
+	testModuleErrors(t, []module{geo, {"", `import "geo";`}}, "1:8: error[R0010]: Unknown module [geo].")
+	testModuleErrors(t, []module{geo, {"", `fn f() { import "lib/geo"; return 1; }`}}, "1:10: error[R0013]: Imports are only allowed at the top level.")
+	testModuleErrors(t, []module{geo, {"", `{ pub fn f() { return 1; } }`}}, "1:10: error[R0013]: Local definition [f] cannot be public.")
+	// Imported modules do not see the definitions of the importing module.
+	testModuleErrors(t, []module{{"a", "pub fn f() { return g(); }"}, {"", `import "a"; fn g() { return 1; }`}}, "1:21: error[R0001]: Undefined identifier [g].")
+}
+
+func TestResolveNotes(t *testing.T) {
+	d := resolveErrors(t, "fn f() { return 1; }\nfn f() { return 2; }")[0]
+	if len(d.Notes) != 1 || d.Notes[0].Span.Start != (diag.Pos{Line: 1, Col: 4}) {
+		t.Errorf("Expected a note at the previous definition but got %v.", d.Notes)
+	}
+}
+
+func TestResolveNotesInOtherModules(t *testing.T) {
+	mods := []module{
+		{"lib/geo", "pub fn area(w, h) { return w * h; }\nfn helper() { return 1; }"},
+		{"", `import "lib/geo"; geo.helper(); geo.area(1);`},
+	}
+	files := []string{"lib/geo.dk", "main.dk"}
+	r := diag.NewRenderer()
+	progs := []*parser.Program{}
+	for i, mod := range mods {
+		prog := parser.NewParser(lexer.NewFileLexer(files[i], mod.source)).Parse()
+		prog.Path = mod.path
+		progs = append(progs, prog)
+		r.AddSource(files[i], mod.source)
+	}
+	diags := diag.NewList()
+	resolve.NewResolver(diags).ResolveModules(progs)
+	var buf bytes.Buffer
+	r.RenderAll(&buf, diags.Items())
+	expected := `main.dk:1:23: error[R0012]: [helper] is not public in module [geo].
+   |
+ 1 | import "lib/geo"; geo.helper(); geo.area(1);
+   |                       ^^^^^^
+ = note: lib/geo.dk:2:4: [helper] is defined here.
+   |
+ 2 | fn helper() { return 1; }
+   |    ^^^^^^
+main.dk:1:41: error[R0004]: Function [area] expects [2] arguments but got [1].
+   |
+ 1 | import "lib/geo"; geo.helper(); geo.area(1);
+   |                                         ^
+ = note: lib/geo.dk:1:8: [area] is defined here.
+   |
+ 1 | pub fn area(w, h) { return w * h; }
+   |        ^^^^
+`
+	if buf.String() != expected {
+		t.Errorf("Expected [\n%s] but got [\n%s].", expected, buf.String())
+	}
+}
+
+func resolveProgram(t *testing.T, input string) (*parser.Program, *resolve.Info, *diag.List) {
+	t.Helper()
+	p := parser.NewParser(lexer.NewLexer(input))
+	prog := p.Parse()
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", p.Errors())
+	}
+	diags := diag.NewList()
+	info := resolve.NewResolver(diags).Resolve(prog)
+	return prog, info, diags
+}
+
+// module is the source code of a module and its import path.
+type module struct {
+	path   string
+	source string
+}
+
+func testModuleErrors(t *testing.T, mods []module, expected ...string) {
+	t.Helper()
+	progs := []*parser.Program{}
+	for _, mod := range mods {
+		p := parser.NewParser(lexer.NewLexer(mod.source))
+		prog := p.Parse()
+		if p.HasErrors() {
+			t.Fatalf("Unexpected parser errors %v.", p.Errors())
+		}
+		prog.Path = mod.path
+		progs = append(progs, prog)
+	}
+	diags := diag.NewList()
+	resolve.NewResolver(diags).ResolveModules(progs)
+	actual := diags.Items()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
+
+func resolveErrors(t *testing.T, input string) []*diag.Diagnostic {
+	t.Helper()
+	_, _, diags := resolveProgram(t, input)
+	return diags.Items()
+}
+
+// testBinding checks the object the last used identifier is bound to.
+func testBinding(t *testing.T, input string, expected string) {
+	t.Helper()
+	_, info, diags := resolveProgram(t, input)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", diags.Items())
+	}
+	var last *parser.Identifier
+	for id := range info.Uses {
+		if last == nil || id.Token.Col > last.Token.Col {
+			last = id
+		}
+	}
+	obj := info.ObjectOf(last)
+	actual := fmt.Sprintf("%s %s", obj.Kind, obj.Name)
+	if obj.Decl != nil {
+		actual += fmt.Sprintf(" at %d:%d", obj.Decl.Token.Line, obj.Decl.Token.Col)
+	}
+	if actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
+
+// testCaptures checks the captured names of the function literals in the
+// order of their position.
+func testCaptures(t *testing.T, input string, expected ...string) {
+	t.Helper()
+	_, info, diags := resolveProgram(t, input)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", diags.Items())
+	}
+	lits := []*parser.FunctionLiteral{}
+	for lit := range info.Captures {
+		lits = append(lits, lit)
+	}
+	sort.Slice(lits, func(i, j int) bool { return lits[i].Token.Offset < lits[j].Token.Offset })
+	if len(lits) != len(expected) {
+		t.Fatalf("Expected [%d] function literals but got [%d].", len(expected), len(lits))
+	}
+	for i, lit := range lits {
+		names := []string{}
+		for _, obj := range info.Captures[lit] {
+			names = append(names, obj.Name)
+			if !obj.Captured {
+				t.Errorf("Expected [%s] to be captured.", obj.Name)
+			}
+		}
+		if actual := strings.Join(names, " "); actual != expected[i] {
+			t.Errorf("Expected [%s] but got [%s].", expected[i], actual)
+		}
+	}
+}
+
+func testErrors(t *testing.T, input string, expected ...string) {
+	t.Helper()
+	actual := resolveErrors(t, input)
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package token
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Position describes a location in a source file.
+type Position struct {
+	File   string // File name, if any.
+	Offset int    // Byte offset, starting at 0.
+	Line   int    // Line number, starting at 1.
+	Col    int    // Column number, starting at 1.
+}
+
+// IsValid returns true iff the position has a line number.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form [file:line:col]. The file name
+// is omitted if it is empty.
+func (p Position) String() string {
+	s := fmt.Sprintf("%d:%d", p.Line, p.Col)
+	if p.File != "" {
+		s = p.File + ":" + s
+	}
+	return s
+}
+
+// ColumnUnit is the unit columns are counted in.
+type ColumnUnit int
+
+const (
+	// Runes counts every Unicode code point as one column.
+	Runes ColumnUnit = iota
+	// UTF16 counts columns in UTF-16 code units as many editors do. Code
+	// points outside of the basic multilingual plane take two columns.
+	UTF16
+)
+
+// Pos is a compact encoding of a source position within a file set. It is
+// the byte offset of the position plus the base of its file. The zero
+// value NoPos is not part of any file.
+type Pos int
+
+const NoPos Pos = 0
+
+// File maps byte offsets within a source file to lines and columns.
+type File struct {
+	name  string
+	base  int
+	src   string
+	unit  ColumnUnit
+	lines []int // Byte offsets of the first character of each line.
+}
+
+// NewFile creates a file that does not belong to a file set.
+func NewFile(name string, src string, unit ColumnUnit) *File {
+	f := &File{name: name, base: 1, src: src, unit: unit, lines: []int{0}}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			f.lines = append(f.lines, i+1)
+		}
+	}
+	return f
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+func (f *File) Source() string {
+	return f.src
+}
+
+// Size returns the length of the source in bytes.
+func (f *File) Size() int {
+	return len(f.src)
+}
+
+// Base returns the position of the first byte of the file in its file
+// set.
+func (f *File) Base() int {
+	return f.base
+}
+
+// LineCount returns the number of lines of the file.
+func (f *File) LineCount() int {
+	return len(f.lines)
+}
+
+// Line returns the text of the line without the line break. Line numbers
+// start at 1.
+func (f *File) Line(line int) string {
+	if line < 1 || line > len(f.lines) {
+		return ""
+	}
+	start := f.lines[line-1]
+	end := len(f.src)
+	if line < len(f.lines) {
+		end = f.lines[line] - 1
+	}
+	return f.src[start:end]
+}
+
+// Pos returns the position of the byte offset in the file set.
+func (f *File) Pos(offset int) Pos {
+	return Pos(f.base + offset)
+}
+
+// Offset returns the byte offset of the position within the file.
+func (f *File) Offset(p Pos) int {
+	return int(p) - f.base
+}
+
+// Position returns the line and column of the byte offset. The offset may
+// be the size of the file to refer to the end of the file.
+func (f *File) Position(offset int) Position {
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset }) - 1
+	return Position{
+		File:   f.name,
+		Offset: offset,
+		Line:   i + 1,
+		Col:    f.columns(f.src[f.lines[i]:offset]) + 1,
+	}
+}
+
+// columns returns the number of columns the text takes.
+func (f *File) columns(s string) int {
+	if f.unit == Runes {
+		return utf8.RuneCountInString(s)
+	}
+	n := 0
+	for _, c := range s {
+		n++
+		if c > 0xFFFF {
+			n++
+		}
+	}
+	return n
+}
+
+// FileSet maps positions to the files of a program. Each file occupies a
+// range of positions that starts at the base of the file.
+type FileSet struct {
+	base  int
+	unit  ColumnUnit
+	files []*File
+}
+
+// NewFileSet creates a file set that counts columns in runes.
+func NewFileSet() *FileSet {
+	return NewFileSetWithUnit(Runes)
+}
+
+// NewFileSetWithUnit creates a file set that counts columns in the given
+// unit.
+func NewFileSetWithUnit(unit ColumnUnit) *FileSet {
+	return &FileSet{base: 1, unit: unit}
+}
+
+// AddFile adds a file with the given source to the set.
+func (s *FileSet) AddFile(name string, src string) *File {
+	f := NewFile(name, src, s.unit)
+	f.base = s.base
+	// Reserve one extra position for the end of the file.
+	s.base += len(src) + 1
+	s.files = append(s.files, f)
+	return f
+}
+
+func (s *FileSet) Files() []*File {
+	return s.files
+}
+
+// File returns the file that contains the position or nil if no file
+// contains it.
+func (s *FileSet) File(p Pos) *File {
+	i := sort.Search(len(s.files), func(i int) bool { return s.files[i].base > int(p) }) - 1
+	if i < 0 || int(p) > s.files[i].base+s.files[i].Size() {
+		return nil
+	}
+	return s.files[i]
+}
+
+// Lookup returns the file with the given name or nil if there is no such
+// file.
+func (s *FileSet) Lookup(name string) *File {
+	for _, f := range s.files {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Position converts the position into a file name, line and column. It
+// returns the zero Position if the position is not part of the set.
+func (s *FileSet) Position(p Pos) Position {
+	if f := s.File(p); f != nil {
+		return f.Position(f.Offset(p))
+	}
+	return Position{}
+}
@@ -0,0 +1,56 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/mhoertnagl/donkey/token"
+)
+
+func TestFilePosition(t *testing.T) {
+	f := token.NewFile("a.dk", "let a;\n\nlet größe;\n", token.Runes)
+	testPosition(t, f.Position(0), "a.dk:1:1")
+	testPosition(t, f.Position(5), "a.dk:1:6")
+	testPosition(t, f.Position(6), "a.dk:1:7")
+	testPosition(t, f.Position(7), "a.dk:2:1")
+	testPosition(t, f.Position(8), "a.dk:3:1")
+	testPosition(t, f.Position(19), "a.dk:3:10")
+	testPosition(t, f.Position(21), "a.dk:4:1")
+	if n := f.LineCount(); n != 4 {
+		t.Errorf("Expected [4] lines but got [%d].", n)
+	}
+	if l := f.Line(3); l != "let größe;" {
+		t.Errorf("Expected [let größe;] but got [%s].", l)
+	}
+}
+
+func TestFilePositionUTF16(t *testing.T) {
+	f := token.NewFile("", "😀é x", token.UTF16)
+	testPosition(t, f.Position(4), "1:3")
+	testPosition(t, f.Position(7), "1:5")
+}
+
+func TestFileSet(t *testing.T) {
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.dk", "let a;\n")
+	b := fset.AddFile("b.dk", "x\ny")
+	testPosition(t, fset.Position(a.Pos(4)), "a.dk:1:5")
+	testPosition(t, fset.Position(a.Pos(7)), "a.dk:2:1")
+	testPosition(t, fset.Position(b.Pos(0)), "b.dk:1:1")
+	testPosition(t, fset.Position(b.Pos(2)), "b.dk:2:1")
+	if f := fset.File(b.Pos(3)); f != b {
+		t.Errorf("Expected the end of [b.dk] to belong to [b.dk].")
+	}
+	if p := fset.Position(token.NoPos); p.IsValid() {
+		t.Errorf("Expected an invalid position but got [%s].", p)
+	}
+	if f := fset.Lookup("b.dk"); f != b {
+		t.Errorf("Expected to find [b.dk].")
+	}
+}
+
+func testPosition(t *testing.T, p token.Position, expected string) {
+	t.Helper()
+	if actual := p.String(); actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
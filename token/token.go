@@ -2,11 +2,29 @@ package token
 
 type TokenType string
 
+// Token is a lexeme of the source together with its location. The start
+// position refers to the first character of the token. The end position
+// refers to the character immediately following the token.
 type Token struct {
-	Typ     TokenType
-	Literal string
-	Line    int
-	Col     int
+	Typ       TokenType
+	Literal   string
+	File      string
+	Line      int
+	Col       int
+	Offset    int
+	EndLine   int
+	EndCol    int
+	EndOffset int
+}
+
+// Start returns the position of the first character of the token.
+func (t Token) Start() Position {
+	return Position{File: t.File, Offset: t.Offset, Line: t.Line, Col: t.Col}
+}
+
+// End returns the position of the character following the token.
+func (t Token) End() Position {
+	return Position{File: t.File, Offset: t.EndOffset, Line: t.EndLine, Col: t.EndCol}
 }
 
 const (
@@ -14,17 +32,24 @@ const (
 	EOF     TokenType = "EOF"
 	ID      TokenType = "ID"
 	INT     TokenType = "INT"
+	FLOAT   TokenType = "FLOAT"
+	STR     TokenType = "STRING"
 	ASSIGN  TokenType = "="
 	PLUS    TokenType = "+"
 	MINUS   TokenType = "-"
 	TIMES   TokenType = "*"
+	POW     TokenType = "**"
 	DIV     TokenType = "/"
+	MOD     TokenType = "%"
 	INV     TokenType = "~"
 	AND     TokenType = "&"
 	OR      TokenType = "|"
 	XOR     TokenType = "^"
-	// TODO: NOR
-	// NOR TokenType = "~|"
+	// The negated bitwise operators ~&, ~| and ~^ are the complements of
+	// &, | and ^.
+	NAND   TokenType = "~&"
+	NOR    TokenType = "~|"
+	XNOR   TokenType = "~^"
 	SLL    TokenType = "<<"
 	SRL    TokenType = ">>"
 	SRA    TokenType = ">>>"
@@ -41,10 +66,17 @@ const (
 	GE     TokenType = ">="
 	COMMA  TokenType = ","
 	SCOLON TokenType = ";"
+	COLON  TokenType = ":"
+	DOT    TokenType = "."
+	DARROW TokenType = "=>"
+	ARROW  TokenType = "->"
+	BLANK  TokenType = "_"
 	LPAR   TokenType = "("
 	RPAR   TokenType = ")"
 	LBRA   TokenType = "{"
 	RBRA   TokenType = "}"
+	LBRK   TokenType = "["
+	RBRK   TokenType = "]"
 	FUN    TokenType = "FUN"
 	LET    TokenType = "LET"
 	TRUE   TokenType = "TRUE"
@@ -52,16 +84,59 @@ const (
 	IF     TokenType = "IF"
 	ELSE   TokenType = "ELSE"
 	RETURN TokenType = "RETURN"
+	WHILE  TokenType = "WHILE"
+	FOR    TokenType = "FOR"
+	BREAK  TokenType = "BREAK"
+	CONT   TokenType = "CONTINUE"
+	STRUCT TokenType = "STRUCT"
+	ENUM   TokenType = "ENUM"
+	MATCH  TokenType = "MATCH"
+	IMPORT TokenType = "IMPORT"
+	PUB    TokenType = "PUB"
+	EXTERN TokenType = "EXTERN"
+	EXPORT TokenType = "EXPORT"
+	// ELLIPSIS marks the variable arguments of an extern function.
+	ELLIPSIS TokenType = "..."
+	// Compound assignment operators apply the binary operator to the
+	// target and the value and assign the result to the target.
+	PLUSEQ  TokenType = "+="
+	MINUSEQ TokenType = "-="
+	TIMESEQ TokenType = "*="
+	DIVEQ   TokenType = "/="
+	MODEQ   TokenType = "%="
+	POWEQ   TokenType = "**="
+	ANDEQ   TokenType = "&="
+	OREQ    TokenType = "|="
+	XOREQ   TokenType = "^="
+	SLLEQ   TokenType = "<<="
+	SRLEQ   TokenType = ">>="
+	SRAEQ   TokenType = ">>>="
+	ROLEQ   TokenType = "<<>="
+	ROREQ   TokenType = "<>>="
+	// Increment and decrement add 1 to and subtract 1 from the target.
+	INC TokenType = "++"
+	DEC TokenType = "--"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     FUN,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUN,
+	"let":      LET,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"break":    BREAK,
+	"continue": CONT,
+	"struct":   STRUCT,
+	"enum":     ENUM,
+	"match":    MATCH,
+	"import":   IMPORT,
+	"pub":      PUB,
+	"extern":   EXTERN,
+	"export":   EXPORT,
 }
 
 func LookupId(id string) TokenType {
@@ -70,3 +145,31 @@ func LookupId(id string) TokenType {
 	}
 	return ID
 }
+
+var compoundOps = map[TokenType]TokenType{
+	PLUSEQ:  PLUS,
+	MINUSEQ: MINUS,
+	TIMESEQ: TIMES,
+	DIVEQ:   DIV,
+	MODEQ:   MOD,
+	POWEQ:   POW,
+	ANDEQ:   AND,
+	OREQ:    OR,
+	XOREQ:   XOR,
+	SLLEQ:   SLL,
+	SRLEQ:   SRL,
+	SRAEQ:   SRA,
+	ROLEQ:   ROL,
+	ROREQ:   ROR,
+	INC:     PLUS,
+	DEC:     MINUS,
+}
+
+// CompoundOp returns the binary operator of a compound assignment
+// operator. Increment and decrement are compound assignments of 1. The
+// second result is false if the token is not a compound assignment
+// operator.
+func CompoundOp(tok TokenType) (TokenType, bool) {
+	op, ok := compoundOps[tok]
+	return op, ok
+}
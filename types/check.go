@@ -0,0 +1,1382 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	"github.com/mhoertnagl/donkey/token"
+)
+
+// Info holds the results of type checking a program.
+type Info struct {
+	// Types maps every expression to its type.
+	Types map[parser.Expression]Type
+	// Defs maps every declared name to its type. Declared names are the
+	// names of let statements, function parameters, functions, structs,
+	// enums and variants. Variants with fields are constructor functions.
+	Defs map[*parser.Identifier]Type
+	// Funcs maps every function definition to its signature.
+	Funcs map[*parser.FunDefStatement]*Func
+	// Structs maps every struct definition to its type.
+	Structs map[*parser.StructDefStatement]*Struct
+	// Enums maps every enum definition to its type.
+	Enums map[*parser.EnumDefStatement]*Enum
+	// Schemes maps every generic function definition to its scheme. The
+	// signatures of generic functions contain their type parameters.
+	Schemes map[*parser.FunDefStatement]*Scheme
+	// Instances maps every use of a generic function to the types its type
+	// parameters are instantiated with. The types are in the order of the
+	// type parameters of the scheme.
+	Instances map[*parser.Identifier][]Type
+}
+
+func newInfo() *Info {
+	return &Info{
+		Types:     make(map[parser.Expression]Type),
+		Defs:      make(map[*parser.Identifier]Type),
+		Funcs:     make(map[*parser.FunDefStatement]*Func),
+		Structs:   make(map[*parser.StructDefStatement]*Struct),
+		Enums:     make(map[*parser.EnumDefStatement]*Enum),
+		Schemes:   make(map[*parser.FunDefStatement]*Scheme),
+		Instances: make(map[*parser.Identifier][]Type),
+	}
+}
+
+// TypeOf returns the type of the expression or nil if the expression has
+// not been checked.
+func (info *Info) TypeOf(e parser.Expression) Type {
+	return info.Types[e]
+}
+
+// The check state of a function definition.
+const (
+	unchecked = iota
+	checking
+	checked
+)
+
+// Checker assigns a type to every expression of a program and reports
+// type errors.
+//
+// The checker relies on the bindings of the name resolver and does not
+// report undefined identifiers itself.
+//
+// Type annotations of variables, parameters, results and fields determine
+// their types. Integer constants are of type int unless they are used where
+// a value of another integer type is expected. Floating-point constants are
+// of type f64 unless they are used where a value of type f32 is expected.
+//
+// The types of parameters and results without a type annotation are
+// inferred from their uses (see infer.go). Values whose fields are
+// selected are of the first struct type that declares the field. Values
+// that are matched are of the enum of the variant of the first arm.
+// Operands of arithmetic operators whose types remain unknown are ints.
+// Fields of structs and variants without a type annotation are of type
+// int. Functions may be called before their definition. Such a function is
+// checked on demand.
+type Checker struct {
+	info  *Info
+	names *resolve.Info
+	diags *diag.List
+	vars  map[*resolve.Object]Type
+	fun   *function // The function being checked.
+	state map[*parser.FunDefStatement]int
+	// Struct types in the order of their definition.
+	structs []*Struct
+	// Locations of the constraints that bound the type variables.
+	origins map[*Var]diag.Location
+	// Groups of mutually recursive top-level functions.
+	group map[*parser.FunDefStatement][]*parser.FunDefStatement
+	// Integer literals that do not fit into 64 bits.
+	wide []*parser.Integer
+}
+
+// function is a function definition or function literal.
+type function struct {
+	name    string // Empty for function literals.
+	sig     *Func
+	returns bool // True iff the body has a return statement.
+}
+
+func (f *function) String() string {
+	if f.name == "" {
+		return "Anonymous function"
+	}
+	return fmt.Sprintf("Function [%s]", f.name)
+}
+
+func NewChecker(diags *diag.List) *Checker {
+	return &Checker{
+		info:    newInfo(),
+		diags:   diags,
+		vars:    make(map[*resolve.Object]Type),
+		state:   make(map[*parser.FunDefStatement]int),
+		origins: make(map[*Var]diag.Location),
+		group:   make(map[*parser.FunDefStatement][]*parser.FunDefStatement),
+	}
+}
+
+func (c *Checker) HasErrors() bool {
+	return c.diags.HasErrors()
+}
+
+func (c *Checker) Errors() []*diag.Diagnostic {
+	return c.diags.Items()
+}
+
+// Check checks the program. The names of the program have to be resolved
+// by the resolver in advance.
+func (c *Checker) Check(prog *parser.Program, names *resolve.Info) *Info {
+	c.names = names
+	c.groups(prog)
+	c.stmts(prog.Statements)
+	c.finish()
+	c.wideInts()
+	return c.info
+}
+
+func (c *Checker) declare(id *parser.Identifier, typ Type) {
+	if obj := c.names.Defs[id]; obj != nil {
+		c.vars[obj] = typ
+	}
+	c.info.Defs[id] = typ
+}
+
+// typeOf returns the type of the object the identifier refers to.
+func (c *Checker) typeOf(id *parser.Identifier) Type {
+	obj := c.names.Uses[id]
+	if obj == nil {
+		return Invalid
+	}
+	if obj.Kind == resolve.Func {
+		return c.signature(obj.Fun)
+	}
+	// Variables of the global scope are unknown if they are used by a
+	// function that is checked on demand before their declaration.
+	if typ, ok := c.vars[obj]; ok {
+		return typ
+	}
+	return Invalid
+}
+
+func (c *Checker) error(tok token.Token, code string, format string, a ...any) *diag.Diagnostic {
+	return c.diags.TokenErrorf(code, tok, format, a...)
+}
+
+// nodeError reports an error that covers the whole node.
+func (c *Checker) nodeError(n parser.Node, code string, format string, a ...any) *diag.Diagnostic {
+	return c.diags.NodeErrorf(code, n, format, a...)
+}
+
+// signature returns the signature of the function. The types of parameters
+// and results without a type annotation are type variables until the body
+// of the function has been checked.
+func (c *Checker) signature(n *parser.FunDefStatement) *Func {
+	if sig, ok := c.info.Funcs[n]; ok {
+		return sig
+	}
+	var sig *Func
+	if n.Extern {
+		sig = c.externSignature(n)
+	} else {
+		sig = &Func{Params: c.params(n.ParamTypes, len(n.Params)), Result: c.result(n.Result)}
+	}
+	c.info.Funcs[n] = sig
+	c.info.Defs[n.Name] = sig
+	return sig
+}
+
+// externSignature returns the signature of an extern function. There is
+// no body to infer types from so parameters need a type annotation. An
+// extern function without a result type returns void.
+func (c *Checker) externSignature(n *parser.FunDefStatement) *Func {
+	sig := &Func{Params: make([]Type, len(n.Params)), Result: Void, Variadic: n.Variadic}
+	for i, param := range n.Params {
+		if i < len(n.ParamTypes) && n.ParamTypes[i] != nil {
+			sig.Params[i] = c.typeExpr(n.ParamTypes[i])
+		} else {
+			c.error(param.Token, "T0030", "Parameter [%s] of extern function [%s] needs a type annotation.", param.Value, n.Name.Value)
+			sig.Params[i] = Invalid
+		}
+	}
+	if n.Result != nil {
+		sig.Result = c.typeExpr(n.Result)
+	}
+	return sig
+}
+
+// params returns the types of the parameters of a function. Parameters
+// without a type annotation are of unknown type.
+func (c *Checker) params(types []parser.TypeExpr, n int) []Type {
+	params := make([]Type, n)
+	for i := range params {
+		if i < len(types) && types[i] != nil {
+			params[i] = c.typeExpr(types[i])
+		} else {
+			params[i] = c.fresh()
+		}
+	}
+	return params
+}
+
+// result returns the result type of a function. The result type of a
+// function without a result type annotation is unknown.
+func (c *Checker) result(n parser.TypeExpr) Type {
+	if n == nil {
+		return c.fresh()
+	}
+	return c.typeExpr(n)
+}
+
+// typeExpr returns the type denoted by the type annotation. Unresolved
+// names have been reported by the resolver and are of invalid type. The
+// result type of a function type defaults to int.
+func (c *Checker) typeExpr(n parser.TypeExpr) Type {
+	switch n := n.(type) {
+	case *parser.Identifier:
+		obj := c.names.Uses[n]
+		if obj == nil {
+			return Invalid
+		}
+		switch obj.Kind {
+		case resolve.Type:
+			return Predeclared[obj.Name]
+		case resolve.Struct:
+			if typ, ok := c.info.Structs[obj.Struct]; ok {
+				return typ
+			}
+		case resolve.Enum:
+			if typ, ok := c.info.Enums[obj.Enum]; ok {
+				return typ
+			}
+		}
+	case *parser.SelectorExpression:
+		// A type of an imported module.
+		return c.typeExpr(n.Field)
+	case *parser.ArrayType:
+		return &Array{Elem: c.typeExpr(n.Elem)}
+	case *parser.FuncType:
+		sig := &Func{Params: make([]Type, len(n.Params)), Result: Int}
+		for i, p := range n.Params {
+			sig.Params[i] = c.typeExpr(p)
+		}
+		if n.Result != nil {
+			sig.Result = c.typeExpr(n.Result)
+		}
+		return sig
+	}
+	return Invalid
+}
+
+// structWithField returns the first struct type that declares a field
+// with the name or nil if there is none.
+func (c *Checker) structWithField(name string) Type {
+	for _, s := range c.structs {
+		if s.FieldIndex(name) >= 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+// enumOfArms returns the enum of the variant of the first arm that is not
+// a wildcard or nil if there is none.
+func (c *Checker) enumOfArms(n *parser.MatchExpression) Type {
+	for _, arm := range n.Arms {
+		obj := c.names.Uses[arm.Pattern]
+		if obj == nil || obj.Kind != resolve.Variant {
+			continue
+		}
+		if enum, ok := c.info.Enums[obj.Enum]; ok {
+			return enum
+		}
+	}
+	return nil
+}
+
+// stmts declares the struct and enum types of a block before the
+// statements are checked. The types are declared before their fields so
+// that fields may refer to any type of the block.
+func (c *Checker) stmts(ns []parser.Statement) {
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.StructDefStatement:
+			c.info.Structs[n] = &Struct{Name: n.Name.Value, Fields: []*Field{}}
+		case *parser.EnumDefStatement:
+			c.info.Enums[n] = &Enum{Name: n.Name.Value, Variants: []*Variant{}}
+		}
+	}
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.StructDefStatement:
+			c.structDef(n)
+		case *parser.EnumDefStatement:
+			c.enumDef(n)
+		}
+	}
+	for _, s := range ns {
+		switch n := s.(type) {
+		case *parser.StructDefStatement:
+			if typ := c.info.Structs[n]; containsItself(typ) {
+				c.error(n.Name.Token, "T0033", "Struct [%s] cannot contain itself.", n.Name.Value)
+			}
+		case *parser.EnumDefStatement:
+			if typ := c.info.Enums[n]; containsItself(typ) {
+				c.error(n.Name.Token, "T0033", "Enum [%s] cannot contain itself.", n.Name.Value)
+			}
+		}
+	}
+	for _, s := range ns {
+		c.stmt(s)
+	}
+}
+
+// containsItself returns true iff a value of the struct or enum type
+// contains a value of the same type. Such a value would be infinitely
+// large. Arrays refer to their elements and may contain the type.
+func containsItself(t Type) bool {
+	seen := map[Type]bool{}
+	var contains func(Type) bool
+	contains = func(u Type) bool {
+		for _, f := range fieldTypes(u) {
+			f = Resolve(f)
+			if f == t {
+				return true
+			}
+			if !seen[f] {
+				seen[f] = true
+				if contains(f) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return contains(t)
+}
+
+// fieldTypes returns the types of the fields of a struct or of the
+// variants of an enum.
+func fieldTypes(t Type) []Type {
+	types := []Type{}
+	switch t := t.(type) {
+	case *Struct:
+		for _, f := range t.Fields {
+			types = append(types, f.Type)
+		}
+	case *Enum:
+		for _, v := range t.Variants {
+			types = append(types, v.Fields...)
+		}
+	}
+	return types
+}
+
+// structDef declares the fields of the struct type of the definition.
+// Duplicate fields have been reported by the resolver and are ignored.
+func (c *Checker) structDef(n *parser.StructDefStatement) {
+	typ := c.info.Structs[n]
+	for i, id := range n.Fields {
+		if typ.FieldIndex(id.Value) < 0 {
+			typ.Fields = append(typ.Fields, &Field{Name: id.Value, Type: c.fieldType(n.FieldTypes, i)})
+		}
+	}
+	c.info.Defs[n.Name] = typ
+	c.structs = append(c.structs, typ)
+}
+
+// fieldType returns the type of the i-th field of a struct or variant.
+// Fields without a type annotation are of type int.
+func (c *Checker) fieldType(types []parser.TypeExpr, i int) Type {
+	if i < len(types) && types[i] != nil {
+		return c.typeExpr(types[i])
+	}
+	return Int
+}
+
+// enumDef declares the variants of the enum type of the definition and
+// their types.
+func (c *Checker) enumDef(n *parser.EnumDefStatement) {
+	typ := c.info.Enums[n]
+	for _, v := range n.Variants {
+		// Duplicate variants have been reported by the resolver.
+		if typ.VariantIndex(v.Name.Value) >= 0 {
+			continue
+		}
+		variant := &Variant{Name: v.Name.Value, Fields: make([]Type, len(v.Fields))}
+		for i := range v.Fields {
+			variant.Fields[i] = c.fieldType(v.FieldTypes, i)
+		}
+		typ.Variants = append(typ.Variants, variant)
+		if len(v.Fields) == 0 {
+			c.info.Defs[v.Name] = typ
+		} else {
+			c.info.Defs[v.Name] = &Func{Params: variant.Fields, Result: typ}
+		}
+	}
+	c.info.Defs[n.Name] = typ
+}
+
+func (c *Checker) stmt(n parser.Statement) {
+	switch n := n.(type) {
+	case *parser.LetStatement:
+		c.letStmt(n)
+	case *parser.AssignStatement:
+		c.assignStmt(n)
+	case *parser.FunDefStatement:
+		c.funDefStmt(n)
+	case *parser.BlockStatement:
+		c.blockStmt(n)
+	case *parser.IfStatement:
+		c.ifStmt(n)
+	case *parser.WhileStatement:
+		c.whileStmt(n)
+	case *parser.ForStatement:
+		c.forStmt(n)
+	case *parser.ReturnStatement:
+		c.returnStmt(n)
+	case *parser.ExpressionStatement:
+		c.exprOrVoid(n.Value)
+	}
+}
+
+// letStmt declares the variable with the type of its annotation or the
+// type of its value if it has none.
+func (c *Checker) letStmt(n *parser.LetStatement) {
+	typ := c.expr(n.Value)
+	if n.Type != nil {
+		t := c.typeExpr(n.Type)
+		typ = c.convert(n.Value, typ, t)
+		if !c.assignable(typ, t, diag.NodeLocation(n.Value)) {
+			c.explain(c.error(n.Name.Token, "T0008", "Cannot assign [%s] to [%s] of type [%s].", typ, n.Name, t), typ)
+		}
+		typ = t
+	}
+	c.declare(n.Name, typ)
+}
+
+func (c *Checker) assignStmt(n *parser.AssignStatement) {
+	typ := c.expr(n.Value)
+	var target Type
+	switch t := n.Target.(type) {
+	case *parser.Identifier:
+		target = c.typeOf(t)
+		c.info.Types[t] = target
+	case *parser.IndexExpression, *parser.SelectorExpression:
+		target = c.expr(t)
+	}
+	typ = c.convert(n.Value, typ, target)
+	// A compound assignment assigns the result of the operator.
+	if n.Operator != "" {
+		typ = c.binaryOp(n.Token, n.Operator, target, typ)
+	}
+	if !c.assignable(typ, target, diag.NodeLocation(n.Value)) {
+		c.explain(c.error(n.Token, "T0008", "Cannot assign [%s] to [%s] of type [%s].", typ, n.Target, target), target, typ)
+	}
+}
+
+// funDefStmt checks the body of the function unless it has been checked
+// on demand already. A top-level function is checked together with the
+// functions of its group and generalized afterwards.
+func (c *Checker) funDefStmt(n *parser.FunDefStatement) {
+	if c.state[n] != unchecked {
+		return
+	}
+	if n.Extern {
+		c.state[n] = checked
+		c.linkage(n, c.signature(n))
+		return
+	}
+	group, ok := c.group[n]
+	if !ok {
+		group = []*parser.FunDefStatement{n}
+	}
+	for _, m := range group {
+		c.state[m] = checking
+		c.signature(m)
+	}
+	for _, m := range group {
+		c.function(&function{name: m.Name.Value, sig: c.signature(m)}, m.Params, m.Body)
+		c.state[m] = checked
+	}
+	// The result of a function that only returns the results of recursive
+	// calls is unknown.
+	for _, m := range group {
+		sig := c.signature(m)
+		if v, ok := Resolve(sig.Result).(*Var); ok && !occurs(v, sig.Params) {
+			c.error(m.Name.Token, "T0011", "Cannot infer the return type of [%s].", m.Name.Value)
+			v.bound = Invalid
+		}
+	}
+	if ok {
+		c.generalize(group)
+	}
+	for _, m := range group {
+		if m.Export {
+			c.export(m)
+		}
+	}
+}
+
+// export checks the signature of an exported function. An exported
+// function has a single symbol and cannot be generic.
+func (c *Checker) export(n *parser.FunDefStatement) {
+	if _, ok := c.info.Schemes[n]; ok {
+		c.error(n.Name.Token, "T0032", "Exported function [%s] cannot be generic.", n.Name.Value)
+		return
+	}
+	c.linkage(n, c.signature(n))
+}
+
+// linkage checks that the parameters and the result of an extern or
+// exported function can be passed to and from C.
+func (c *Checker) linkage(n *parser.FunDefStatement, sig *Func) {
+	for i, param := range n.Params {
+		if !cCompatible(sig.Params[i]) {
+			c.error(param.Token, "T0031", "Parameter [%s] of type [%s] is not compatible with C.", param.Value, sig.Params[i])
+		}
+	}
+	if !cCompatible(sig.Result) {
+		var at parser.Node = n.Name
+		if n.Result != nil {
+			at = n.Result
+		}
+		c.nodeError(at, "T0031", "Result type [%s] of [%s] is not compatible with C.", sig.Result, n.Name.Value)
+	}
+}
+
+// cCompatible returns true iff values of the type can be passed to and
+// from C. Integers of up to 64 bits, floating-point numbers, booleans and
+// strings are compatible. Unknown types that are not type parameters
+// default to int.
+func cCompatible(t Type) bool {
+	switch t := Resolve(t).(type) {
+	case *Var:
+		return !t.generic
+	case *Basic:
+		return t.bits <= 64
+	}
+	return false
+}
+
+func (c *Checker) funLit(n *parser.FunctionLiteral) Type {
+	sig := &Func{Params: c.params(n.ParamTypes, len(n.Params)), Result: c.result(n.Result)}
+	c.function(&function{sig: sig}, n.Params, n.Body)
+	return sig
+}
+
+// function checks the body of a function definition or function literal.
+func (c *Checker) function(f *function, params []*parser.Identifier, body *parser.BlockStatement) {
+	// The function may be checked on demand from within another function.
+	fun := c.fun
+	c.fun = f
+	for i, param := range params {
+		c.declare(param, f.sig.Params[i])
+	}
+	c.blockStmt(body)
+	c.fun = fun
+
+	// Functions without return statements are reported by the code
+	// generator.
+	if v, ok := Resolve(f.sig.Result).(*Var); ok && !f.returns {
+		v.bound = Int
+	}
+}
+
+// occurs returns true iff the type variable occurs in one of the types.
+func occurs(v *Var, types []Type) bool {
+	for _, t := range types {
+		for _, w := range freeVars(t, nil) {
+			if w == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Checker) blockStmt(n *parser.BlockStatement) {
+	c.stmts(n.Statements)
+}
+
+func (c *Checker) ifStmt(n *parser.IfStatement) {
+	c.condition(n.Condition)
+	c.stmt(n.Consequence)
+	if n.Alternative != nil {
+		c.stmt(n.Alternative)
+	}
+}
+
+func (c *Checker) whileStmt(n *parser.WhileStatement) {
+	c.condition(n.Condition)
+	c.blockStmt(n.Body)
+}
+
+func (c *Checker) forStmt(n *parser.ForStatement) {
+	if n.Init != nil {
+		c.stmt(n.Init)
+	}
+	if n.Condition != nil {
+		c.condition(n.Condition)
+	}
+	if n.Post != nil {
+		c.stmt(n.Post)
+	}
+	c.blockStmt(n.Body)
+}
+
+func (c *Checker) returnStmt(n *parser.ReturnStatement) {
+	typ := c.expr(n.Value)
+	if c.fun == nil {
+		return
+	}
+	sig := c.fun.sig
+	c.fun.returns = true
+	typ = c.convert(n.Value, typ, sig.Result)
+	if !c.assignable(typ, sig.Result, diag.NodeLocation(n.Value)) {
+		c.explain(c.error(n.Token, "T0007", "%s returns [%s] but got [%s].", c.fun, sig.Result, typ), sig.Result, typ)
+	}
+}
+
+func (c *Checker) condition(n parser.Expression) {
+	typ := c.expr(n)
+	if !c.assignable(typ, Bool, diag.NodeLocation(n)) {
+		c.explain(c.nodeError(n, "T0002", "Condition must be of type [%s] but is [%s].", Bool, typ), typ)
+	}
+}
+
+// expr returns the type of the expression. Calls of void functions have no
+// value.
+func (c *Checker) expr(n parser.Expression) Type {
+	typ := c.exprOrVoid(n)
+	if Resolve(typ) == Void {
+		c.nodeError(n, "T0034", "[%s] does not return a value.", n)
+		return Invalid
+	}
+	return typ
+}
+
+// exprOrVoid returns the type of the expression statement which may be
+// void.
+func (c *Checker) exprOrVoid(n parser.Expression) Type {
+	typ := c.exprType(n)
+	c.info.Types[n] = typ
+	return typ
+}
+
+func (c *Checker) exprType(n parser.Expression) Type {
+	switch n := n.(type) {
+	case *parser.Integer:
+		if n.Value.BitLen() > 64 {
+			c.wide = append(c.wide, n)
+		}
+		return Int
+	case *parser.Float:
+		return F64
+	case *parser.Boolean:
+		return Bool
+	case *parser.String:
+		return String
+	case *parser.Identifier:
+		return c.identifier(n)
+	case *parser.CallExpression:
+		return c.callExpr(n)
+	case *parser.FunctionLiteral:
+		return c.funLit(n)
+	case *parser.ArrayLiteral:
+		return c.arrayLit(n)
+	case *parser.IndexExpression:
+		return c.indexExpr(n)
+	case *parser.StructLiteral:
+		return c.structLit(n)
+	case *parser.SelectorExpression:
+		if id := c.names.Qualified(n); id != nil {
+			return c.expr(id)
+		}
+		return c.selector(n)
+	case *parser.MatchExpression:
+		return c.match(n)
+	case *parser.BinaryExpression:
+		return c.binaryExpr(n)
+	case *parser.PrefixExpression:
+		return c.prefixExpr(n)
+	}
+	return Invalid
+}
+
+func (c *Checker) identifier(n *parser.Identifier) Type {
+	obj := c.names.Uses[n]
+	if obj == nil {
+		return Invalid
+	}
+	switch obj.Kind {
+	case resolve.Builtin:
+		c.error(n.Token, "T0010", "Function [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	case resolve.Func:
+		if obj.Fun.Variadic {
+			c.error(n.Token, "T0010", "Variadic function [%s] cannot be used as a value.", n.Value)
+			return Invalid
+		}
+		return c.funcType(n, obj)
+	case resolve.Struct:
+		c.error(n.Token, "T0010", "Struct [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	case resolve.Enum:
+		c.error(n.Token, "T0010", "Enum [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	case resolve.Type:
+		c.error(n.Token, "T0010", "Type [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	case resolve.Module:
+		c.error(n.Token, "T0010", "Module [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	case resolve.Variant:
+		// Variants without fields are values of their enum.
+		if enum, ok := c.info.Defs[obj.Variant.Name].(*Enum); ok {
+			return enum
+		}
+		c.error(n.Token, "T0010", "Variant [%s] cannot be used as a value.", n.Value)
+		return Invalid
+	}
+	return c.typeOf(n)
+}
+
+// funcType returns the signature of the function the identifier refers
+// to. The function is checked on demand if it has not been checked yet.
+// Generic functions are instantiated unless they are used within their
+// own group.
+func (c *Checker) funcType(n *parser.Identifier, obj *resolve.Object) *Func {
+	c.funDefStmt(obj.Fun)
+	if s, ok := c.info.Schemes[obj.Fun]; ok {
+		return c.instantiate(n, s)
+	}
+	return c.signature(obj.Fun)
+}
+
+func (c *Checker) callExpr(n *parser.CallExpression) Type {
+	if obj := c.builtin(n.Function); obj != nil {
+		return c.builtinCall(obj, n)
+	}
+	if sig := c.constructor(n.Function); sig != nil {
+		return c.variantCall(sig, n)
+	}
+	if typ := c.conversionType(n.Function); typ != nil {
+		return c.conversion(typ, n)
+	}
+	if sig := c.variadic(n.Function); sig != nil {
+		return c.variadicCall(sig, n)
+	}
+	sig := c.callee(n.Function, len(n.Args))
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.expr(arg)
+	}
+	if sig == nil {
+		return Invalid
+	}
+	name := n.Function.String()
+	if len(args) != len(sig.Params) {
+		// Wrong numbers of arguments of named functions are reported by
+		// the resolver.
+		if !c.isFunc(n.Function) {
+			c.error(n.Token, "T0005", "Function [%s] expects [%d] arguments but got [%d].", name, len(sig.Params), len(args))
+		}
+		return sig.Result
+	}
+	c.args(name, sig, n.Args, args)
+	return sig.Result
+}
+
+// args checks that the arguments are of the types of the parameters.
+func (c *Checker) args(name string, sig *Func, ns []parser.Expression, args []Type) {
+	for i, arg := range args {
+		arg = c.convert(ns[i], arg, sig.Params[i])
+		if !c.assignable(arg, sig.Params[i], diag.NodeLocation(ns[i])) {
+			d := c.nodeError(ns[i], "T0006", "Argument [%d] of [%s] must be of type [%s] but is [%s].", i+1, name, sig.Params[i], arg)
+			c.explain(d, sig.Params[i], arg)
+		}
+	}
+}
+
+// variadic returns the signature of the variadic function the callee
+// refers to or nil if the callee is not a variadic function.
+func (c *Checker) variadic(n parser.Expression) *Func {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	obj := c.names.Uses[id]
+	if obj == nil || obj.Kind != resolve.Func || !obj.Fun.Variadic {
+		return nil
+	}
+	return c.funcType(id, obj)
+}
+
+// variadicCall checks a call of a variadic extern function. The additional
+// arguments are passed to C as they are and must be of a type that is
+// compatible with C.
+func (c *Checker) variadicCall(sig *Func, n *parser.CallExpression) Type {
+	c.info.Types[n.Function] = sig
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.expr(arg)
+	}
+	// Missing arguments have been reported by the resolver.
+	fixed := len(sig.Params)
+	if len(args) < fixed {
+		return sig.Result
+	}
+	name := n.Function.String()
+	c.args(name, sig, n.Args[:fixed], args[:fixed])
+	for i, arg := range args[fixed:] {
+		if !cCompatible(arg) {
+			c.nodeError(n.Args[fixed+i], "T0031", "Argument [%d] of [%s] of type [%s] is not compatible with C.", fixed+i+1, name, arg)
+		}
+	}
+	return sig.Result
+}
+
+// callee returns the signature of the called function or nil if the
+// callee is not a function. A callee of unknown type is a function that
+// takes the given number of arguments.
+func (c *Checker) callee(n parser.Expression, args int) *Func {
+	typ := c.expr(n)
+	if v, ok := Resolve(typ).(*Var); ok {
+		sig := &Func{Params: make([]Type, args), Result: c.fresh()}
+		for i := range sig.Params {
+			sig.Params[i] = c.fresh()
+		}
+		c.bind(v, sig, diag.NodeLocation(n))
+	}
+	if sig, ok := Resolve(typ).(*Func); ok {
+		return sig
+	}
+	if Resolve(typ) != Invalid {
+		c.explain(c.nodeError(n, "T0004", "[%s] is not a function.", n), typ)
+	}
+	return nil
+}
+
+// isFunc returns true iff the expression refers to a named function.
+func (c *Checker) isFunc(n parser.Expression) bool {
+	id := c.names.Name(n)
+	if id == nil {
+		return false
+	}
+	obj := c.names.Uses[id]
+	return obj != nil && obj.Kind == resolve.Func
+}
+
+// builtin returns the builtin function the callee refers to or nil if the
+// callee is not a builtin function.
+func (c *Checker) builtin(n parser.Expression) *resolve.Object {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Builtin {
+		return obj
+	}
+	return nil
+}
+
+// constructor returns the constructor of the variant the callee refers to
+// or nil if the callee is not a variant with fields.
+func (c *Checker) constructor(n parser.Expression) *Func {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	obj := c.names.Uses[id]
+	if obj == nil || obj.Kind != resolve.Variant {
+		return nil
+	}
+	sig, _ := c.info.Defs[obj.Variant.Name].(*Func)
+	return sig
+}
+
+// variantCall checks a call of the constructor of a variant. It returns
+// the enum of the variant.
+func (c *Checker) variantCall(sig *Func, n *parser.CallExpression) Type {
+	c.info.Types[n.Function] = sig
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.expr(arg)
+	}
+	name := n.Function.String()
+	if len(args) != len(sig.Params) {
+		c.error(n.Token, "T0005", "Variant [%s] expects [%d] values but got [%d].", name, len(sig.Params), len(args))
+		return sig.Result
+	}
+	c.args(name, sig, n.Args, args)
+	return sig.Result
+}
+
+// conversionType returns the type the callee refers to or nil if the
+// callee is not the name of a predeclared type.
+func (c *Checker) conversionType(n parser.Expression) Type {
+	id := c.names.Name(n)
+	if id == nil {
+		return nil
+	}
+	if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Type {
+		return Predeclared[obj.Name]
+	}
+	return nil
+}
+
+// conversion checks the conversion of a single value to the type. Integers
+// and floating-point numbers can be converted to any integer or
+// floating-point type. Other values can only be converted to their own
+// type.
+func (c *Checker) conversion(typ Type, n *parser.CallExpression) Type {
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.expr(arg)
+	}
+	c.info.Types[n.Function] = &Func{Params: args, Result: typ}
+	if len(args) != 1 {
+		c.error(n.Token, "T0005", "Conversion to [%s] expects [%d] values but got [%d].", typ, 1, len(args))
+		return typ
+	}
+	// The value of a conversion of unknown type is an int.
+	arg := args[0]
+	if _, ok := Resolve(arg).(*Var); ok {
+		c.unify(arg, Int, diag.NodeLocation(n.Args[0]))
+	}
+	if Resolve(arg) != Invalid && !Identical(arg, typ) && !(IsNumeric(arg) && IsNumeric(typ)) {
+		c.explain(c.nodeError(n.Args[0], "T0028", "Cannot convert [%s] of type [%s] to [%s].", n.Args[0], arg, typ), arg)
+	}
+	return typ
+}
+
+// builtinCall checks a call of a builtin function. The functions print
+// and println take a single value of a basic type and return the number of
+// bytes written. The function len takes an array or a string and returns
+// its length.
+func (c *Checker) builtinCall(obj *resolve.Object, n *parser.CallExpression) Type {
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.expr(arg)
+	}
+	c.info.Types[n.Function] = &Func{Params: args, Result: Int}
+	if len(args) != 1 {
+		c.error(n.Token, "T0005", "Function [%s] expects [%d] arguments but got [%d].", obj.Name, 1, len(args))
+		return Int
+	}
+	// Values of unknown type are arrays if their length is taken and ints
+	// if they are printed.
+	arg := Resolve(args[0])
+	if v, ok := arg.(*Var); ok {
+		if obj.Name == "len" {
+			arg = &Array{Elem: c.fresh()}
+		} else {
+			arg = Int
+		}
+		c.bind(v, arg, diag.NodeLocation(n.Args[0]))
+	}
+	switch obj.Name {
+	case "len":
+		if _, ok := arg.(*Array); !ok && arg != String && arg != Invalid {
+			c.explain(c.nodeError(n.Args[0], "T0016", "Cannot take the length of values of type [%s].", args[0]), args[0])
+		}
+	default:
+		if _, ok := arg.(*Basic); !ok {
+			c.explain(c.nodeError(n.Args[0], "T0012", "Cannot print values of type [%s].", args[0]), args[0])
+		}
+	}
+	return Int
+}
+
+// arrayLit checks that all elements of the array literal are of the type
+// of the first element. The element type of empty arrays is unknown.
+func (c *Checker) arrayLit(n *parser.ArrayLiteral) Type {
+	var elem Type = c.fresh()
+	for i, e := range n.Elements {
+		typ := c.expr(e)
+		if i == 0 {
+			elem = typ
+		} else if !c.assignable(c.convert(e, typ, elem), elem, diag.NodeLocation(e)) {
+			c.explain(c.nodeError(e, "T0013", "Element [%d] of the array must be of type [%s] but is [%s].", i+1, elem, typ), elem, typ)
+		}
+	}
+	if Resolve(elem) == Invalid {
+		return Invalid
+	}
+	return &Array{Elem: elem}
+}
+
+// indexExpr checks the index expression. A value of unknown type that is
+// indexed is an array.
+func (c *Checker) indexExpr(n *parser.IndexExpression) Type {
+	typ := c.expr(n.Left)
+	index := c.expr(n.Index)
+	if !c.assignable(index, Int, diag.NodeLocation(n.Index)) {
+		c.explain(c.nodeError(n.Index, "T0015", "Index must be of type [%s] but is [%s].", Int, index), index)
+	}
+	if v, ok := Resolve(typ).(*Var); ok {
+		c.bind(v, &Array{Elem: c.fresh()}, diag.NodeLocation(n.Left))
+	}
+	if Resolve(typ) == Invalid {
+		return Invalid
+	}
+	arr, ok := Resolve(typ).(*Array)
+	if !ok {
+		c.explain(c.nodeError(n.Left, "T0014", "Cannot index [%s] of type [%s].", n.Left, typ), typ)
+		return Invalid
+	}
+	return arr.Elem
+}
+
+// structLit checks that the struct literal initializes every field of the
+// struct exactly once with a value of the type of the field.
+func (c *Checker) structLit(n *parser.StructLiteral) Type {
+	values := make([]Type, len(n.Values))
+	for i, v := range n.Values {
+		values[i] = c.expr(v)
+	}
+	obj := c.names.Uses[n.Name]
+	if obj == nil || obj.Kind != resolve.Struct {
+		return Invalid
+	}
+	typ := c.info.Structs[obj.Struct]
+	c.info.Types[n.Name] = typ
+	init := map[string]bool{}
+	for i, id := range n.Fields {
+		j := typ.FieldIndex(id.Value)
+		switch {
+		case j < 0:
+			c.error(id.Token, "T0017", "Struct [%s] has no field [%s].", typ, id.Value)
+		case init[id.Value]:
+			c.error(id.Token, "T0020", "Field [%s] is already initialized.", id.Value)
+		case !c.assignable(c.convert(n.Values[i], values[i], typ.Fields[j].Type), typ.Fields[j].Type, diag.NodeLocation(n.Values[i])):
+			d := c.nodeError(n.Values[i], "T0021", "Field [%s] of [%s] must be of type [%s] but is [%s].", id.Value, typ, typ.Fields[j].Type, values[i])
+			c.explain(d, values[i])
+		}
+		init[id.Value] = true
+	}
+	for _, f := range typ.Fields {
+		if !init[f.Name] {
+			c.error(n.Name.Token, "T0019", "Missing field [%s] in literal of struct [%s].", f.Name, typ)
+		}
+	}
+	return typ
+}
+
+// selector checks the selection of a field. A value of unknown type whose
+// field is selected is of the first struct type that declares the field.
+func (c *Checker) selector(n *parser.SelectorExpression) Type {
+	typ := c.expr(n.Left)
+	if v, ok := Resolve(typ).(*Var); ok {
+		if s := c.structWithField(n.Field.Value); s != nil {
+			c.bind(v, s, diag.NodeLocation(n))
+		}
+	}
+	if Resolve(typ) == Invalid {
+		return Invalid
+	}
+	s, ok := Resolve(typ).(*Struct)
+	if !ok {
+		c.explain(c.nodeError(n.Left, "T0018", "Cannot select field [%s] of [%s] of type [%s].", n.Field.Value, n.Left, typ), typ)
+		return Invalid
+	}
+	i := s.FieldIndex(n.Field.Value)
+	if i < 0 {
+		c.error(n.Field.Token, "T0017", "Struct [%s] has no field [%s].", s, n.Field.Value)
+		return Invalid
+	}
+	return s.Fields[i].Type
+}
+
+// match checks that the arms of the match expression are reachable, that
+// they cover every variant of the enum and that their bodies are of the
+// same type. The type of the match is the type of the bodies. A value of
+// unknown type that is matched is of the enum of the variant of the first
+// arm.
+func (c *Checker) match(n *parser.MatchExpression) Type {
+	typ := c.expr(n.Value)
+	if v, ok := Resolve(typ).(*Var); ok {
+		if enum := c.enumOfArms(n); enum != nil {
+			c.bind(v, enum, diag.NodeLocation(n.Value))
+		}
+	}
+	enum, _ := Resolve(typ).(*Enum)
+	if enum == nil && Resolve(typ) != Invalid {
+		c.explain(c.nodeError(n.Value, "T0022", "Cannot match [%s] of type [%s].", n.Value, typ), typ)
+	}
+	var res Type = Invalid
+	wildcard := false
+	matched := map[string]bool{}
+	for i, arm := range n.Arms {
+		if wildcard || matched[arm.Pattern.Value] {
+			c.error(arm.Pattern.Token, "T0025", "Unreachable pattern [%s].", arm.Pattern.Value)
+		}
+		if arm.IsWildcard() {
+			wildcard = true
+		} else {
+			matched[arm.Pattern.Value] = true
+		}
+		c.pattern(enum, arm)
+		body := c.expr(arm.Body)
+		if i == 0 {
+			res = body
+		} else if !c.assignable(c.convert(arm.Body, body, res), res, diag.NodeLocation(arm.Body)) {
+			c.explain(c.nodeError(arm.Body, "T0026", "Arm [%d] of the match must be of type [%s] but is [%s].", i+1, res, body), res, body)
+		}
+	}
+	if enum != nil && !wildcard {
+		missing := []string{}
+		for _, v := range enum.Variants {
+			if !matched[v.Name] {
+				missing = append(missing, v.Name)
+			}
+		}
+		if len(missing) > 0 {
+			c.error(n.Token, "T0027", "Match is not exhaustive. Missing variants [%s].", strings.Join(missing, ", "))
+		}
+	}
+	return res
+}
+
+// pattern declares the bindings of the arm. The bindings are of the types
+// of the fields of the variant.
+func (c *Checker) pattern(enum *Enum, arm *parser.MatchArm) {
+	var fields []Type
+	obj := c.names.Uses[arm.Pattern]
+	if enum != nil && obj != nil && obj.Kind == resolve.Variant {
+		if c.info.Enums[obj.Enum] != enum {
+			c.error(arm.Pattern.Token, "T0023", "Variant [%s] does not belong to [%s].", arm.Pattern.Value, enum)
+		} else {
+			fields = enum.Variants[enum.VariantIndex(arm.Pattern.Value)].Fields
+			if len(arm.Bindings) != len(fields) {
+				c.error(arm.Pattern.Token, "T0024", "Variant [%s] has [%d] fields but the pattern binds [%d].", arm.Pattern.Value, len(fields), len(arm.Bindings))
+			}
+		}
+	}
+	for i, id := range arm.Bindings {
+		var typ Type = Invalid
+		if i < len(fields) {
+			typ = fields[i]
+		}
+		c.declare(id, typ)
+	}
+}
+
+func (c *Checker) binaryExpr(n *parser.BinaryExpression) Type {
+	l := c.expr(n.Left)
+	r := c.expr(n.Right)
+	// Integer constants take the type of the other operand.
+	l = c.convert(n.Left, l, r)
+	r = c.convert(n.Right, r, l)
+	return c.binaryOp(n.Token, n.Operator, l, r)
+}
+
+// binaryOp returns the type of the result of the operator applied to
+// operands of types l and r. Errors are reported at the token. Operands of
+// unknown type are of the type of the other operand. Operands of the
+// logical operators are bools and operands of other operators are ints if
+// the types of both are unknown.
+func (c *Checker) binaryOp(tok token.Token, op token.TokenType, l, r Type) Type {
+	at := diag.TokenLocation(tok)
+	c.unknown(l, r, at)
+	c.unknown(r, l, at)
+	if op == token.CONJ || op == token.DISJ {
+		c.unknown(l, Bool, at)
+		c.unknown(r, Bool, at)
+	} else {
+		c.unknown(l, Int, at)
+		c.unknown(r, Int, at)
+	}
+	if Resolve(l) == Invalid || Resolve(r) == Invalid {
+		return Invalid
+	}
+	switch op {
+	case token.PLUS, token.MINUS, token.TIMES, token.DIV, token.MOD, token.POW:
+		if IsNumeric(l) && Identical(l, r) {
+			return l
+		}
+	case token.AND, token.OR, token.XOR, token.NAND, token.NOR, token.XNOR,
+		token.SLL, token.SRL, token.SRA, token.ROL, token.ROR:
+		if IsInteger(l) && Identical(l, r) {
+			return l
+		}
+	case token.LT, token.LE, token.GT, token.GE:
+		if IsNumeric(l) && Identical(l, r) {
+			return Bool
+		}
+	case token.EQU, token.NEQ:
+		if Identical(l, r) && (IsNumeric(l) || Resolve(l) == Bool) {
+			return Bool
+		}
+	case token.CONJ, token.DISJ:
+		if Resolve(l) == Bool && Resolve(r) == Bool {
+			return Bool
+		}
+	}
+	c.explain(c.error(tok, "T0001", "Operator [%s] is not defined for [%s] and [%s].", op, l, r), l, r)
+	return Invalid
+}
+
+// unknown binds the type to t if it is unknown.
+func (c *Checker) unknown(typ, t Type, at diag.Location) {
+	if v, ok := Resolve(typ).(*Var); ok {
+		c.bind(v, t, at)
+	}
+}
+
+// prefixExpr checks the prefix operator. The operand of ! is a bool and
+// the operands of - and ~ are ints if their type is unknown.
+func (c *Checker) prefixExpr(n *parser.PrefixExpression) Type {
+	v := c.expr(n.Value)
+	if n.Operator == token.NOT {
+		c.unknown(v, Bool, diag.TokenLocation(n.Token))
+	} else {
+		c.unknown(v, Int, diag.TokenLocation(n.Token))
+	}
+	if Resolve(v) == Invalid {
+		return Invalid
+	}
+	switch n.Operator {
+	case token.MINUS:
+		if IsNumeric(v) {
+			return v
+		}
+	case token.INV:
+		if IsInteger(v) {
+			return v
+		}
+	case token.NOT:
+		if Resolve(v) == Bool {
+			return Bool
+		}
+	}
+	c.explain(c.error(n.Token, "T0001", "Operator [%s] is not defined for [%s].", n.Operator, v), v)
+	return Invalid
+}
+
+// convert gives an integer constant the integer type t and a
+// floating-point constant the floating-point type t. Constants are
+// literals and the results of arithmetic and bitwise operators on
+// constants. Integer constants are of type int and floating-point
+// constants are of type f64 unless they are used where a value of another
+// type is expected. The elements of an array literal are converted to the
+// element type. convert returns the type of the expression after the
+// conversion.
+func (c *Checker) convert(n parser.Expression, typ, t Type) Type {
+	if lit, ok := n.(*parser.ArrayLiteral); ok {
+		return c.convertArray(lit, typ, t)
+	}
+	t = Resolve(t)
+	from := Resolve(typ)
+	if from == t || !isConstant(n) || !(from == Int && IsInteger(t) || from == F64 && IsFloat(t)) {
+		return typ
+	}
+	c.setConstantType(n, t)
+	return t
+}
+
+// convertArray converts the elements of the array literal to the element
+// type of t.
+func (c *Checker) convertArray(n *parser.ArrayLiteral, typ, t Type) Type {
+	arr, ok := Resolve(t).(*Array)
+	if !ok || len(n.Elements) == 0 {
+		return typ
+	}
+	for _, e := range n.Elements {
+		if !Identical(c.convert(e, c.info.Types[e], arr.Elem), arr.Elem) {
+			return typ
+		}
+	}
+	c.info.Types[n] = t
+	return t
+}
+
+// setConstantType sets the type of the integer constant and its operands.
+// Literals that do not fit into the type are reported.
+func (c *Checker) setConstantType(n parser.Expression, t Type) {
+	c.constantType(n, t, false)
+}
+
+// constantType sets the type of the constant. Literals are checked with
+// their sign: neg is true iff the constant is negated by an odd number of
+// enclosing minus operators.
+func (c *Checker) constantType(n parser.Expression, t Type, neg bool) {
+	c.info.Types[n] = t
+	switch n := n.(type) {
+	case *parser.Integer:
+		if !fits(n.Value, neg, t.(*Basic)) {
+			value := n.Token.Literal
+			if neg {
+				value = "-" + value
+			}
+			c.error(n.Token, "T0029", "Constant [%s] overflows [%s].", value, t)
+		}
+	case *parser.Float:
+		if t == F32 && math.Abs(n.Value) > math.MaxFloat32 {
+			c.error(n.Token, "T0029", "Constant [%s] overflows [%s].", n, t)
+		}
+	case *parser.PrefixExpression:
+		c.constantType(n.Value, t, n.Operator == token.MINUS && !neg)
+	case *parser.BinaryExpression:
+		c.constantType(n.Left, t, false)
+		c.constantType(n.Right, t, false)
+	}
+}
+
+// wideInts reports the integer literals of more than 64 bits that are not
+// converted to a 128 bit type. Literals of up to 64 bits are allowed for
+// int and keep their bits.
+func (c *Checker) wideInts() {
+	for _, n := range c.wide {
+		if c.info.Types[n] == Int {
+			c.error(n.Token, "T0029", "Constant [%s] overflows [%s].", n.Token.Literal, Int)
+		}
+	}
+}
+
+// isConstant returns true iff the expression is an integer or a
+// floating-point constant.
+func isConstant(n parser.Expression) bool {
+	switch n := n.(type) {
+	case *parser.Integer, *parser.Float:
+		return true
+	case *parser.PrefixExpression:
+		return n.Operator != token.NOT && isConstant(n.Value)
+	case *parser.BinaryExpression:
+		switch n.Operator {
+		case token.EQU, token.NEQ, token.LT, token.LE, token.GT, token.GE, token.CONJ, token.DISJ:
+			return false
+		}
+		return isConstant(n.Left) && isConstant(n.Right)
+	}
+	return false
+}
+
+// fits returns true iff the integer with the magnitude and the sign is in
+// the range of the signed or unsigned integer type.
+func fits(mag *big.Int, neg bool, t *Basic) bool {
+	switch {
+	case t.unsigned && neg:
+		return mag.Sign() == 0
+	case t.unsigned:
+		return mag.BitLen() <= t.bits
+	case neg:
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.bits-1))
+		return mag.Cmp(limit) <= 0
+	}
+	return mag.BitLen() < t.bits
+}
+
+// assignable returns true iff a value of type v may be used where a value
+// of type t is expected. The types are unified with a constraint at the
+// span. Invalid types are assignable to any type to avoid follow-up
+// errors.
+func (c *Checker) assignable(v, t Type, at diag.Location) bool {
+	return c.unify(v, t, at)
+}
@@ -0,0 +1,346 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/lexer"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+	"github.com/mhoertnagl/donkey/types"
+)
+
+func TestExpressionTypes(t *testing.T) {
+	testType(t, "1;", "int")
+	testType(t, "true;", "bool")
+	testType(t, "-1;", "int")
+	testType(t, "~1;", "int")
+	testType(t, "!false;", "bool")
+	testType(t, "1 + 2 * 3;", "int")
+	testType(t, "1 <<> 2;", "int")
+	testType(t, "1 % 2 ** 3;", "int")
+	testType(t, "1 ~& 2 ~| 3 ~^ 4;", "int")
+	testType(t, "1 < 2;", "bool")
+	testType(t, "1 == 2;", "bool")
+	testType(t, "true != false;", "bool")
+	testType(t, "true && 1 < 2;", "bool")
+	testType(t, "let a = true; a;", "bool")
+	testType(t, `"abc";`, "string")
+	testType(t, `let s = "abc"; s;`, "string")
+	testType(t, `println("abc");`, "int")
+	testType(t, `print(1 < 2);`, "int")
+	testType(t, "fn f(a) { return a < 1; } f(0);", "bool")
+	testType(t, "g(0); fn g(a) { return a; }", "int")
+	testType(t, "fn f(a) { if a == 0 { return 1; } return f(a - 1); } f(2);", "int")
+	testType(t, "fn f() { return 1; } let a = f; a;", "fn() -> int")
+	testType(t, "let add = fn(a, b) { return a + b; }; add;", "fn(int, int) -> int")
+	testType(t, "let add = fn(a, b) { return a + b; }; add(1, 2);", "int")
+	testType(t, "fn(a) { return a > 0; }(1);", "bool")
+	testType(t, "let a = true; let f = fn() { return a; }; f();", "bool")
+	testType(t, "fn apply(f, x) { return f(x); } apply(fn(a) { return a * 2; }, 3);", "int")
+	testType(t, "fn adder(a) { return fn(b) { return a + b; }; } adder(1)(2);", "int")
+	testType(t, "[1, 2];", "[int]")
+	testType(t, "[];", "[int]")
+	testType(t, "[[true], [false]];", "[[bool]]")
+	testType(t, `let a = ["a"]; a[0];`, "string")
+	testType(t, "let a = [[1]]; a[0];", "[int]")
+	testType(t, "len([1]);", "int")
+	testType(t, `len("abc");`, "int")
+	testType(t, "let a = [1]; a[0] = 2; a;", "[int]")
+	testType(t, "struct P { x, y } P { y: 1, x: 2 };", "P")
+	testType(t, "struct P { x, y } let p = P { x: 1, y: 2 }; p.y;", "int")
+	testType(t, "let p = P { x: 1, y: 2 }; p.x = 3; p; struct P { x, y }", "P")
+	testType(t, "struct P { x } let ps = [P { x: 1 }]; ps[0].x;", "int")
+	testType(t, "fn f() { struct Q { a } return Q { a: 1 }; } f();", "Q")
+	testType(t, "enum E { A(x), B } A(1);", "E")
+	testType(t, "enum E { A(x), B } B;", "E")
+	testType(t, "enum E { A(x), B } let e = B; match e { A(x) => x > 0, B => false };", "bool")
+	testType(t, "enum E { A(x, y), B } match A(1, 2) { A(x, y) => x + y, _ => 0 };", "int")
+	testType(t, "let a: u8 = 1; a;", "u8")
+	testType(t, "let a: i64 = 1; a;", "int")
+	testType(t, "let a: u8 = 1; a + 2 * 3;", "u8")
+	testType(t, "let a: i16 = 1; -a;", "i16")
+	testType(t, "let a: u32 = 1; 1 < a;", "bool")
+	testType(t, "let a: [u8] = [1, 2]; a;", "[u8]")
+	testType(t, "let a: u8 = 1; [a, 2];", "[u8]")
+	testType(t, "u16(1);", "u16")
+	testType(t, "let a: i8 = 1; u64(a);", "u64")
+	testType(t, "fn f(a: u8) -> u8 { return a; } f(1);", "u8")
+	testType(t, "struct P { x: i32 } P { x: 1 }.x;", "i32")
+	testType(t, "enum E { A(x: u16) } match A(1) { A(x) => x };", "u16")
+	testType(t, "1.5;", "f64")
+	testType(t, "-1.5e3;", "f64")
+	testType(t, "1.5 % 1.0 ** 2.0;", "f64")
+	testType(t, "1.5 < 2.0;", "bool")
+	testType(t, "let a: f32 = 1.5; a * 2.0;", "f32")
+	testType(t, "let a: [f32] = [1.0, 2.5]; a;", "[f32]")
+	testType(t, "f64(1);", "f64")
+	testType(t, "let a = 2.5; u8(a);", "u8")
+	testType(t, "let a: f32 = 1.0; f64(a) + 1.0;", "f64")
+	testType(t, "fn half(x) { return x / 2.0; } half(3.0);", "f64")
+	testType(t, "fn id(x) { return x; } id(1); id(true);", "bool")
+	testType(t, "fn id(x) { return x; } let a = id(1); id(true); a;", "int")
+	testType(t, "fn first(a) { return a[0]; } first([P { x: 1 }]); struct P { x }", "P")
+	testType(t, "fn apply(f, x) { return f(x); } apply(fn(a) { return a > 0; }, 1);", "bool")
+	testType(t, "fn id(x) { return x; } id(id)(2);", "int")
+}
+
+func TestFunctionSignatures(t *testing.T) {
+	testSignature(t, "fn f() { return 1; }", "fn() -> int")
+	testSignature(t, "fn f(a, b) { return a == b; }", "fn(int, int) -> bool")
+	testSignature(t, `fn f() { return "a"; }`, "fn() -> string")
+	testSignature(t, "fn f(a) { return g(a); } fn g(a) { return a > 0; }", "fn(int) -> bool")
+	testSignature(t, "fn apply(f, x) { return f(x); }", "fn(fn('a) -> 'b, 'a) -> 'b")
+	testSignature(t, "fn adder(a) { return fn(b) { return a + b; }; }", "fn(int) -> fn(int) -> int")
+	testSignature(t, "fn f(g) { return fn() { return g(1, 2); }; }", "fn(fn(int, int) -> 'a) -> fn() -> 'a")
+	testSignature(t, "fn f(a, i) { return a[i]; }", "fn(['a], int) -> 'a")
+	testSignature(t, "fn f(a) { return len(a); }", "fn(['a]) -> int")
+	testSignature(t, "fn f() { return [1 < 2]; }", "fn() -> [bool]")
+	testSignature(t, "fn f(p, q) { return p.y + q.x; } struct P { x, y } struct Q { y }", "fn(P, P) -> int")
+	testSignature(t, "fn f(a) { return P { x: a }; } struct P { x }", "fn(int) -> P")
+	testSignature(t, "fn area(s) { return match s { Circle(r) => 3 * r * r, Rect(w, h) => w * h }; } enum Shape { Circle(r), Rect(w, h) }", "fn(Shape) -> int")
+	testSignature(t, "fn f(a: u8, b: i32) -> u16 { return 1; }", "fn(u8, i32) -> u16")
+	testSignature(t, "fn f(a: [bool], g: fn(u8) -> i8) { return g(1); }", "fn([bool], fn(u8) -> i8) -> i8")
+	testSignature(t, "fn f(p: P) { return p; } struct P { x }", "fn(P) -> P")
+	testSignature(t, "fn f(a: u8, b) { return b; }", "fn(u8, 'a) -> 'a")
+	testSignature(t, "fn id(x) { return x; }", "fn('a) -> 'a")
+	testSignature(t, "fn f(a, b) { return [b, b]; }", "fn('a, 'b) -> ['b]")
+	testSignature(t, "fn f(a) { let b = []; return b; }", "fn('a) -> ['b]")
+	testSignature(t, "fn even(n) { if n == 0 { return true; } return odd(n - 1); } fn odd(n) { if n == 0 { return false; } return even(n - 1); }", "fn(int) -> bool")
+	testSignature(t, "fn f(a) { return g(a); } fn g(b) { if true { return b; } return f(b); }", "fn('a) -> 'a")
+}
+
+func TestTypeErrors(t *testing.T) {
+	testErrors(t, "1 + true;", "1:3: error[T0001]: Operator [+] is not defined for [int] and [bool].")
+	testErrors(t, "true < false;", "1:6: error[T0001]: Operator [<] is not defined for [bool] and [bool].")
+	testErrors(t, "1 && true;", "1:3: error[T0001]: Operator [&&] is not defined for [int] and [bool].")
+	testErrors(t, "!1;", "1:1: error[T0001]: Operator [!] is not defined for [int].")
+	testErrors(t, "-true;", "1:1: error[T0001]: Operator [-] is not defined for [bool].")
+	testErrors(t, "if 1 { }", "1:4: error[T0002]: Condition must be of type [bool] but is [int].")
+	testErrors(t, "while 1 + 2 { }", "1:7: error[T0002]: Condition must be of type [bool] but is [int].")
+	testErrors(t, "for ; 0; { }", "1:7: error[T0002]: Condition must be of type [bool] but is [int].")
+	testErrors(t, "let a = 1; a(2);", "1:12: error[T0004]: [a] is not a function.")
+	testErrors(t, "fn f(a) { return a + 1; } f(true);", "1:29: error[T0006]: Argument [1] of [f] must be of type [int] but is [bool].")
+	testErrors(t, "fn f(a) { if a < 0 { return 1; } return false; }", "1:34: error[T0007]: Function [f] returns [int] but got [bool].")
+	testErrors(t, "let a = 1; a = true;", "1:14: error[T0008]: Cannot assign [bool] to [a] of type [int].")
+	testErrors(t, "let a = 1; a += true;", "1:14: error[T0001]: Operator [+] is not defined for [int] and [bool].")
+	testErrors(t, "let a = true; a++;", "1:16: error[T0001]: Operator [+] is not defined for [bool] and [int].")
+	testErrors(t, `let s = "a"; s <<= 1;`, "1:16: error[T0001]: Operator [<<] is not defined for [string] and [int].")
+	testErrors(t, "let f = fn(a) { return a; }; f(1, 2);", "1:31: error[T0005]: Function [f] expects [1] arguments but got [2].")
+	testErrors(t, "fn(a) { if a < 0 { return 1; } return false; };", "1:32: error[T0007]: Anonymous function returns [int] but got [bool].")
+	testErrors(t, "fn apply(f) { return f(1); } apply(2);", "1:36: error[T0006]: Argument [1] of [apply] must be of type [fn(int) -> 'a] but is [int].")
+	testErrors(t, "fn apply(f) { return f(1) + 1; } apply(fn(a) { return a > 0; });", "1:40: error[T0006]: Argument [1] of [apply] must be of type [fn(int) -> int] but is [fn(int) -> bool].")
+	testErrors(t, `"a" + "b";`, `1:5: error[T0001]: Operator [+] is not defined for [string] and [string].`)
+	testErrors(t, `"a" == "b";`, `1:5: error[T0001]: Operator [==] is not defined for [string] and [string].`)
+	testErrors(t, `print(1, 2);`, "1:6: error[T0005]: Function [print] expects [1] arguments but got [2].")
+	testErrors(t, `let a = print;`, "1:9: error[T0010]: Function [print] cannot be used as a value.")
+	testErrors(t, "fn f(a) { return f(a); }", "1:4: error[T0011]: Cannot infer the return type of [f].")
+	testErrors(t, "[1, true, 2];", "1:5: error[T0013]: Element [2] of the array must be of type [int] but is [bool].")
+	testErrors(t, "let a = 1; a[0];", "1:12: error[T0014]: Cannot index [a] of type [int].")
+	testErrors(t, "let a = [1]; a[true];", "1:16: error[T0015]: Index must be of type [int] but is [bool].")
+	testErrors(t, "let a = [1]; a[0] = true;", "1:19: error[T0008]: Cannot assign [bool] to [a[0]] of type [int].")
+	testErrors(t, "len(1);", "1:5: error[T0016]: Cannot take the length of values of type [int].")
+	testErrors(t, "len([1], [2]);", "1:4: error[T0005]: Function [len] expects [1] arguments but got [2].")
+	testErrors(t, "print([1]);", "1:7: error[T0012]: Cannot print values of type [[int]].")
+	testErrors(t, "struct P { x } let a = P;", "1:24: error[T0010]: Struct [P] cannot be used as a value.")
+	testErrors(t, "struct N { next: N }", "1:8: error[T0033]: Struct [N] cannot contain itself.")
+	testErrors(t, "struct A { b: B } struct B { a: A }", "1:8: error[T0033]: Struct [A] cannot contain itself.", "1:26: error[T0033]: Struct [B] cannot contain itself.")
+	testErrors(t, "enum L { Nil, Cons(h, t: L) }", "1:6: error[T0033]: Enum [L] cannot contain itself.")
+	testErrors(t, "struct T { v, kids: [T] } enum L { Nil, Cons(h, t: [L]) }")
+	testErrors(t, "struct P { x } P { x: 1, y: 2 };", "1:26: error[T0017]: Struct [P] has no field [y].")
+	testErrors(t, "struct P { x } let p = P { x: 1 }; p.y;", "1:38: error[T0017]: Struct [P] has no field [y].")
+	testErrors(t, "let a = [1]; a.x;", "1:14: error[T0018]: Cannot select field [x] of [a] of type [[int]].")
+	testErrors(t, "struct P { x, y } P { x: 1 };", "1:19: error[T0019]: Missing field [y] in literal of struct [P].")
+	testErrors(t, "struct P { x } P { x: 1, x: 2 };", "1:26: error[T0020]: Field [x] is already initialized.")
+	testErrors(t, "struct P { x } P { x: true };", "1:23: error[T0021]: Field [x] of [P] must be of type [int] but is [bool].")
+	testErrors(t, "struct P { x } let p = P { x: 1 }; p.x = false;", "1:40: error[T0008]: Cannot assign [bool] to [p.x] of type [int].")
+	testErrors(t, "enum E { A(x) } let a = E;", "1:25: error[T0010]: Enum [E] cannot be used as a value.")
+	testErrors(t, "enum E { A(x) } let a = A;", "1:25: error[T0010]: Variant [A] cannot be used as a value.")
+	testErrors(t, "enum E { A(x) } A(1, 2);", "1:18: error[T0005]: Variant [A] expects [1] values but got [2].")
+	testErrors(t, "enum E { A(x) } A(true);", "1:19: error[T0006]: Argument [1] of [A] must be of type [int] but is [bool].")
+	testErrors(t, "enum E { A } A();", "1:14: error[T0004]: [A] is not a function.")
+	testErrors(t, "match 1 { _ => 1 };", "1:7: error[T0022]: Cannot match [1] of type [int].")
+	testErrors(t, "enum E { A } enum F { B } match A { B => 1, _ => 2 };", "1:37: error[T0023]: Variant [B] does not belong to [E].")
+	testErrors(t, "enum E { A(x) } match A(1) { A => 1 };", "1:30: error[T0024]: Variant [A] has [1] fields but the pattern binds [0].")
+	testErrors(t, "enum E { A, B } match A { A => 1, A => 2, B => 3 };", "1:35: error[T0025]: Unreachable pattern [A].")
+	testErrors(t, "enum E { A, B } match A { _ => 1, B => 2 };", "1:35: error[T0025]: Unreachable pattern [B].")
+	testErrors(t, "enum E { A, B } match A { A => 1, B => true };", "1:40: error[T0026]: Arm [2] of the match must be of type [int] but is [bool].")
+	testErrors(t, "enum E { A, B(x), C } match A { B(x) => x };", "1:23: error[T0027]: Match is not exhaustive. Missing variants [A, C].")
+	testErrors(t, "struct P { x } struct Q { x } fn f(p) { return p.x; } f(Q { x: 1 });", "1:57: error[T0006]: Argument [1] of [f] must be of type [P] but is [Q].")
+	testErrors(t, "let a: bool = 1;", "1:5: error[T0008]: Cannot assign [int] to [a] of type [bool].")
+	testErrors(t, "let a: u8 = 1; let b: i8 = 1; a + b;", "1:33: error[T0001]: Operator [+] is not defined for [u8] and [i8].")
+	testErrors(t, "let a: u8 = 1; let b = 2; a = b;", "1:29: error[T0008]: Cannot assign [int] to [a] of type [u8].")
+	testErrors(t, "fn f() -> u8 { return true; }", "1:16: error[T0007]: Function [f] returns [u8] but got [bool].")
+	testErrors(t, "let a = u8;", "1:9: error[T0010]: Type [u8] cannot be used as a value.")
+	testErrors(t, "u8(1, 2);", "1:3: error[T0005]: Conversion to [u8] expects [1] values but got [2].")
+	testErrors(t, "u8(true);", "1:4: error[T0028]: Cannot convert [true] of type [bool] to [u8].")
+	testErrors(t, "let a: u8 = 256;", "1:13: error[T0029]: Constant [256] overflows [u8].")
+	testErrors(t, "let a: i8 = -1 - 256;", "1:18: error[T0029]: Constant [256] overflows [i8].")
+	testErrors(t, "let a: i8 = -200;", "1:14: error[T0029]: Constant [-200] overflows [i8].")
+	testErrors(t, "let a: i8 = 200;", "1:13: error[T0029]: Constant [200] overflows [i8].")
+	testErrors(t, "let a: u8 = -1;", "1:14: error[T0029]: Constant [-1] overflows [u8].")
+	testErrors(t, "let a: u64 = -(-1) + -9223372036854775808;", "1:23: error[T0029]: Constant [-9223372036854775808] overflows [u64].")
+	testErrors(t, "let a: i8 = -128; let b: i8 = 127; let c: u8 = 255; let d: u8 = -0; let e: i32 = -(-2147483647);")
+	testErrors(t, "let a: u64 = 18446744073709551615; let b: i128 = -18446744073709551615; let c: u32 = ~0;")
+	testErrors(t, "let a: u32 = 4294967296;", "1:14: error[T0029]: Constant [4294967296] overflows [u32].")
+	testErrors(t, "let a: u128 = 18446744073709551616; let b: i128 = -0x8000_0000_0000_0000_0000_0000_0000_0000; let c: u128 = 0xFFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF_FFFF;")
+	testErrors(t, "let a: u64 = 18446744073709551616;", "1:14: error[T0029]: Constant [18446744073709551616] overflows [u64].")
+	testErrors(t, "let a = 18446744073709551616;", "1:9: error[T0029]: Constant [18446744073709551616] overflows [int].")
+	testErrors(t, "let a: i128 = 0x8000_0000_0000_0000_0000_0000_0000_0000;", "1:15: error[T0029]: Constant [0x8000_0000_0000_0000_0000_0000_0000_0000] overflows [i128].")
+	testErrors(t, "let a: i128 = -170141183460469231731687303715884105729;", "1:16: error[T0029]: Constant [-170141183460469231731687303715884105729] overflows [i128].")
+	testErrors(t, "1 + 1.5;", "1:3: error[T0001]: Operator [+] is not defined for [int] and [f64].")
+	testErrors(t, "1.5 & 1.0;", "1:5: error[T0001]: Operator [&] is not defined for [f64] and [f64].")
+	testErrors(t, "~1.5;", "1:1: error[T0001]: Operator [~] is not defined for [f64].")
+	testErrors(t, "let a: f64 = 1;", "1:5: error[T0008]: Cannot assign [int] to [a] of type [f64].")
+	testErrors(t, "let a: f32 = 1.0; let b = 2.0; a + b;", "1:34: error[T0001]: Operator [+] is not defined for [f32] and [f64].")
+	testErrors(t, "let a: f32 = 1e39;", "1:14: error[T0029]: Constant [1e+39] overflows [f32].")
+	testErrors(t, `f32("a");`, `1:5: error[T0028]: Cannot convert ["a"] of type [string] to [f32].`)
+}
+
+func TestInferenceNotes(t *testing.T) {
+	_, _, diags := check(t, "fn f(a) {\n  let b = a + 1;\n  return a && true;\n}")
+	if len(diags.Items()) != 1 {
+		t.Fatalf("Expected [1] error but got %v.", diags.Items())
+	}
+	d := diags.Items()[0]
+	if d.String() != "3:12: error[T0001]: Operator [&&] is not defined for [int] and [bool]." {
+		t.Errorf("Unexpected error [%s].", d)
+	}
+	if len(d.Notes) != 1 || d.Notes[0].Span.Start != (diag.Pos{Line: 2, Col: 13}) {
+		t.Errorf("Expected a note at the constraint of [a] but got %v.", d.Notes)
+	}
+}
+
+func TestNoFollowUpErrors(t *testing.T) {
+	testErrors(t, "(1 + true) * 2;", "1:4: error[T0001]: Operator [+] is not defined for [int] and [bool].")
+	testErrors(t, "let a = -true; if a { }", "1:9: error[T0001]: Operator [-] is not defined for [bool].")
+	testErrors(t, "fn f(a) { return a; } f(!1) + 1;", "1:25: error[T0001]: Operator [!] is not defined for [int].")
+}
+
+func TestExterns(t *testing.T) {
+	testSignature(t, "extern fn putchar(c: i32) -> i32;", "fn(i32) -> i32")
+	testSignature(t, "extern fn printf(format: string, ...) -> i32;", "fn(string, ...) -> i32")
+	testSignature(t, "extern fn abort();", "fn() -> void")
+	testSignature(t, "export fn f(x: u8, y) { return x + u8(y); }", "fn(u8, int) -> u8")
+	testErrors(t, `extern fn printf(format: string, ...) -> i32; printf("%d %f %s\n", i32(1), 2.5, "a"); putchar(65); extern fn putchar(c: i32) -> i32;`)
+	testErrors(t, "extern fn f(c);", "1:13: error[T0030]: Parameter [c] of extern function [f] needs a type annotation.")
+	testErrors(t, "extern fn f(a: [u8]) -> fn() -> int;", "1:13: error[T0031]: Parameter [a] of type [[u8]] is not compatible with C.", "1:25: error[T0031]: Result type [fn() -> int] of [f] is not compatible with C.")
+	testErrors(t, "extern fn f(a: i128);", "1:13: error[T0031]: Parameter [a] of type [i128] is not compatible with C.")
+	testErrors(t, "struct P { x } export fn f(p: P) { return p.x; }", "1:28: error[T0031]: Parameter [p] of type [P] is not compatible with C.")
+	testErrors(t, "export fn id(x) { return x; }", "1:11: error[T0032]: Exported function [id] cannot be generic.")
+	testErrors(t, `extern fn printf(format: string, ...) -> i32; printf("%s", [1]);`, "1:60: error[T0031]: Argument [2] of [printf] of type [[int]] is not compatible with C.")
+	testErrors(t, `extern fn printf(format: string, ...) -> i32; printf(1);`, "1:54: error[T0006]: Argument [1] of [printf] must be of type [string] but is [int].")
+	testErrors(t, `extern fn printf(format: string, ...) -> i32; let p = printf;`, "1:55: error[T0010]: Variadic function [printf] cannot be used as a value.")
+	testErrors(t, "extern fn abort(); abort(); let f = abort; if false { f(); }")
+	testErrors(t, "extern fn abort(); let a = abort();", "1:28: error[T0034]: [abort()] does not return a value.")
+	testErrors(t, "extern fn abort(); fn f() { return abort(); }", "1:36: error[T0034]: [abort()] does not return a value.")
+	testErrors(t, "extern fn abort(); let f = abort; 1 + f();", "1:39: error[T0034]: [f()] does not return a value.")
+	testErrors(t, `extern fn putchar(c: i32) -> i32; let p = putchar; p(true);`, "1:54: error[T0006]: Argument [1] of [p] must be of type [i32] but is [bool].")
+}
+
+func TestModules(t *testing.T) {
+	geo := "pub struct P { x: i32 } pub enum E { A(x: u8) } pub fn origin() -> P { return P { x: 0 }; } pub fn id(x) { return x; }"
+	testModuleErrors(t, geo, `import "geo"; let p: geo.P = geo.origin(); let e: geo.E = geo.A(u8(1)); let b: bool = geo.id(true);`)
+	testModuleErrors(t, geo, `import "geo"; let a = geo;`, "1:23: error[T0010]: Module [geo] cannot be used as a value.")
+	testModuleErrors(t, geo, `import "geo"; let p: geo.P = 1;`, "1:19: error[T0008]: Cannot assign [int] to [p] of type [P].")
+	testModuleErrors(t, geo, `import "geo"; geo.A(true);`, "1:21: error[T0006]: Argument [1] of [geo.A] must be of type [u8] but is [bool].")
+}
+
+// testModuleErrors checks a program with the main module and the module
+// geo.
+func testModuleErrors(t *testing.T, geo string, input string, expected ...string) {
+	t.Helper()
+	mods := []*parser.Program{}
+	for i, src := range []string{geo, input} {
+		p := parser.NewParser(lexer.NewLexer(src))
+		mod := p.Parse()
+		if p.HasErrors() {
+			t.Fatalf("Unexpected parser errors %v.", p.Errors())
+		}
+		if i == 0 {
+			mod.Path = "geo"
+		}
+		mods = append(mods, mod)
+	}
+	diags := diag.NewList()
+	names := resolve.NewResolver(diags).ResolveModules(mods)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected resolver errors %v.", diags.Items())
+	}
+	prog := parser.NewProgram()
+	for _, mod := range mods {
+		prog.Statements = append(prog.Statements, mod.Statements...)
+	}
+	types.NewChecker(diags).Check(prog, names)
+	actual := diags.Items()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
+
+func check(t *testing.T, input string) (*parser.Program, *types.Info, *diag.List) {
+	t.Helper()
+	l := lexer.NewLexer(input)
+	p := parser.NewParser(l)
+	prog := p.Parse()
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parser errors %v.", p.Errors())
+	}
+	diags := diag.NewList()
+	names := resolve.NewResolver(diags).Resolve(prog)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected resolver errors %v.", diags.Items())
+	}
+	info := types.NewChecker(diags).Check(prog, names)
+	return prog, info, diags
+}
+
+// testType checks the type of the last expression statement.
+func testType(t *testing.T, input string, expected string) {
+	t.Helper()
+	prog, info, diags := check(t, input)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", diags.Items())
+	}
+	var last parser.Expression
+	for _, s := range prog.Statements {
+		if e, ok := s.(*parser.ExpressionStatement); ok {
+			last = e.Value
+		}
+	}
+	if actual := info.TypeOf(last).String(); actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
+
+// testSignature checks the signature of the first function.
+func testSignature(t *testing.T, input string, expected string) {
+	t.Helper()
+	prog, info, diags := check(t, input)
+	if diags.HasErrors() {
+		t.Fatalf("Unexpected errors %v.", diags.Items())
+	}
+	fun := prog.Statements[0].(*parser.FunDefStatement)
+	if actual := info.Funcs[fun].String(); actual != expected {
+		t.Errorf("Expected [%s] but got [%s].", expected, actual)
+	}
+}
+
+func testErrors(t *testing.T, input string, expected ...string) {
+	t.Helper()
+	_, _, diags := check(t, input)
+	actual := diags.Items()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] errors but got %v.", len(expected), actual)
+	}
+	for i, e := range expected {
+		if a := actual[i].String(); a != e {
+			t.Errorf("Expected [%s] but got [%s].", e, a)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/mhoertnagl/donkey/diag"
+	"github.com/mhoertnagl/donkey/parser"
+	"github.com/mhoertnagl/donkey/resolve"
+)
+
+// The checker infers the types of variables, parameters and results
+// without a type annotation by unification. Every unknown type is a fresh
+// type variable. The uses of a value constrain its type variable to other
+// types. Unification binds the variable to the type it has to be equal to.
+// The location of the constraint is recorded so that a conflict with a
+// later constraint can be reported at both locations.
+//
+// Top-level functions are generalized after their bodies have been
+// checked. The type variables of their signatures that remain unbound
+// become type parameters and every use of the function instantiates them
+// with fresh type variables:
+//
+//	fn id(x) { return x; }  =>  fn('a) -> 'a
+//
+// Mutually recursive functions are checked and generalized together. Uses
+// of a function within its own group are not instantiated. Type variables
+// that are still unbound after checking the program default to int.
+
+// fresh returns a new unbound type variable.
+func (c *Checker) fresh() *Var {
+	return &Var{}
+}
+
+// unify makes the types a and b identical by binding type variables. The
+// span is the location of the constraint. unify returns false if the
+// types cannot be made identical. Invalid types unify with any type.
+func (c *Checker) unify(a, b Type, at diag.Location) bool {
+	a, b = Resolve(a), Resolve(b)
+	if a == b {
+		return true
+	}
+	// Unknown types of invalid values are invalid to avoid follow-up errors.
+	if a == Invalid || b == Invalid {
+		if v, ok := a.(*Var); ok {
+			v.bound = Invalid
+		}
+		if v, ok := b.(*Var); ok {
+			v.bound = Invalid
+		}
+		return true
+	}
+	if v, ok := a.(*Var); ok {
+		return c.bind(v, b, at)
+	}
+	if v, ok := b.(*Var); ok {
+		return c.bind(v, a, at)
+	}
+	switch a := a.(type) {
+	case *Array:
+		b, ok := b.(*Array)
+		return ok && c.unify(a.Elem, b.Elem, at)
+	case *Func:
+		b, ok := b.(*Func)
+		if !ok || len(a.Params) != len(b.Params) || a.Variadic != b.Variadic {
+			return false
+		}
+		for i := range a.Params {
+			if !c.unify(a.Params[i], b.Params[i], at) {
+				return false
+			}
+		}
+		return c.unify(a.Result, b.Result, at)
+	}
+	return false
+}
+
+// bind binds the type variable to the type unless the type contains the
+// variable itself.
+func (c *Checker) bind(v *Var, t Type, at diag.Location) bool {
+	for _, w := range freeVars(t, nil) {
+		if w == v {
+			return false
+		}
+	}
+	v.bound = t
+	c.origins[v] = at
+	return true
+}
+
+// explain attaches a note to the diagnostic for every type that has been
+// inferred from a constraint at another location.
+func (c *Checker) explain(d *diag.Diagnostic, types ...Type) {
+	for _, t := range types {
+		// The last variable of a chain of bound variables has been bound
+		// to the type itself.
+		var origin *diag.Location
+		for v, ok := t.(*Var); ok && v.bound != nil; v, ok = v.bound.(*Var) {
+			if loc, ok := c.origins[v]; ok {
+				origin = &loc
+			}
+		}
+		if origin != nil && *origin != (diag.Location{File: d.File, Span: d.Span}) {
+			d.AddNote(origin, "Type [%s] is inferred here.", t)
+		}
+	}
+}
+
+// instantiate returns the type of the generic function with fresh type
+// variables for its type parameters. The types of the type parameters are
+// recorded for the use of the function.
+func (c *Checker) instantiate(id *parser.Identifier, s *Scheme) *Func {
+	m := make(map[*Var]Type)
+	args := make([]Type, len(s.Vars))
+	for i, v := range s.Vars {
+		args[i] = &Var{name: v.name}
+		m[v] = args[i]
+	}
+	c.info.Instances[id] = args
+	return Subst(s.Type, m).(*Func)
+}
+
+// generalize turns the signatures of a group of top-level functions into
+// schemes. The unbound type variables of the signatures become type
+// parameters unless they occur in the type of a variable that is declared
+// outside of the group.
+func (c *Checker) generalize(funs []*parser.FunDefStatement) {
+	local := c.declaredIn(funs)
+	env := []*Var{}
+	for obj, typ := range c.vars {
+		if !local[obj] {
+			env = freeVars(typ, env)
+		}
+	}
+	free := map[*Var]bool{}
+	for _, v := range env {
+		free[v] = true
+	}
+	count := 0
+	for _, n := range funs {
+		sig := c.info.Funcs[n]
+		params := []*Var{}
+		for _, v := range freeVars(sig, nil) {
+			if free[v] {
+				continue
+			}
+			if !v.generic {
+				v.generic = true
+				v.name = varName(count)
+				count++
+			}
+			params = append(params, v)
+		}
+		if len(params) > 0 {
+			c.info.Schemes[n] = &Scheme{Vars: params, Type: sig}
+		}
+	}
+}
+
+// declaredIn returns the objects declared by the functions including
+// their parameters.
+func (c *Checker) declaredIn(funs []*parser.FunDefStatement) map[*resolve.Object]bool {
+	objs := map[*resolve.Object]bool{}
+	for _, n := range funs {
+		parser.Inspect(n, func(n parser.Node) bool {
+			if id, ok := n.(*parser.Identifier); ok {
+				if obj := c.names.Defs[id]; obj != nil {
+					objs[obj] = true
+				}
+			}
+			return true
+		})
+	}
+	return objs
+}
+
+// groups partitions the top-level functions of the program into groups of
+// mutually recursive functions. A function belongs to the group of every
+// function that it refers to and that refers back to it. The functions of
+// a group are in the order of their definition.
+func (c *Checker) groups(prog *parser.Program) {
+	funs := []*parser.FunDefStatement{}
+	topLevel := map[*parser.FunDefStatement]bool{}
+	for _, s := range prog.Statements {
+		if n, ok := s.(*parser.FunDefStatement); ok {
+			funs = append(funs, n)
+			topLevel[n] = true
+		}
+	}
+	// The top-level functions each function refers to.
+	refs := map[*parser.FunDefStatement][]*parser.FunDefStatement{}
+	for _, n := range funs {
+		if n.Body == nil {
+			continue
+		}
+		parser.Inspect(n.Body, func(m parser.Node) bool {
+			if id, ok := m.(*parser.Identifier); ok {
+				if obj := c.names.Uses[id]; obj != nil && obj.Kind == resolve.Func && topLevel[obj.Fun] {
+					refs[n] = append(refs[n], obj.Fun)
+				}
+			}
+			return true
+		})
+	}
+
+	// Tarjan's algorithm for strongly connected components.
+	index := map[*parser.FunDefStatement]int{}
+	low := map[*parser.FunDefStatement]int{}
+	onStack := map[*parser.FunDefStatement]bool{}
+	stack := []*parser.FunDefStatement{}
+	var visit func(n *parser.FunDefStatement)
+	visit = func(n *parser.FunDefStatement) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range refs[n] {
+			if _, ok := index[m]; !ok {
+				visit(m)
+				if low[m] < low[n] {
+					low[n] = low[m]
+				}
+			} else if onStack[m] && index[m] < low[n] {
+				low[n] = index[m]
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		group := []*parser.FunDefStatement{}
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			group = append(group, m)
+			if m == n {
+				break
+			}
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].Token.Offset < group[j].Token.Offset })
+		for _, m := range group {
+			c.group[m] = group
+		}
+	}
+	for _, n := range funs {
+		if _, ok := index[n]; !ok {
+			visit(n)
+		}
+	}
+}
+
+// finish replaces the type variables of the results by the types they are
+// bound to. Type variables that are neither bound nor type parameters of a
+// generic function default to int.
+func (c *Checker) finish() {
+	for n, t := range c.info.Types {
+		c.info.Types[n] = complete(t)
+	}
+	for id, t := range c.info.Defs {
+		c.info.Defs[id] = complete(t)
+	}
+	for n, sig := range c.info.Funcs {
+		c.info.Funcs[n] = complete(sig).(*Func)
+		if s, ok := c.info.Schemes[n]; ok {
+			s.Type = c.info.Funcs[n]
+		}
+	}
+	for id, args := range c.info.Instances {
+		for i, t := range args {
+			args[i] = complete(t)
+		}
+		c.info.Instances[id] = args
+	}
+}
+
+// complete returns the type with all bound type variables replaced. Type
+// variables that are not type parameters are bound to int.
+func complete(t Type) Type {
+	switch t := Resolve(t).(type) {
+	case *Var:
+		if !t.generic {
+			t.bound = Int
+			return Int
+		}
+		return t
+	case *Array:
+		return &Array{Elem: complete(t.Elem)}
+	case *Func:
+		sig := &Func{Params: make([]Type, len(t.Params)), Result: complete(t.Result), Variadic: t.Variadic}
+		for i, p := range t.Params {
+			sig.Params[i] = complete(p)
+		}
+		return sig
+	case nil:
+		return nil
+	default:
+		return t
+	}
+}
@@ -0,0 +1,308 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/mhoertnagl/donkey/utils"
+)
+
+type Type interface {
+	String() string
+}
+
+// Basic is a predeclared type. Integer and floating-point types have a size
+// in bits. Integer types are either signed or unsigned.
+type Basic struct {
+	name     string
+	bits     int
+	unsigned bool
+	float    bool
+}
+
+func (t *Basic) String() string { return t.name }
+
+// Bits returns the size of an integer or floating-point type in bits and 0
+// for other types.
+func (t *Basic) Bits() int { return t.bits }
+
+// IsUnsigned returns true iff the type is an unsigned integer type.
+func (t *Basic) IsUnsigned() bool { return t.unsigned }
+
+var (
+	// Invalid is the type of expressions that contain a type error. No
+	// further errors are reported for expressions of invalid type.
+	Invalid = &Basic{name: "invalid"}
+	// Int is the type of integers without a type annotation. It is the
+	// same type as i64.
+	Int    = &Basic{name: "int", bits: 64}
+	Bool   = &Basic{name: "bool"}
+	String = &Basic{name: "string"}
+	// Void is the result type of extern functions without a result type.
+	// Their calls have no value and can only be used as statements.
+	Void = &Basic{name: "void"}
+
+	I8   = &Basic{name: "i8", bits: 8}
+	I16  = &Basic{name: "i16", bits: 16}
+	I32  = &Basic{name: "i32", bits: 32}
+	I128 = &Basic{name: "i128", bits: 128}
+	U8   = &Basic{name: "u8", bits: 8, unsigned: true}
+	U16  = &Basic{name: "u16", bits: 16, unsigned: true}
+	U32  = &Basic{name: "u32", bits: 32, unsigned: true}
+	U64  = &Basic{name: "u64", bits: 64, unsigned: true}
+	U128 = &Basic{name: "u128", bits: 128, unsigned: true}
+
+	// Floating-point literals are of type f64 unless they are used where a
+	// value of type f32 is expected.
+	F32 = &Basic{name: "f32", bits: 32, float: true}
+	F64 = &Basic{name: "f64", bits: 64, float: true}
+)
+
+// Predeclared maps the names of the predeclared types to the types.
+var Predeclared = map[string]Type{
+	"int":    Int,
+	"bool":   Bool,
+	"string": String,
+	"i8":     I8,
+	"i16":    I16,
+	"i32":    I32,
+	"i64":    Int,
+	"i128":   I128,
+	"u8":     U8,
+	"u16":    U16,
+	"u32":    U32,
+	"u64":    U64,
+	"u128":   U128,
+	"f32":    F32,
+	"f64":    F64,
+}
+
+// IsInteger returns true iff the type is an integer type.
+func IsInteger(t Type) bool {
+	b, ok := Resolve(t).(*Basic)
+	return ok && b.bits > 0 && !b.float
+}
+
+// IsFloat returns true iff the type is a floating-point type.
+func IsFloat(t Type) bool {
+	b, ok := Resolve(t).(*Basic)
+	return ok && b.float
+}
+
+// IsNumeric returns true iff the type is an integer or a floating-point
+// type.
+func IsNumeric(t Type) bool {
+	return IsInteger(t) || IsFloat(t)
+}
+
+// IsUnsigned returns true iff the type is an unsigned integer type.
+func IsUnsigned(t Type) bool {
+	b, ok := Resolve(t).(*Basic)
+	return ok && b.unsigned
+}
+
+// Var is a type variable. Type inference binds type variables to the
+// types they stand for. The type variables of a generic function that
+// remain unbound are its type parameters.
+type Var struct {
+	name    string
+	bound   Type
+	generic bool
+}
+
+func (t *Var) String() string {
+	if t.bound != nil {
+		return t.bound.String()
+	}
+	if t.name != "" {
+		return "'" + t.name
+	}
+	return "'_"
+}
+
+// Scheme is the polymorphic type of a generic function. Every use of the
+// function instantiates the type variables with fresh types.
+type Scheme struct {
+	Vars []*Var
+	Type *Func
+}
+
+func (s *Scheme) String() string { return s.Type.String() }
+
+// Resolve returns the type the type variable is bound to. Other types are
+// returned as is.
+func Resolve(t Type) Type {
+	for {
+		v, ok := t.(*Var)
+		if !ok || v.bound == nil {
+			return t
+		}
+		t = v.bound
+	}
+}
+
+// Subst replaces the type variables of the map in the type by their types.
+// Bound type variables are replaced by the types they are bound to.
+func Subst(t Type, m map[*Var]Type) Type {
+	switch t := Resolve(t).(type) {
+	case *Var:
+		if typ, ok := m[t]; ok {
+			return typ
+		}
+		return t
+	case *Array:
+		return &Array{Elem: Subst(t.Elem, m)}
+	case *Func:
+		sig := &Func{Params: make([]Type, len(t.Params)), Result: Subst(t.Result, m), Variadic: t.Variadic}
+		for i, p := range t.Params {
+			sig.Params[i] = Subst(p, m)
+		}
+		return sig
+	case nil:
+		return nil
+	default:
+		return t
+	}
+}
+
+// freeVars appends the unbound type variables of the type that are not
+// part of the list yet.
+func freeVars(t Type, vars []*Var) []*Var {
+	switch t := Resolve(t).(type) {
+	case *Var:
+		for _, v := range vars {
+			if v == t {
+				return vars
+			}
+		}
+		return append(vars, t)
+	case *Array:
+		return freeVars(t.Elem, vars)
+	case *Func:
+		for _, p := range t.Params {
+			vars = freeVars(p, vars)
+		}
+		if t.Result != nil {
+			vars = freeVars(t.Result, vars)
+		}
+	}
+	return vars
+}
+
+// varName returns the name of the i-th type parameter: a, b, ..., z, a1,
+// b1 and so on.
+func varName(i int) string {
+	name := string(rune('a' + i%26))
+	if i >= 26 {
+		name += fmt.Sprint(i / 26)
+	}
+	return name
+}
+
+// Func is the type of a function. Variadic functions are extern functions
+// that accept additional arguments after their parameters.
+type Func struct {
+	Params   []Type
+	Result   Type
+	Variadic bool
+}
+
+func (t *Func) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("fn(")
+	params := utils.Map(t.Params, Type.String)
+	if t.Variadic {
+		params = append(params, "...")
+	}
+	buf.WriteString(strings.Join(params, ", "))
+	buf.WriteString(")")
+	if t.Result != nil {
+		buf.WriteString(" -> ")
+		buf.WriteString(t.Result.String())
+	}
+	return buf.String()
+}
+
+// Array is a sequence of values of the element type.
+type Array struct {
+	Elem Type
+}
+
+func (t *Array) String() string { return "[" + t.Elem.String() + "]" }
+
+// Struct is a record of named fields. Every struct definition declares a
+// distinct type.
+type Struct struct {
+	Name   string
+	Fields []*Field
+}
+
+// Field is a named member of a struct.
+type Field struct {
+	Name string
+	Type Type
+}
+
+func (t *Struct) String() string { return t.Name }
+
+// FieldIndex returns the index of the field with the name or -1 if the
+// struct has no such field.
+func (t *Struct) FieldIndex(name string) int {
+	for i, f := range t.Fields {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Enum is a tagged union of variants. Every enum definition declares a
+// distinct type.
+type Enum struct {
+	Name     string
+	Variants []*Variant
+}
+
+// Variant is an alternative of an enum with the types of its fields.
+type Variant struct {
+	Name   string
+	Fields []Type
+}
+
+func (t *Enum) String() string { return t.Name }
+
+// VariantIndex returns the index of the variant with the name or -1 if the
+// enum has no such variant.
+func (t *Enum) VariantIndex(name string) int {
+	for i, v := range t.Variants {
+		if v.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Identical returns true iff both types are the same.
+func Identical(a, b Type) bool {
+	a, b = Resolve(a), Resolve(b)
+	switch a := a.(type) {
+	case *Basic:
+		return a == b
+	case *Array:
+		b, ok := b.(*Array)
+		return ok && Identical(a.Elem, b.Elem)
+	case *Func:
+		b, ok := b.(*Func)
+		if !ok || len(a.Params) != len(b.Params) || !Identical(a.Result, b.Result) {
+			return false
+		}
+		for i := range a.Params {
+			if !Identical(a.Params[i], b.Params[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
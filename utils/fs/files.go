@@ -2,6 +2,7 @@ package fs
 
 import (
 	"io/fs"
+	"os"
 	"path/filepath"
 )
 
@@ -18,3 +19,11 @@ func FindFilesWithExtension(root, ext string) []string {
 	})
 	return files
 }
+
+// FindFile returns the file with the slash-separated path and the extension
+// relative to the root. It returns false if there is no such file.
+func FindFile(root, path, ext string) (string, bool) {
+	file := filepath.Join(root, filepath.FromSlash(path)+ext)
+	info, err := os.Stat(file)
+	return file, err == nil && info.Mode().IsRegular()
+}